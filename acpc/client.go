@@ -0,0 +1,119 @@
+package acpc
+
+import (
+	"bufio"
+	"io"
+	"net"
+
+	"github.com/openpoker-dev/contrib/agent"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Client ACPC 客户端，连接到庄家参加比赛
+	Client struct {
+		Game Game
+		rw   io.ReadWriter
+		conn conn
+		last string // 最近收到的 match state
+	}
+)
+
+// Dial 连接到 addr 上的庄家
+func Dial(addr string, g Game) (*Client, error) {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(c, g)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// NewClient 在已经建立的连接上发送版本号
+func NewClient(rw io.ReadWriter, g Game) (*Client, error) {
+	c := &Client{Game: g, rw: rw, conn: conn{w: rw, r: bufio.NewReader(rw)}}
+	if err := c.conn.write(version); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Next 读取下一个 match state，庄家关闭连接时返回 io.EOF
+func (c *Client) Next() (*MatchState, error) {
+	line, err := c.conn.read()
+	if err != nil {
+		return nil, err
+	}
+	ms, err := ParseMatchState(line)
+	if err != nil {
+		return nil, err
+	}
+	c.last = line
+	return ms, nil
+}
+
+// Respond 对最近收到的 match state 回复行动
+func (c *Client) Respond(a Action) error {
+	return c.conn.write(c.last + ":" + a.String())
+}
+
+// Play 由机器人打完整场比赛，轮到自己时行动，每局结束时通知机器人
+func (c *Client) Play(a agent.Agent) error {
+	cfg, err := c.Game.Config()
+	if err != nil {
+		return err
+	}
+	for {
+		ms, err := c.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		h, err := ms.Replay(c.Game)
+		if err != nil {
+			return err
+		}
+
+		seat := c.Game.Seat(ms.Position)
+		if _, done := h.Result(); done {
+			a.Observe(agent.Hide(h.History(), seat))
+			continue
+		}
+		if toAct, _ := h.ToAct(); toAct != seat {
+			continue
+		}
+		action := a.Act(agent.NewObservation(h, seat, cfg.BigBlind))
+		if err := c.Respond(c.convert(h, seat, action)); err != nil {
+			return err
+		}
+	}
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	if closer, ok := c.rw.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// convert 引擎行动对应的 ACPC 行动，无限注的加注额换算成本局总共投入的筹码
+func (c *Client) convert(h *table.Hand, seat int, a table.Action) Action {
+	switch a.Type {
+	case table.ActionFold:
+		return Action{Type: ActionFold}
+	case table.ActionBet, table.ActionRaise:
+		if c.Game.Betting == BettingLimit {
+			return Action{Type: ActionRaise}
+		}
+		s := h.Seats()[seat-1]
+		return Action{Type: ActionRaise, Size: a.Amount + s.Total - s.Committed}
+	}
+	return Action{Type: ActionCall}
+}
@@ -0,0 +1,68 @@
+package acpc
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/agent"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/stretchr/testify/assert"
+)
+
+// spy 记录观察到的状态和牌局历史，总是跟注
+type spy struct {
+	observations []agent.Observation
+	records      []*history.Hand
+}
+
+func (s *spy) Act(o agent.Observation) table.Action {
+	s.observations = append(s.observations, o)
+	return agent.CallingStation(o)
+}
+
+func (s *spy) Observe(record *history.Hand) {
+	s.records = append(s.records, record)
+}
+
+func TestClientConvert(t *testing.T) {
+	ms, err := ParseMatchState("MATCHSTATE:0:0:r300c/:AsKd|/2c3c4c")
+	assert.Nil(t, err)
+	h, err := ms.Replay(HoldemNoLimit2P)
+	assert.Nil(t, err)
+
+	c := &Client{Game: HoldemNoLimit2P}
+	assert.Equal(t, Action{Type: ActionRaise, Size: 700}, c.convert(h, 1, table.Action{Seat: 1, Type: table.ActionBet, Amount: 400}))
+	assert.Equal(t, Action{Type: ActionCall}, c.convert(h, 1, table.Action{Seat: 1, Type: table.ActionCheck}))
+	assert.Equal(t, Action{Type: ActionFold}, c.convert(h, 1, table.Action{Seat: 1, Type: table.ActionFold}))
+
+	c.Game = HoldemLimit2P
+	assert.Equal(t, Action{Type: ActionRaise}, c.convert(h, 1, table.Action{Seat: 1, Type: table.ActionBet, Amount: 400}))
+}
+
+func TestClientObservation(t *testing.T) {
+	s := &spy{}
+	match(t, &Dealer{Game: HoldemNoLimit2P, Hands: 10, Seed: 5}, s, agent.Maniac)
+	assert.Len(t, s.records, 10)
+	assert.NotEmpty(t, s.observations)
+	for _, o := range s.observations {
+		assert.Len(t, o.Hole, 2)
+		assert.Equal(t, int64(100), o.BigBlind)
+		for _, seat := range o.Seats {
+			if seat.Seat != o.Seat {
+				assert.Nil(t, seat.Hole)
+			}
+		}
+	}
+	// 没有摊牌时看不到对手的手牌
+	for _, record := range s.records {
+		if len(record.Showdown) == 0 {
+			hidden := 0
+			for _, p := range record.Players {
+				if p.Cards == nil {
+					hidden++
+				}
+			}
+			assert.Equal(t, 1, hidden)
+		}
+	}
+}
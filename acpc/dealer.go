@@ -0,0 +1,240 @@
+package acpc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Dealer ACPC 庄家，在本地牌局引擎上主持一场比赛
+	// 第 h 局中第 i 个玩家坐在位置 (i+h)%n，每局开始时筹码重置
+	Dealer struct {
+		Game  Game
+		Hands int       // 比赛的局数
+		Seed  int64     // 发牌的随机种子，第 h 局使用 Seed+h
+		Names []string  // 玩家名字，用于比赛日志，为空时使用 p1、p2……
+		Log   io.Writer // 比赛日志，每局一行 STATE，为空时不记录
+	}
+
+	// Result 比赛结果
+	Result struct {
+		Hands int
+		Net   []int64 // 每个玩家净赢的筹码，与连接的顺序相同
+	}
+
+	// conn 一个玩家的连接
+	conn struct {
+		w io.Writer
+		r *bufio.Reader
+	}
+)
+
+// Serve 等待游戏定义的人数连接到 ln 之后开始比赛，比赛结束后关闭所有连接
+func (d *Dealer) Serve(ln net.Listener) (*Result, error) {
+	conns := make([]io.ReadWriter, 0, d.Game.Players)
+	defer func() {
+		for _, c := range conns {
+			c.(io.Closer).Close()
+		}
+	}()
+	for len(conns) < d.Game.Players {
+		c, err := ln.Accept()
+		if err != nil {
+			return nil, err
+		}
+		conns = append(conns, c)
+	}
+	return d.Run(conns...)
+}
+
+// Run 在已经建立的连接上进行比赛，每个连接先发送版本号
+// 连接断开或者回复不符合协议时中止比赛，不合法的行动按 ACPC 庄家的规则修正
+func (d *Dealer) Run(players ...io.ReadWriter) (*Result, error) {
+	cfg, err := d.Game.Config()
+	if err != nil {
+		return nil, err
+	}
+	n := d.Game.Players
+	if len(players) != n {
+		return nil, fmt.Errorf("%w: %d players for a %d player game", ErrProtocol, len(players), n)
+	}
+	names := d.Names
+	if len(names) != n {
+		names = make([]string, n)
+		for i := range names {
+			names[i] = "p" + strconv.Itoa(i+1)
+		}
+	}
+
+	conns := make([]conn, n)
+	for i, p := range players {
+		conns[i] = conn{w: p, r: bufio.NewReader(p)}
+		line, err := conns[i].read()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "VERSION:2.") {
+			return nil, fmt.Errorf("%w: unsupported version %q", ErrProtocol, line)
+		}
+	}
+
+	result := &Result{Hands: d.Hands, Net: make([]int64, n)}
+	for hand := 0; hand < d.Hands; hand++ {
+		// order[p] 为位置 p 上的玩家
+		order := make([]int, n)
+		for i := range order {
+			order[(i+hand)%n] = i
+		}
+		values, err := d.play(cfg, hand, order, names, conns)
+		if err != nil {
+			return nil, fmt.Errorf("hand %d: %w", hand, err)
+		}
+		for p, v := range values {
+			result.Net[order[p]] += v
+		}
+	}
+	return result, nil
+}
+
+// play 打一局，返回每个位置净赢的筹码
+func (d *Dealer) play(cfg table.Config, hand int, order []int, names []string, conns []conn) ([]int64, error) {
+	n := d.Game.Players
+	players := make([]table.Player, n)
+	for p := range players {
+		players[p] = table.Player{Seat: d.Game.Seat(p), Name: names[order[p]], Stack: d.Game.Stack}
+	}
+	deck := card.NewSeededDeck(d.Seed + int64(hand))
+	deck.Shuffle()
+	h, err := table.NewHand(cfg, players, d.Game.Button(), deck)
+	if err != nil {
+		return nil, err
+	}
+
+	betting := [][]Action{nil}
+	sent := make([]string, n)
+	for {
+		for p := range sent {
+			sent[p] = state(h, betting, p, hand).String()
+			if err := conns[order[p]].write(sent[p]); err != nil {
+				return nil, err
+			}
+		}
+		seat, ok := h.ToAct()
+		if !ok {
+			break
+		}
+
+		p := seat - 1
+		line, err := conns[order[p]].read()
+		if err != nil {
+			return nil, err
+		}
+		replied, a, err := ParseResponse(line)
+		if err != nil {
+			return nil, err
+		}
+		if replied != sent[p] {
+			return nil, fmt.Errorf("%w: response %q to a stale state", ErrProtocol, line)
+		}
+		round := int(h.Street())
+		if err := h.Apply(engineAction(h, seat, a)); err != nil {
+			return nil, err
+		}
+		actions := h.Actions()
+		betting[round] = append(betting[round], acpcAction(actions[len(actions)-1], h.Seats()[p].Total, d.Game.Betting))
+		for len(betting) < boardRounds(len(h.Board()))+1 {
+			betting = append(betting, nil)
+		}
+	}
+
+	values := make([]int64, n)
+	for p, s := range h.Seats() {
+		values[p] = s.Stack - d.Game.Stack
+	}
+	if d.Log != nil {
+		d.log(h, betting, hand, values, order, names)
+	}
+	return values, nil
+}
+
+// log 按 ACPC 日志格式记录一局：STATE:局号:行动:所有手牌/公共牌:输赢:玩家
+func (d *Dealer) log(h *table.Hand, betting [][]Action, hand int, values []int64, order []int, names []string) {
+	ms := state(h, betting, 0, hand)
+	for p, s := range h.Seats() {
+		ms.Holes[p] = s.Hole
+	}
+	line := strings.TrimPrefix(ms.String(), fmt.Sprintf("%s:0:", matchStatePrefix))
+	scores := make([]string, len(values))
+	seated := make([]string, len(values))
+	for p, v := range values {
+		scores[p] = strconv.FormatInt(v, 10)
+		seated[p] = names[order[p]]
+	}
+	fmt.Fprintf(d.Log, "STATE:%s:%s:%s\n", line, strings.Join(scores, "|"), strings.Join(seated, "|"))
+}
+
+// state 位置 p 看到的状态，牌局结束时可以看到摊牌的手牌
+func state(h *table.Hand, betting [][]Action, p int, hand int) *MatchState {
+	ms := &MatchState{Position: p, Hand: hand, Betting: betting}
+	shown := make(map[int]bool)
+	if result, done := h.Result(); done {
+		for seat := range result.Showdown {
+			shown[seat] = true
+		}
+	}
+	for q, s := range h.Seats() {
+		var hole []card.Card
+		if q == p || shown[s.Seat] {
+			hole = s.Hole
+		}
+		ms.Holes = append(ms.Holes, hole)
+	}
+	board := h.Board()
+	ms.Board = [][]card.Card{nil}
+	for round, end := range []int{3, 4, 5}[:len(betting)-1] {
+		ms.Board = append(ms.Board, board[[]int{0, 3, 4}[round]:end])
+	}
+	return ms
+}
+
+// boardRounds 公共牌对应的已经开始的翻牌后轮数
+func boardRounds(cards int) int {
+	switch {
+	case cards >= 5:
+		return 3
+	case cards == 4:
+		return 2
+	case cards >= 3:
+		return 1
+	}
+	return 0
+}
+
+func (c conn) write(line string) error {
+	_, err := io.WriteString(c.w, line+"\r\n")
+	return err
+}
+
+// read 读取一行，忽略空行和 # 或 ; 开头的注释
+func (c conn) read() (string, error) {
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line != "" && line[0] != '#' && line[0] != ';' {
+			return line, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
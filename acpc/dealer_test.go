@@ -0,0 +1,164 @@
+package acpc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+// match 让机器人通过管道连接到庄家打一场比赛
+func match(t *testing.T, d *Dealer, agents ...agent.Agent) *Result {
+	var wg sync.WaitGroup
+	conns := make([]io.ReadWriter, len(agents))
+	for i, a := range agents {
+		server, client := net.Pipe()
+		conns[i] = server
+		wg.Add(1)
+		go func(a agent.Agent) {
+			defer wg.Done()
+			c, err := NewClient(client, d.Game)
+			assert.Nil(t, err)
+			assert.Nil(t, c.Play(a))
+			c.Close()
+		}(a)
+	}
+	result, err := d.Run(conns...)
+	assert.Nil(t, err)
+	for _, c := range conns {
+		c.(net.Conn).Close()
+	}
+	wg.Wait()
+	return result
+}
+
+func TestDealerMatch(t *testing.T) {
+	var log bytes.Buffer
+	d := &Dealer{Game: HoldemNoLimit2P, Hands: 50, Seed: 1, Names: []string{"station", "random"}, Log: &log}
+	result := match(t, d, agent.CallingStation, agent.NewRandom(2))
+	assert.Equal(t, 50, result.Hands)
+	assert.Equal(t, int64(0), result.Net[0]+result.Net[1])
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	assert.Len(t, lines, 50)
+	for i, line := range lines {
+		assert.True(t, strings.HasPrefix(line, "STATE:"), line)
+		if i%2 == 0 {
+			assert.True(t, strings.HasSuffix(line, ":station|random"), line)
+		} else {
+			assert.True(t, strings.HasSuffix(line, ":random|station"), line)
+		}
+	}
+
+	// 种子相同时结果相同
+	log.Reset()
+	again := match(t, &Dealer{Game: HoldemNoLimit2P, Hands: 50, Seed: 1}, agent.CallingStation, agent.NewRandom(2))
+	assert.Equal(t, result, again)
+}
+
+func TestDealerThreePlayers(t *testing.T) {
+	// 每个机器人收到每一局的结果
+	s := &spy{}
+	d := &Dealer{Game: HoldemLimit3P, Hands: 30, Seed: 9}
+	result := match(t, d, agent.Maniac, agent.NewRandom(1), s)
+	assert.Equal(t, int64(0), result.Net[0]+result.Net[1]+result.Net[2])
+	assert.Len(t, s.records, 30)
+	for _, record := range s.records {
+		assert.Nil(t, record.Validate())
+	}
+}
+
+func TestDealerRawProtocol(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	var log bytes.Buffer
+	d := &Dealer{Game: HoldemLimit2P, Hands: 2, Seed: 3, Log: &log}
+	done := make(chan *Result)
+	go func() {
+		result, err := d.Serve(ln)
+		assert.Nil(t, err)
+		done <- result
+	}()
+
+	// 第一个玩家是手写的 ACPC 机器人：只跟注，不理会注释
+	raw, err := net.Dial("tcp", ln.Addr().String())
+	assert.Nil(t, err)
+	defer raw.Close()
+	_, err = io.WriteString(raw, "# comment\r\nVERSION:2.0.0\r\n")
+	assert.Nil(t, err)
+
+	c, err := Dial(ln.Addr().String(), HoldemLimit2P)
+	assert.Nil(t, err)
+	go func() {
+		assert.Nil(t, c.Play(agent.CallingStation))
+	}()
+
+	var states []string
+	r := bufio.NewReader(raw)
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		assert.True(t, strings.HasSuffix(line, "\r\n"))
+		line = strings.TrimSuffix(line, "\r\n")
+		states = append(states, line)
+
+		ms, err := ParseMatchState(line)
+		assert.Nil(t, err)
+		h, err := ms.Replay(HoldemLimit2P)
+		assert.Nil(t, err)
+		if seat, ok := h.ToAct(); ok && seat == HoldemLimit2P.Seat(ms.Position) {
+			_, err = io.WriteString(raw, line+":c\r\n")
+			assert.Nil(t, err)
+		}
+	}
+	result := <-done
+
+	// 第一局在位置0（大盲），第二局在位置1（庄家）
+	assert.Regexp(t, `^MATCHSTATE:0:0::[2-9TJQKA][shdc][2-9TJQKA][shdc]\|$`, states[0])
+	assert.Regexp(t, `^MATCHSTATE:0:0:cc/cc/cc/cc:\w{4}\|\w{4}/\w{6}/\w{2}/\w{2}$`, states[8])
+	assert.Regexp(t, `^MATCHSTATE:1:1::\|\w{4}$`, states[9])
+	assert.Len(t, states, 18)
+	assert.Equal(t, int64(0), result.Net[0]+result.Net[1])
+	assert.Equal(t, 2, strings.Count(log.String(), "STATE:"))
+}
+
+func TestDealerProtocolErrors(t *testing.T) {
+	d := &Dealer{Game: HoldemNoLimit2P, Hands: 1}
+	_, err := d.Run(&bytes.Buffer{})
+	assert.True(t, errors.Is(err, ErrProtocol))
+
+	run := func(first, second string) error {
+		a, b := net.Pipe()
+		c, e := net.Pipe()
+		go func() {
+			io.WriteString(b, first)
+			io.Copy(io.Discard, b)
+		}()
+		go func() {
+			io.WriteString(e, second)
+			io.Copy(io.Discard, e)
+		}()
+		_, err := d.Run(a, c)
+		a.Close()
+		c.Close()
+		return err
+	}
+	assert.True(t, errors.Is(run("VERSION:1.0.0\r\n", "VERSION:2.0.0\r\n"), ErrProtocol))
+	// 位置1先行动，回复的不是最近的状态
+	assert.True(t, errors.Is(run("VERSION:2.0.0\r\n", "VERSION:2.0.0\r\nMATCHSTATE:1:0::|AsKd:c\r\n"), ErrProtocol))
+
+	_, err = (&Dealer{Game: Game{Players: 2}}).Run(&bytes.Buffer{}, &bytes.Buffer{})
+	assert.True(t, errors.Is(err, ErrUnsupportedGame))
+}
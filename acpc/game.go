@@ -0,0 +1,345 @@
+// Package acpc 年度计算机扑克大赛（ACPC）的 match state 协议，包括庄家服务器和客户端
+package acpc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Game ACPC 的游戏定义，位置从0开始
+	Game struct {
+		Betting     Betting
+		Players     int
+		Rounds      int
+		Stack       int64   // 每局开始时的筹码，限注游戏没有定义时为 math.MaxInt32
+		Blinds      []int64 // 每个位置的盲注
+		RaiseSizes  []int64 // 限注游戏每轮的加注额
+		FirstPlayer []int   // 每轮第一个行动的位置
+		MaxRaises   []int   // 限注游戏每轮的加注次数上限
+		Suits       int
+		Ranks       int
+		HoleCards   int
+		BoardCards  []int // 每轮发出的公共牌数量
+	}
+
+	// Betting 下注结构
+	Betting int
+)
+
+const (
+	BettingLimit   Betting = iota // 固定限注
+	BettingNoLimit                // 无限注
+)
+
+var (
+	// HoldemLimit2P 两人限注德州扑克，大盲在位置0
+	HoldemLimit2P = Game{
+		Betting:     BettingLimit,
+		Players:     2,
+		Rounds:      4,
+		Stack:       math.MaxInt32,
+		Blinds:      []int64{10, 5},
+		RaiseSizes:  []int64{10, 10, 20, 20},
+		FirstPlayer: []int{1, 0, 0, 0},
+		MaxRaises:   []int{3, 4, 4, 4},
+		Suits:       4,
+		Ranks:       13,
+		HoleCards:   2,
+		BoardCards:  []int{0, 3, 1, 1},
+	}
+
+	// HoldemNoLimit2P 两人无限注德州扑克，每局200个大盲
+	HoldemNoLimit2P = Game{
+		Betting:     BettingNoLimit,
+		Players:     2,
+		Rounds:      4,
+		Stack:       20000,
+		Blinds:      []int64{100, 50},
+		FirstPlayer: []int{1, 0, 0, 0},
+		Suits:       4,
+		Ranks:       13,
+		HoleCards:   2,
+		BoardCards:  []int{0, 3, 1, 1},
+	}
+
+	// HoldemLimit3P 三人限注德州扑克，庄家在位置2
+	HoldemLimit3P = Game{
+		Betting:     BettingLimit,
+		Players:     3,
+		Rounds:      4,
+		Stack:       math.MaxInt32,
+		Blinds:      []int64{5, 10, 0},
+		RaiseSizes:  []int64{10, 10, 20, 20},
+		FirstPlayer: []int{2, 0, 0, 0},
+		MaxRaises:   []int{3, 4, 4, 4},
+		Suits:       4,
+		Ranks:       13,
+		HoleCards:   2,
+		BoardCards:  []int{0, 3, 1, 1},
+	}
+)
+
+var (
+	ErrProtocol        = errors.New("acpc protocol error")
+	ErrUnsupportedGame = errors.New("unsupported acpc game")
+)
+
+// ParseGame 读取 GAMEDEF 和 END GAMEDEF 之间的游戏定义，# 开头的行是注释
+func ParseGame(r io.Reader) (Game, error) {
+	g := Game{Rounds: 4, Suits: 4, Ranks: 13, HoleCards: 2}
+	var stacks []int64
+	started := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		lower := strings.ToLower(line)
+		switch {
+		case lower == "gamedef":
+			started = true
+			continue
+		case lower == "end gamedef":
+			if err := g.validate(stacks); err != nil {
+				return Game{}, err
+			}
+			return g, nil
+		case !started:
+			return Game{}, fmt.Errorf("%w: expect GAMEDEF, got %q", ErrProtocol, line)
+		case lower == "limit":
+			g.Betting = BettingLimit
+			continue
+		case lower == "nolimit":
+			g.Betting = BettingNoLimit
+			continue
+		}
+
+		i := strings.IndexByte(line, '=')
+		if i < 0 {
+			return Game{}, fmt.Errorf("%w: malformed game line %q", ErrProtocol, line)
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:i]))
+		values, err := parseInts(strings.Fields(line[i+1:]))
+		if err != nil || len(values) == 0 {
+			return Game{}, fmt.Errorf("%w: malformed game line %q", ErrProtocol, line)
+		}
+		switch key {
+		case "numplayers":
+			g.Players = int(values[0])
+		case "numrounds":
+			g.Rounds = int(values[0])
+		case "stack":
+			stacks = values
+		case "blind":
+			g.Blinds = values
+		case "raisesize":
+			g.RaiseSizes = values
+		case "firstplayer":
+			// 文件中的位置从1开始
+			g.FirstPlayer = make([]int, len(values))
+			for j, v := range values {
+				g.FirstPlayer[j] = int(v) - 1
+			}
+		case "maxraises":
+			g.MaxRaises = toInts(values)
+		case "numsuits":
+			g.Suits = int(values[0])
+		case "numranks":
+			g.Ranks = int(values[0])
+		case "numholecards":
+			g.HoleCards = int(values[0])
+		case "numboardcards":
+			g.BoardCards = toInts(values)
+		default:
+			return Game{}, fmt.Errorf("%w: unknown game key %q", ErrProtocol, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Game{}, err
+	}
+	return Game{}, fmt.Errorf("%w: missing END GAMEDEF", ErrProtocol)
+}
+
+// String 游戏定义文件的格式
+func (g Game) String() string {
+	var b strings.Builder
+	b.WriteString("GAMEDEF\n")
+	if g.Betting == BettingLimit {
+		b.WriteString("limit\n")
+	} else {
+		b.WriteString("nolimit\n")
+	}
+	fmt.Fprintf(&b, "numPlayers = %d\n", g.Players)
+	fmt.Fprintf(&b, "numRounds = %d\n", g.Rounds)
+	if g.Betting == BettingNoLimit || g.Stack != math.MaxInt32 {
+		b.WriteString("stack =")
+		for i := 0; i < g.Players; i++ {
+			fmt.Fprintf(&b, " %d", g.Stack)
+		}
+		b.WriteString("\n")
+	}
+	writeInts(&b, "blind", g.Blinds)
+	if g.Betting == BettingLimit {
+		writeInts(&b, "raiseSize", g.RaiseSizes)
+	}
+	first := make([]int64, len(g.FirstPlayer))
+	for i, p := range g.FirstPlayer {
+		first[i] = int64(p + 1)
+	}
+	writeInts(&b, "firstPlayer", first)
+	if len(g.MaxRaises) > 0 {
+		writeInts(&b, "maxRaises", toInt64s(g.MaxRaises))
+	}
+	fmt.Fprintf(&b, "numSuits = %d\n", g.Suits)
+	fmt.Fprintf(&b, "numRanks = %d\n", g.Ranks)
+	fmt.Fprintf(&b, "numHoleCards = %d\n", g.HoleCards)
+	writeInts(&b, "numBoardCards", toInt64s(g.BoardCards))
+	b.WriteString("END GAMEDEF\n")
+	return b.String()
+}
+
+// Config 对应的牌局配置，只支持标准的德州扑克：
+// 两人时位置1是庄家和小盲，三人以上时位置0和1是小盲和大盲，最后一个位置是庄家
+func (g Game) Config() (table.Config, error) {
+	if err := g.standard(); err != nil {
+		return table.Config{}, err
+	}
+	cfg := table.Config{Variant: table.VariantHoldem, Structure: table.NoLimit{}}
+	if g.Players == 2 {
+		cfg.SmallBlind, cfg.BigBlind = g.Blinds[1], g.Blinds[0]
+	} else {
+		cfg.SmallBlind, cfg.BigBlind = g.Blinds[0], g.Blinds[1]
+	}
+	if g.Betting == BettingLimit {
+		// 翻牌前大盲算一次下注，所以翻牌前的加注上限比其他轮少一次
+		cfg.Structure = table.FixedLimit{SmallBet: g.RaiseSizes[0], BigBet: g.RaiseSizes[2], Cap: g.MaxRaises[1]}
+	}
+	return cfg, nil
+}
+
+// Seat 位置对应的座位号
+func (g Game) Seat(position int) int {
+	return position + 1
+}
+
+// Button 庄家的座位号
+func (g Game) Button() int {
+	return g.Seat(g.Players - 1)
+}
+
+// standard 检查是否是引擎支持的标准德州扑克
+func (g Game) standard() error {
+	if g.Players < 2 || g.Players > 10 || g.Rounds != 4 || g.Suits != 4 || g.Ranks != 13 || g.HoleCards != 2 ||
+		!equalInts(g.BoardCards, []int{0, 3, 1, 1}) {
+		return fmt.Errorf("%w: only hold'em with 2-10 players is supported", ErrUnsupportedGame)
+	}
+	if len(g.Blinds) != g.Players || g.Stack <= 0 {
+		return fmt.Errorf("%w: blinds %v, stack %d", ErrUnsupportedGame, g.Blinds, g.Stack)
+	}
+
+	// 两人时位置0是大盲，三人以上时位置0和1是小盲和大盲，其他位置没有盲注
+	small, big, first := g.Blinds[1], g.Blinds[0], []int{1, 0, 0, 0}
+	if g.Players > 2 {
+		small, big, first = g.Blinds[0], g.Blinds[1], []int{2, 0, 0, 0}
+		for _, b := range g.Blinds[2:] {
+			if b != 0 {
+				small = 0
+			}
+		}
+	}
+	if small <= 0 || small > big || !equalInts(g.FirstPlayer, first) {
+		return fmt.Errorf("%w: blinds %v and first player %v are not standard", ErrUnsupportedGame, g.Blinds, g.FirstPlayer)
+	}
+
+	if g.Betting == BettingLimit {
+		if len(g.RaiseSizes) != 4 || g.RaiseSizes[0] != g.RaiseSizes[1] || g.RaiseSizes[2] != g.RaiseSizes[3] ||
+			len(g.MaxRaises) != 4 || g.MaxRaises[0]+1 != g.MaxRaises[1] || !equalInts(g.MaxRaises[1:], []int{g.MaxRaises[1], g.MaxRaises[1], g.MaxRaises[1]}) {
+			return fmt.Errorf("%w: raise sizes %v and max raises %v are not standard", ErrUnsupportedGame, g.RaiseSizes, g.MaxRaises)
+		}
+	}
+	return nil
+}
+
+// validate 检查解析出的游戏定义是否完整，各个玩家的筹码必须相同
+func (g *Game) validate(stacks []int64) error {
+	if g.Players < 2 || len(g.Blinds) != g.Players || len(g.FirstPlayer) != g.Rounds || len(g.BoardCards) != g.Rounds {
+		return fmt.Errorf("%w: incomplete game definition", ErrProtocol)
+	}
+	g.Stack = math.MaxInt32
+	if len(stacks) > 0 {
+		if len(stacks) != g.Players {
+			return fmt.Errorf("%w: %d stacks for %d players", ErrProtocol, len(stacks), g.Players)
+		}
+		for _, s := range stacks {
+			if s != stacks[0] {
+				return fmt.Errorf("%w: unequal stacks %v", ErrUnsupportedGame, stacks)
+			}
+		}
+		g.Stack = stacks[0]
+	}
+	if g.Betting == BettingLimit && (len(g.RaiseSizes) != g.Rounds || len(g.MaxRaises) != g.Rounds) {
+		return fmt.Errorf("%w: limit game needs raiseSize and maxRaises", ErrProtocol)
+	}
+	return nil
+}
+
+func parseInts(fields []string) ([]int64, error) {
+	values := make([]int64, 0, len(fields))
+	for _, f := range fields {
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func writeInts(b *strings.Builder, key string, values []int64) {
+	b.WriteString(key + " =")
+	for _, v := range values {
+		fmt.Fprintf(b, " %d", v)
+	}
+	b.WriteString("\n")
+}
+
+func toInts(values []int64) []int {
+	ints := make([]int, len(values))
+	for i, v := range values {
+		ints[i] = int(v)
+	}
+	return ints
+}
+
+func toInt64s(values []int) []int64 {
+	ints := make([]int64, len(values))
+	for i, v := range values {
+		ints[i] = int64(v)
+	}
+	return ints
+}
+
+func equalInts(a, b []int) bool {
+	return equalInt64s(toInt64s(a), toInt64s(b))
+}
+
+func equalInt64s(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package acpc
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/stretchr/testify/assert"
+)
+
+const noLimitGame = `# 2p no-limit hold'em
+GAMEDEF
+nolimit
+numPlayers = 2
+numRounds = 4
+stack = 20000 20000
+blind = 100 50
+firstPlayer = 2 1 1 1
+numSuits = 4
+numRanks = 13
+numHoleCards = 2
+numBoardCards = 0 3 1 1
+END GAMEDEF
+`
+
+func TestParseGame(t *testing.T) {
+	g, err := ParseGame(strings.NewReader(noLimitGame))
+	assert.Nil(t, err)
+	assert.Equal(t, HoldemNoLimit2P, g)
+
+	for _, game := range []Game{HoldemLimit2P, HoldemNoLimit2P, HoldemLimit3P} {
+		parsed, err := ParseGame(strings.NewReader(game.String()))
+		assert.Nil(t, err)
+		assert.Equal(t, game, parsed)
+	}
+	assert.NotContains(t, HoldemLimit2P.String(), "stack")
+	assert.Equal(t, int64(math.MaxInt32), HoldemLimit2P.Stack)
+
+	for _, text := range []string{
+		"nolimit\n",
+		"GAMEDEF\nnolimit\nnumPlayers = two\nEND GAMEDEF\n",
+		"GAMEDEF\nnolimit\nfoo = 1\nEND GAMEDEF\n",
+		"GAMEDEF\nnolimit\nnumPlayers = 2\n",
+		"GAMEDEF\nlimit\nnumPlayers = 2\nblind = 10 5\nfirstPlayer = 2 1 1 1\nnumBoardCards = 0 3 1 1\nEND GAMEDEF\n",
+	} {
+		_, err := ParseGame(strings.NewReader(text))
+		assert.True(t, errors.Is(err, ErrProtocol), text)
+	}
+	_, err = ParseGame(strings.NewReader(strings.Replace(noLimitGame, "stack = 20000 20000", "stack = 20000 10000", 1)))
+	assert.True(t, errors.Is(err, ErrUnsupportedGame))
+}
+
+func TestGameConfig(t *testing.T) {
+	cfg, err := HoldemNoLimit2P.Config()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50), cfg.SmallBlind)
+	assert.Equal(t, int64(100), cfg.BigBlind)
+	assert.Equal(t, table.NoLimit{}, cfg.Structure)
+	assert.Equal(t, 2, HoldemNoLimit2P.Button())
+
+	cfg, err = HoldemLimit3P.Config()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), cfg.SmallBlind)
+	assert.Equal(t, int64(10), cfg.BigBlind)
+	assert.Equal(t, table.FixedLimit{SmallBet: 10, BigBet: 20, Cap: 4}, cfg.Structure)
+	assert.Equal(t, 3, HoldemLimit3P.Button())
+
+	unsupported := []func(g *Game){
+		func(g *Game) { g.Blinds = []int64{50, 100} },
+		func(g *Game) { g.FirstPlayer = []int{0, 1, 1, 1} },
+		func(g *Game) { g.HoleCards = 4 },
+		func(g *Game) { g.MaxRaises = []int{4, 4, 4, 4} },
+	}
+	for i, change := range unsupported {
+		g := HoldemLimit2P
+		change(&g)
+		_, err := g.Config()
+		assert.True(t, errors.Is(err, ErrUnsupportedGame), i)
+	}
+}
@@ -0,0 +1,289 @@
+package acpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/openpoker-dev/contrib/table/replay"
+)
+
+type (
+	// MatchState 一个位置看到的牌局状态，例如 MATCHSTATE:0:12:cr200c/:AsKd|/2c3c4c
+	MatchState struct {
+		Position int
+		Hand     int           // 比赛中的第几局，从0开始
+		Betting  [][]Action    // 每轮的行动，长度为已经开始的轮数
+		Holes    [][]card.Card // 每个位置的手牌，看不到时为空
+		Board    [][]card.Card // 每轮发出的公共牌，第一轮为空
+	}
+
+	// Action ACPC 的行动，无限注加注的 Size 为加注后本局总共投入的筹码
+	Action struct {
+		Type ActionType
+		Size int64
+	}
+
+	// ActionType ACPC 的行动类型
+	ActionType byte
+)
+
+const (
+	ActionFold  ActionType = 'f' // 弃牌
+	ActionCall  ActionType = 'c' // 跟注或过牌
+	ActionRaise ActionType = 'r' // 下注或加注
+)
+
+const (
+	matchStatePrefix = "MATCHSTATE"
+	version          = "VERSION:2.0.0"
+)
+
+// ParseMatchState 解析 match state，不包括行尾的换行
+func ParseMatchState(s string) (*MatchState, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 5 || parts[0] != matchStatePrefix {
+		return nil, fmt.Errorf("%w: malformed match state %q", ErrProtocol, s)
+	}
+	position, err1 := strconv.Atoi(parts[1])
+	hand, err2 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil || position < 0 || hand < 0 {
+		return nil, fmt.Errorf("%w: malformed match state %q", ErrProtocol, s)
+	}
+	ms := &MatchState{Position: position, Hand: hand}
+
+	for _, round := range strings.Split(parts[3], "/") {
+		actions, err := parseActions(round)
+		if err != nil {
+			return nil, fmt.Errorf("%w in %q", err, s)
+		}
+		ms.Betting = append(ms.Betting, actions)
+	}
+
+	rounds := strings.Split(parts[4], "/")
+	for _, hole := range strings.Split(rounds[0], "|") {
+		cards, err := parseCards(hole)
+		if err != nil {
+			return nil, fmt.Errorf("%w in %q", err, s)
+		}
+		ms.Holes = append(ms.Holes, cards)
+	}
+	ms.Board = append(ms.Board, nil)
+	for _, round := range rounds[1:] {
+		cards, err := parseCards(round)
+		if err != nil {
+			return nil, fmt.Errorf("%w in %q", err, s)
+		}
+		ms.Board = append(ms.Board, cards)
+	}
+	if position >= len(ms.Holes) {
+		return nil, fmt.Errorf("%w: position %d out of range in %q", ErrProtocol, position, s)
+	}
+	return ms, nil
+}
+
+// ParseResponse 解析客户端的回复，返回回复的 match state 和行动
+func ParseResponse(s string) (string, Action, error) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return "", Action{}, fmt.Errorf("%w: malformed response %q", ErrProtocol, s)
+	}
+	actions, err := parseActions(s[i+1:])
+	if err != nil || len(actions) != 1 {
+		return "", Action{}, fmt.Errorf("%w: malformed response %q", ErrProtocol, s)
+	}
+	return s[:i], actions[0], nil
+}
+
+func (ms *MatchState) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d:%d:", matchStatePrefix, ms.Position, ms.Hand)
+	for i, round := range ms.Betting {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		for _, a := range round {
+			b.WriteString(a.String())
+		}
+	}
+	b.WriteByte(':')
+	for i, hole := range ms.Holes {
+		if i > 0 {
+			b.WriteByte('|')
+		}
+		writeCards(&b, hole)
+	}
+	for _, round := range ms.Board[min(1, len(ms.Board)):] {
+		b.WriteByte('/')
+		writeCards(&b, round)
+	}
+	return b.String()
+}
+
+// Round 当前的轮次，从0开始
+func (ms *MatchState) Round() int {
+	return len(ms.Betting) - 1
+}
+
+// Cards 公共牌
+func (ms *MatchState) Cards() []card.Card {
+	var board []card.Card
+	for _, round := range ms.Board {
+		board = append(board, round...)
+	}
+	return board
+}
+
+// Replay 在本地牌局引擎上重放到当前状态，看不到的手牌用其他牌补齐
+func (ms *MatchState) Replay(g Game) (*table.Hand, error) {
+	cfg, err := g.Config()
+	if err != nil {
+		return nil, err
+	}
+	if len(ms.Holes) != g.Players {
+		return nil, fmt.Errorf("%w: %d hands for %d players", ErrProtocol, len(ms.Holes), g.Players)
+	}
+	record := &history.Hand{Game: history.GameHoldem, Button: g.Button(), Board: ms.Cards()}
+	players := make([]table.Player, g.Players)
+	for i := range players {
+		players[i] = table.Player{Seat: g.Seat(i), Name: strconv.Itoa(i), Stack: g.Stack}
+		record.Players = append(record.Players, history.Player{Seat: g.Seat(i), Cards: ms.Holes[i]})
+	}
+	deck, err := replay.Deck(record)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProtocol, err)
+	}
+	h, err := table.NewHand(cfg, players, g.Button(), deck)
+	if err != nil {
+		return nil, err
+	}
+
+	for round, actions := range ms.Betting {
+		for _, a := range actions {
+			seat, ok := h.ToAct()
+			if !ok || int(h.Street()) != round {
+				return nil, fmt.Errorf("%w: unexpected action %s in round %d", ErrProtocol, a, round)
+			}
+			if err := h.Apply(engineAction(h, seat, a)); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrProtocol, err)
+			}
+		}
+	}
+	return h, nil
+}
+
+func (a Action) String() string {
+	if a.Type == ActionRaise && a.Size > 0 {
+		return "r" + strconv.FormatInt(a.Size, 10)
+	}
+	return string(a.Type)
+}
+
+// engineAction 把 ACPC 行动转换为引擎当前可以执行的行动，按 ACPC 庄家的规则修正不合法的行动：
+// 不需要跟注时弃牌当作过牌，不能加注时当作跟注，加注额限制在合法范围内
+func engineAction(h *table.Hand, seat int, a Action) table.Action {
+	legal := make(map[table.ActionType]table.LegalAction)
+	for _, la := range h.LegalActions() {
+		legal[la.Type] = la
+	}
+	var state table.SeatState
+	for _, s := range h.Seats() {
+		if s.Seat == seat {
+			state = s
+		}
+	}
+
+	if a.Type == ActionRaise {
+		for _, at := range []table.ActionType{table.ActionBet, table.ActionRaise} {
+			la, ok := legal[at]
+			if !ok {
+				continue
+			}
+			// 总投入换算成本轮下注后的总额，限注时 Size 为 0 按最小额处理
+			amount := a.Size - (state.Total - state.Committed)
+			if amount < la.Min {
+				amount = la.Min
+			}
+			if amount > la.Max {
+				amount = la.Max
+			}
+			return table.Action{Seat: seat, Type: at, Amount: amount}
+		}
+	}
+	if _, ok := legal[table.ActionFold]; ok && a.Type == ActionFold {
+		return table.Action{Seat: seat, Type: table.ActionFold}
+	}
+	if _, ok := legal[table.ActionCheck]; ok {
+		return table.Action{Seat: seat, Type: table.ActionCheck}
+	}
+	return table.Action{Seat: seat, Type: table.ActionCall}
+}
+
+// acpcAction 引擎行动对应的 ACPC 行动，total 为行动后本局总共投入的筹码
+func acpcAction(a history.Action, total int64, betting Betting) Action {
+	switch a.Type {
+	case history.ActionFold:
+		return Action{Type: ActionFold}
+	case history.ActionBet, history.ActionRaise:
+		if betting == BettingNoLimit {
+			return Action{Type: ActionRaise, Size: total}
+		}
+		return Action{Type: ActionRaise}
+	}
+	return Action{Type: ActionCall}
+}
+
+func parseActions(s string) ([]Action, error) {
+	var actions []Action
+	for i := 0; i < len(s); {
+		a := Action{Type: ActionType(s[i])}
+		i++
+		switch a.Type {
+		case ActionFold, ActionCall:
+		case ActionRaise:
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			if j > i {
+				a.Size, _ = strconv.ParseInt(s[i:j], 10, 64)
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("%w: unknown action %q", ErrProtocol, s[i-1])
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+func parseCards(s string) ([]card.Card, error) {
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("%w: malformed cards %q", ErrProtocol, s)
+	}
+	var cards []card.Card
+	for i := 0; i < len(s); i += 2 {
+		var c card.Card
+		if err := c.UnmarshalText([]byte(s[i : i+2])); err != nil {
+			return nil, fmt.Errorf("%w: malformed cards %q", ErrProtocol, s)
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
+
+func writeCards(b *strings.Builder, cards []card.Card) {
+	for _, c := range cards {
+		b.WriteString(c.ASCII())
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package acpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMatchState(t *testing.T) {
+	ms, err := ParseMatchState("MATCHSTATE:1:12:r300c/cr900:|JdTc/2c3c4c")
+	assert.Nil(t, err)
+	assert.Equal(t, &MatchState{
+		Position: 1,
+		Hand:     12,
+		Betting: [][]Action{
+			{{Type: ActionRaise, Size: 300}, {Type: ActionCall}},
+			{{Type: ActionCall}, {Type: ActionRaise, Size: 900}},
+		},
+		Holes: [][]card.Card{nil, cardtest.Cards(t, "Jd Tc")},
+		Board: [][]card.Card{nil, cardtest.Cards(t, "2c 3c 4c")},
+	}, ms)
+	assert.Equal(t, 1, ms.Round())
+	assert.Equal(t, cardtest.Cards(t, "2c 3c 4c"), ms.Cards())
+
+	for _, s := range []string{
+		"MATCHSTATE:0:0::AsKd|",
+		"MATCHSTATE:0:0:rrc/:AsKd|QhQd/2c3c4c",
+		"MATCHSTATE:2:99:crr/cc/cc/rf:|AsKd|/2c3c4c/5d/6h",
+	} {
+		ms, err := ParseMatchState(s)
+		assert.Nil(t, err)
+		assert.Equal(t, s, ms.String())
+	}
+
+	for _, s := range []string{
+		"MATCHSTATE:0:0:cr",
+		"STATE:0:0::AsKd|",
+		"MATCHSTATE:x:0::AsKd|",
+		"MATCHSTATE:0:0:cb::AsKd|",
+		"MATCHSTATE:0:0:cx:AsKd|",
+		"MATCHSTATE:0:0::AsK|",
+		"MATCHSTATE:0:0::AsKx|",
+		"MATCHSTATE:3:0::AsKd|",
+	} {
+		_, err := ParseMatchState(s)
+		assert.True(t, errors.Is(err, ErrProtocol), s)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	state, a, err := ParseResponse("MATCHSTATE:0:0:r300:AsKd|:r900")
+	assert.Nil(t, err)
+	assert.Equal(t, "MATCHSTATE:0:0:r300:AsKd|", state)
+	assert.Equal(t, Action{Type: ActionRaise, Size: 900}, a)
+
+	_, a, err = ParseResponse("MATCHSTATE:0:0::AsKd|:f")
+	assert.Nil(t, err)
+	assert.Equal(t, Action{Type: ActionFold}, a)
+
+	for _, s := range []string{"f", "MATCHSTATE:0:0::AsKd|:", "MATCHSTATE:0:0::AsKd|:cc", "MATCHSTATE:0:0::AsKd|:x"} {
+		_, _, err := ParseResponse(s)
+		assert.True(t, errors.Is(err, ErrProtocol), s)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	ms, err := ParseMatchState("MATCHSTATE:1:0:r300c/r900:|JdTc/2c3c4c")
+	assert.Nil(t, err)
+	h, err := ms.Replay(HoldemNoLimit2P)
+	assert.Nil(t, err)
+
+	seat, ok := h.ToAct()
+	assert.True(t, ok)
+	assert.Equal(t, 2, seat)
+	assert.Equal(t, table.StreetFlop, h.Street())
+	assert.Equal(t, cardtest.Cards(t, "2c 3c 4c"), h.Board())
+	states := h.Seats()
+	assert.Equal(t, cardtest.Cards(t, "Jd Tc"), states[1].Hole)
+	assert.Equal(t, int64(900), states[0].Total)
+	assert.Equal(t, int64(600), states[0].Committed)
+	assert.Equal(t, int64(300), states[1].Total)
+
+	// 不合法的行动按庄家的规则修正：过牌时弃牌当作过牌，加注额限制在合法范围内
+	ms, err = ParseMatchState("MATCHSTATE:0:0:cf/r1:AsKd|/2c3c4c")
+	assert.Nil(t, err)
+	h, err = ms.Replay(HoldemNoLimit2P)
+	assert.Nil(t, err)
+	assert.Equal(t, table.StreetFlop, h.Street())
+	assert.False(t, h.Seats()[0].Folded)
+	assert.Equal(t, int64(200), h.Seats()[0].Total)
+	assert.Equal(t, int64(100), h.Seats()[0].Committed)
+
+	// 该行动的玩家已经弃牌
+	ms, err = ParseMatchState("MATCHSTATE:0:0:fc:AsKd|")
+	assert.Nil(t, err)
+	_, err = ms.Replay(HoldemNoLimit2P)
+	assert.True(t, errors.Is(err, ErrProtocol))
+
+	// 手牌与公共牌重复
+	ms, err = ParseMatchState("MATCHSTATE:0:0:cc/:AsKd|/As3c4c")
+	assert.Nil(t, err)
+	_, err = ms.Replay(HoldemNoLimit2P)
+	assert.True(t, errors.Is(err, ErrProtocol))
+}
@@ -0,0 +1,164 @@
+// Package agent 机器人接口和本地对战模拟
+package agent
+
+import (
+	"math/rand"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Agent 机器人，同一个 Agent 不会被并发调用
+	Agent interface {
+		// Act 轮到自己时根据观察到的状态选择行动，不合法的行动按能过牌就过牌、否则弃牌处理
+		Act(o Observation) table.Action
+		// Observe 一局结束时收到牌局历史，没有摊牌的其他玩家手牌已经隐藏，不能修改
+		Observe(record *history.Hand)
+	}
+
+	// Observation 轮到行动时看到的状态，其他玩家的手牌已经隐藏
+	Observation struct {
+		Seat     int
+		Hole     []card.Card
+		Board    []card.Card
+		Street   table.Street
+		Button   int
+		BigBlind int64
+		Pot      int64 // 包括本轮已经下注的筹码
+		Seats    []table.SeatState
+		Legal    []table.LegalAction
+		Actions  []history.Action // 本局之前的行动
+	}
+
+	// Func 只根据状态选择行动的机器人
+	Func func(o Observation) table.Action
+
+	// Random 随机选择合法行动的机器人，下注额在范围内均匀分布
+	Random struct {
+		rand *rand.Rand
+	}
+)
+
+var (
+	_ Agent = Func(nil)
+	_ Agent = (*Random)(nil)
+
+	// CallingStation 从不弃牌也从不加注
+	CallingStation = Func(func(o Observation) table.Action {
+		if la, ok := o.Find(table.ActionCheck); ok {
+			return o.action(la.Type, 0)
+		}
+		if la, ok := o.Find(table.ActionCall); ok {
+			return o.action(la.Type, la.Min)
+		}
+		return o.action(table.ActionFold, 0)
+	})
+
+	// Maniac 总是以最小额度下注或加注
+	Maniac = Func(func(o Observation) table.Action {
+		for _, at := range []table.ActionType{table.ActionRaise, table.ActionBet} {
+			if la, ok := o.Find(at); ok {
+				return o.action(at, la.Min)
+			}
+		}
+		return CallingStation(o)
+	})
+
+	// Folder 能过牌就过牌，否则弃牌
+	Folder = Func(func(o Observation) table.Action {
+		if _, ok := o.Find(table.ActionCheck); ok {
+			return o.action(table.ActionCheck, 0)
+		}
+		return o.action(table.ActionFold, 0)
+	})
+)
+
+func (f Func) Act(o Observation) table.Action {
+	return f(o)
+}
+
+func (f Func) Observe(*history.Hand) {}
+
+// NewRandom 使用固定种子的随机机器人
+func NewRandom(seed int64) *Random {
+	return &Random{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (r *Random) Act(o Observation) table.Action {
+	la := o.Legal[r.rand.Intn(len(o.Legal))]
+	amount := la.Min
+	if la.Max > la.Min {
+		amount += r.rand.Int63n(la.Max - la.Min + 1)
+	}
+	return o.action(la.Type, amount)
+}
+
+func (r *Random) Observe(*history.Hand) {}
+
+// NewObservation 座位 seat 在牌局 h 中看到的状态
+func NewObservation(h *table.Hand, seat int, bigBlind int64) Observation {
+	seats := h.Seats()
+	var hole []card.Card
+	for i := range seats {
+		if seats[i].Seat == seat {
+			hole = seats[i].Hole
+		} else {
+			seats[i].Hole = nil
+		}
+	}
+	return Observation{
+		Seat:     seat,
+		Hole:     hole,
+		Board:    h.Board(),
+		Street:   h.Street(),
+		Button:   h.Button(),
+		BigBlind: bigBlind,
+		Pot:      h.Pot(),
+		Seats:    seats,
+		Legal:    h.LegalActions(),
+		Actions:  h.Actions(),
+	}
+}
+
+// Hide 隐藏没有摊牌的其他玩家的手牌，除了玩家列表之外与 record 共享数据
+func Hide(record *history.Hand, seat int) *history.Hand {
+	shown := make(map[int]bool, len(record.Showdown)+1)
+	shown[seat] = true
+	for _, s := range record.Showdown {
+		shown[s.Seat] = true
+	}
+	hidden := *record
+	hidden.Players = append([]history.Player{}, record.Players...)
+	for i := range hidden.Players {
+		if !shown[hidden.Players[i].Seat] {
+			hidden.Players[i].Cards = nil
+		}
+	}
+	return &hidden
+}
+
+// Find 查找某种行动是否合法
+func (o Observation) Find(at table.ActionType) (table.LegalAction, bool) {
+	for _, la := range o.Legal {
+		if la.Type == at {
+			return la, true
+		}
+	}
+	return table.LegalAction{}, false
+}
+
+// Stack 自己剩余的筹码
+func (o Observation) Stack() int64 {
+	for _, s := range o.Seats {
+		if s.Seat == o.Seat {
+			return s.Stack
+		}
+	}
+	return 0
+}
+
+func (o Observation) action(at table.ActionType, amount int64) table.Action {
+	return table.Action{Seat: o.Seat, Type: at, Amount: amount}
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinAgents(t *testing.T) {
+	facing := Observation{Seat: 2, Legal: []table.LegalAction{
+		{Type: table.ActionFold},
+		{Type: table.ActionCall, Min: 10, Max: 10},
+		{Type: table.ActionRaise, Min: 20, Max: 1000},
+	}}
+	unopened := Observation{Seat: 2, Legal: []table.LegalAction{
+		{Type: table.ActionCheck},
+		{Type: table.ActionBet, Min: 10, Max: 1000},
+	}}
+
+	assert.Equal(t, table.Action{Seat: 2, Type: table.ActionCall, Amount: 10}, CallingStation.Act(facing))
+	assert.Equal(t, table.Action{Seat: 2, Type: table.ActionCheck}, CallingStation.Act(unopened))
+	assert.Equal(t, table.Action{Seat: 2, Type: table.ActionRaise, Amount: 20}, Maniac.Act(facing))
+	assert.Equal(t, table.Action{Seat: 2, Type: table.ActionBet, Amount: 10}, Maniac.Act(unopened))
+	assert.Equal(t, table.Action{Seat: 2, Type: table.ActionFold}, Folder.Act(facing))
+	assert.Equal(t, table.Action{Seat: 2, Type: table.ActionCheck}, Folder.Act(unopened))
+
+	r1, r2 := NewRandom(1), NewRandom(1)
+	for i := 0; i < 100; i++ {
+		a := r1.Act(facing)
+		assert.Equal(t, a, r2.Act(facing))
+		la, ok := facing.Find(a.Type)
+		assert.True(t, ok)
+		if a.Type == table.ActionRaise {
+			assert.True(t, a.Amount >= la.Min && a.Amount <= la.Max)
+		}
+	}
+}
+
+func TestObservation(t *testing.T) {
+	hole, _ := card.ParseCards("As Kd")
+	o := Observation{Seat: 3, Hole: hole, Seats: []table.SeatState{
+		{Stake: table.Stake{Player: table.Player{Seat: 1, Stack: 500}}},
+		{Stake: table.Stake{Player: table.Player{Seat: 3, Stack: 800}}},
+	}}
+	assert.Equal(t, int64(800), o.Stack())
+	_, ok := o.Find(table.ActionCheck)
+	assert.False(t, ok)
+}
@@ -0,0 +1,231 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Entrant 参加模拟的机器人，每个工作协程用 New 创建自己的实例
+	Entrant struct {
+		Name string
+		New  func() Agent
+	}
+
+	// Config 模拟配置
+	Config struct {
+		Table     table.Config // 盲注、玩法和下注结构
+		Stack     int64        // 每局开始时的筹码，为空时使用100个大盲
+		Hands     int          // 每个机器人打的局数，复式模式下向上取整到机器人数量的倍数
+		Seed      int64        // 发牌的随机种子，种子相同时每局的牌相同
+		Duplicate bool         // 复式模式：同一副牌轮换座位各打一次，抵消运气的影响
+		Workers   int          // 并发的工作协程数，为空时为1
+	}
+
+	// Report 模拟结果
+	Report struct {
+		Hands int     // 每个机器人打的局数
+		Deals int     // 不同的发牌数
+		Stats []Stats // 与参赛顺序相同
+	}
+
+	// Stats 一个机器人的成绩
+	Stats struct {
+		Name    string
+		Hands   int
+		Net     int64   // 净赢的筹码
+		BB100   float64 // 每100局赢的大盲数
+		StdErr  float64 // BB100 的标准误差
+		Illegal int     // 不合法行动的次数
+	}
+
+	// worker 一个工作协程的机器人实例和累计成绩
+	worker struct {
+		cfg     Config
+		names   []string
+		agents  []Agent
+		sums    []int64
+		squares []float64
+		net     []int64
+		illegal []int
+	}
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid simulation config")
+)
+
+// Simulate 在本地牌局引擎上让机器人互相对战，所有机器人每局都在座，座位号从1开始
+// 普通模式下每局轮换庄家；复式模式下同一副牌按座位轮换机器人各打一次，按发牌统计成绩
+func Simulate(cfg Config, entrants ...Entrant) (*Report, error) {
+	if len(entrants) < 2 || len(entrants) > 10 {
+		return nil, fmt.Errorf("%w: %d entrants", ErrInvalidConfig, len(entrants))
+	}
+	if cfg.Table.BigBlind <= 0 || cfg.Hands <= 0 {
+		return nil, fmt.Errorf("%w: big blind %d, hands %d", ErrInvalidConfig, cfg.Table.BigBlind, cfg.Hands)
+	}
+	if cfg.Stack <= 0 {
+		cfg.Stack = 100 * cfg.Table.BigBlind
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	n := len(entrants)
+	deals, rotations := cfg.Hands, 1
+	if cfg.Duplicate {
+		deals, rotations = (cfg.Hands+n-1)/n, n
+	}
+
+	workers := make([]*worker, cfg.Workers)
+	errs := make([]error, cfg.Workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		w := &worker{
+			cfg:     cfg,
+			names:   make([]string, n),
+			agents:  make([]Agent, n),
+			sums:    make([]int64, n),
+			squares: make([]float64, n),
+			net:     make([]int64, n),
+			illegal: make([]int, n),
+		}
+		// 每个工作协程使用自己的牌型计算器
+		w.cfg.Table.Evaluator = evaluator.NewEvaluatorManager()
+		for j, e := range entrants {
+			w.names[j] = e.Name
+			w.agents[j] = e.New()
+		}
+		workers[i] = w
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for deal := i; deal < deals; deal += cfg.Workers {
+				if err := workers[i].deal(deal, rotations); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := &Report{Hands: deals * rotations, Deals: deals, Stats: make([]Stats, n)}
+	for j, e := range entrants {
+		var sum int64
+		var squares float64
+		stats := Stats{Name: e.Name, Hands: report.Hands}
+		for _, w := range workers {
+			sum += w.sums[j]
+			squares += w.squares[j]
+			stats.Net += w.net[j]
+			stats.Illegal += w.illegal[j]
+		}
+		// 样本为每次发牌的净赢筹码，复式模式下是各个座位的合计
+		samples := float64(deals)
+		scale := 100 / float64(rotations) / float64(cfg.Table.BigBlind)
+		mean := float64(sum) / samples
+		stats.BB100 = mean * scale
+		if deals > 1 {
+			variance := math.Max(squares-mean*float64(sum), 0) / (samples - 1)
+			stats.StdErr = math.Sqrt(variance/samples) * scale
+		}
+		report.Stats[j] = stats
+	}
+	return report, nil
+}
+
+// Interval 置信度为 z 个标准误差的 BB100 区间，95% 的置信区间 z 为 1.96
+func (s Stats) Interval(z float64) (float64, float64) {
+	return s.BB100 - z*s.StdErr, s.BB100 + z*s.StdErr
+}
+
+// CI95 95% 置信区间
+func (s Stats) CI95() (float64, float64) {
+	return s.Interval(1.96)
+}
+
+// deal 用同一副牌打 rotations 局，第 r 局座位 i 坐第 (i+r)%n 个机器人
+func (w *worker) deal(deal, rotations int) error {
+	n := len(w.agents)
+	button := deal%n + 1
+	samples := make([]int64, n)
+	for r := 0; r < rotations; r++ {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = (i + r) % n
+		}
+		deltas, err := w.play(order, button, w.cfg.Seed+int64(deal))
+		if err != nil {
+			return fmt.Errorf("deal %d: %w", deal, err)
+		}
+		for i, j := range order {
+			samples[j] += deltas[i]
+		}
+	}
+	for j, sample := range samples {
+		w.sums[j] += sample
+		w.squares[j] += float64(sample) * float64(sample)
+		w.net[j] += sample
+	}
+	return nil
+}
+
+// play 打一局，order[i] 为座位 i+1 上机器人的下标，返回每个座位的净赢筹码
+func (w *worker) play(order []int, button int, seed int64) ([]int64, error) {
+	players := make([]table.Player, len(order))
+	for i := range order {
+		players[i] = table.Player{Seat: i + 1, Name: w.names[order[i]], Stack: w.cfg.Stack}
+	}
+	deck := card.NewSeededDeck(seed)
+	deck.Shuffle()
+	h, err := table.NewHand(w.cfg.Table, players, button, deck)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		seat, ok := h.ToAct()
+		if !ok {
+			break
+		}
+		j := order[seat-1]
+		a := w.agents[j].Act(NewObservation(h, seat, w.cfg.Table.BigBlind))
+		a.Seat = seat
+		err := h.Apply(a)
+		if errors.Is(err, table.ErrIllegalAction) {
+			w.illegal[j]++
+			a.Type = table.ActionCheck
+			if _, ok := find(h.LegalActions(), table.ActionCheck); !ok {
+				a.Type = table.ActionFold
+			}
+			err = h.Apply(a)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	record := h.History()
+	deltas := make([]int64, len(order))
+	for i, s := range h.Seats() {
+		deltas[i] = s.Stack - w.cfg.Stack
+		w.agents[order[i]].Observe(Hide(record, s.Seat))
+	}
+	return deltas, nil
+}
+
+func find(legal []table.LegalAction, at table.ActionType) (table.LegalAction, bool) {
+	return Observation{Legal: legal}.Find(at)
+}
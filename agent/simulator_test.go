@@ -0,0 +1,150 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/stretchr/testify/assert"
+)
+
+var blinds = table.Config{SmallBlind: 5, BigBlind: 10}
+
+func entrant(name string, a Agent) Entrant {
+	return Entrant{Name: name, New: func() Agent { return a }}
+}
+
+func randomEntrant(name string, seed int64) Entrant {
+	return Entrant{Name: name, New: func() Agent { return NewRandom(seed) }}
+}
+
+// spy 记录观察到的状态和牌局历史
+type spy struct {
+	observations []Observation
+	records      []*history.Hand
+}
+
+func (s *spy) Act(o Observation) table.Action {
+	s.observations = append(s.observations, o)
+	return CallingStation(o)
+}
+
+func (s *spy) Observe(record *history.Hand) {
+	s.records = append(s.records, record)
+}
+
+func TestSimulateDeterministic(t *testing.T) {
+	cfg := Config{Table: blinds, Hands: 500, Seed: 7}
+	run := func(cfg Config) *Report {
+		report, err := Simulate(cfg, randomEntrant("alice", 1), entrant("bob", CallingStation), entrant("carol", Maniac))
+		assert.Nil(t, err)
+		return report
+	}
+
+	first := run(cfg)
+	assert.Equal(t, first, run(cfg))
+	assert.Equal(t, 500, first.Hands)
+	assert.Equal(t, 500, first.Deals)
+
+	// 筹码守恒
+	var net int64
+	for _, s := range first.Stats {
+		net += s.Net
+		assert.Equal(t, 500, s.Hands)
+		assert.Equal(t, float64(s.Net)/10/500*100, s.BB100)
+		assert.Greater(t, s.StdErr, 0.0)
+	}
+	assert.Equal(t, int64(0), net)
+
+	cfg.Seed = 8
+	assert.NotEqual(t, first, run(cfg))
+}
+
+func TestSimulateDuplicate(t *testing.T) {
+	// 相同的机器人在复式模式下轮换所有座位，运气完全抵消
+	report, err := Simulate(Config{Table: blinds, Hands: 301, Seed: 3, Duplicate: true},
+		entrant("a", CallingStation), entrant("b", CallingStation), entrant("c", CallingStation))
+	assert.Nil(t, err)
+	assert.Equal(t, 101, report.Deals)
+	assert.Equal(t, 303, report.Hands)
+	for _, s := range report.Stats {
+		assert.Equal(t, int64(0), s.Net)
+		assert.Equal(t, 0.0, s.BB100)
+		assert.Equal(t, 0.0, s.StdErr)
+	}
+
+	// 复式模式的标准误差比普通模式小
+	cfg := Config{Table: blinds, Hands: 2000, Seed: 3}
+	normal, err := Simulate(cfg, entrant("station", CallingStation), entrant("maniac", Maniac))
+	assert.Nil(t, err)
+	cfg.Duplicate = true
+	duplicate, err := Simulate(cfg, entrant("station", CallingStation), entrant("maniac", Maniac))
+	assert.Nil(t, err)
+	assert.Less(t, duplicate.Stats[0].StdErr, normal.Stats[0].StdErr)
+	assert.Equal(t, -duplicate.Stats[0].Net, duplicate.Stats[1].Net)
+}
+
+func TestSimulateWorkers(t *testing.T) {
+	// 没有随机性的机器人结果与工作协程数无关
+	cfg := Config{Table: blinds, Hands: 400, Seed: 11, Duplicate: true}
+	single, err := Simulate(cfg, entrant("station", CallingStation), entrant("maniac", Maniac))
+	assert.Nil(t, err)
+	cfg.Workers = 4
+	parallel, err := Simulate(cfg, Entrant{Name: "station", New: func() Agent { return CallingStation }}, Entrant{Name: "maniac", New: func() Agent { return Maniac }})
+	assert.Nil(t, err)
+	assert.Equal(t, single.Stats[0].Net, parallel.Stats[0].Net)
+	assert.InDelta(t, single.Stats[0].StdErr, parallel.Stats[0].StdErr, 1e-9)
+}
+
+func TestSimulateConfidence(t *testing.T) {
+	// 总是弃牌的机器人每两局输掉一个小盲和一个大盲，即 -75bb/100
+	report, err := Simulate(Config{Table: blinds, Hands: 1000, Seed: 5}, entrant("folder", Folder), entrant("maniac", Maniac))
+	assert.Nil(t, err)
+	folder := report.Stats[0]
+	assert.InDelta(t, -75, folder.BB100, 1e-9)
+	low, high := folder.CI95()
+	assert.True(t, low < -75 && high > -75)
+	assert.InDelta(t, 1.96*folder.StdErr, high-folder.BB100, 1e-9)
+}
+
+func TestSimulateObservation(t *testing.T) {
+	s := &spy{}
+	_, err := Simulate(Config{Table: blinds, Hands: 20, Seed: 1}, entrant("spy", s), entrant("maniac", Maniac))
+	assert.Nil(t, err)
+	assert.Len(t, s.records, 20)
+	for _, o := range s.observations {
+		assert.Equal(t, 1, o.Seat)
+		assert.Len(t, o.Hole, 2)
+		assert.NotEmpty(t, o.Legal)
+		assert.Equal(t, int64(10), o.BigBlind)
+		for _, seat := range o.Seats {
+			if seat.Seat != o.Seat {
+				assert.Nil(t, seat.Hole)
+			}
+		}
+	}
+	for _, record := range s.records {
+		assert.Nil(t, record.Validate())
+		for _, p := range record.Players {
+			if p.Seat != 1 && len(record.Showdown) == 0 {
+				assert.Nil(t, p.Cards)
+			}
+		}
+	}
+}
+
+func TestSimulateIllegal(t *testing.T) {
+	cheater := Func(func(o Observation) table.Action {
+		return table.Action{Type: table.ActionRaise, Amount: 1}
+	})
+	report, err := Simulate(Config{Table: blinds, Hands: 10}, entrant("cheater", cheater), entrant("station", CallingStation))
+	assert.Nil(t, err)
+	assert.Greater(t, report.Stats[0].Illegal, 0)
+	assert.Equal(t, 0, report.Stats[1].Illegal)
+
+	_, err = Simulate(Config{Table: blinds, Hands: 10}, entrant("alone", CallingStation))
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+	_, err = Simulate(Config{Hands: 10}, entrant("a", CallingStation), entrant("b", CallingStation))
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+}
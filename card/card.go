@@ -1,8 +1,10 @@
 package card
 
 import (
+	"errors"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 type (
@@ -72,22 +74,53 @@ var (
 )
 
 func NewCard(s string) Card {
+	card, err := parseCard(s)
+	if err != nil {
+		panic(err.Error())
+	}
+	return card
+}
+
+// ParseCards 解析连续书写的牌，允许空白分隔，eg. "AsKd QhQc 2c7d9h"
+func ParseCards(s string) ([]Card, error) {
+	compact := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if len(compact)%2 != 0 {
+		return nil, errors.New("bad cards: " + s)
+	}
+
+	cards := make([]Card, 0, len(compact)/2)
+	for i := 0; i < len(compact); i += 2 {
+		card, err := parseCard(compact[i : i+2])
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+func parseCard(s string) (Card, error) {
 	if len(s) != 2 {
-		panic("bad card: " + s)
+		return Card{}, errors.New("bad card: " + s)
 	}
 	var card Card
 	cr, exists := rankMapping[strings.ToUpper(s[:1])]
 	if !exists {
-		panic("unknown rank: " + s[:1])
+		return Card{}, errors.New("unknown rank: " + s[:1])
 	}
 	card.Rank = cr
 
 	suit, exists := suitMapping[strings.ToLower(s[1:])]
 	if !exists {
-		panic("unknown suit: " + s[1:])
+		return Card{}, errors.New("unknown suit: " + s[1:])
 	}
 	card.Suit = suit
-	return card
+	return card, nil
 }
 
 func (card Card) String() string {
@@ -119,3 +152,47 @@ func (card Card) String() string {
 	}
 	return output
 }
+
+// ASCII 以 ASCII 字符输出，eg. "As" "Td"
+func (card Card) ASCII() string {
+	const (
+		ranks = "?A23456789TJQKA"
+		suits = "?hdsc"
+	)
+	output := make([]byte, 0, 2)
+	if card.Rank >= 0 && int(card.Rank) < len(ranks) {
+		output = append(output, ranks[card.Rank])
+	} else {
+		output = append(output, '?')
+	}
+	if card.Suit >= 0 && int(card.Suit) < len(suits) {
+		output = append(output, suits[card.Suit])
+	} else {
+		output = append(output, '?')
+	}
+	return string(output)
+}
+
+// MarshalText 编码为 ASCII 格式，未知的牌编码为空字符串
+func (card Card) MarshalText() ([]byte, error) {
+	if card == (Card{}) {
+		return []byte{}, nil
+	}
+	if !isStandardCard(card) {
+		return nil, errors.New("bad card: " + card.String())
+	}
+	return []byte(card.ASCII()), nil
+}
+
+func (card *Card) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*card = Card{}
+		return nil
+	}
+	parsed, err := parseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*card = parsed
+	return nil
+}
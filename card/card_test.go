@@ -16,3 +16,29 @@ func TestCardOutput(t *testing.T) {
 	card := NewCard("As")
 	assert.Equal(t, card.String(), "A♠️")
 }
+
+func TestParseCards(t *testing.T) {
+	cards, err := ParseCards("AsKd  Qh\tQc")
+	assert.Nil(t, err)
+	assert.Equal(t, []Card{NewCard("As"), NewCard("Kd"), NewCard("Qh"), NewCard("Qc")}, cards)
+
+	_, err = ParseCards("AsK")
+	assert.NotNil(t, err)
+	_, err = ParseCards("AsKx")
+	assert.NotNil(t, err)
+	_, err = ParseCards("1s")
+	assert.NotNil(t, err)
+}
+
+func TestCardText(t *testing.T) {
+	assert.Equal(t, "Td", NewCard("Td").ASCII())
+
+	text, err := NewCard("Ah").MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, "Ah", string(text))
+
+	var c Card
+	assert.Nil(t, c.UnmarshalText([]byte("9c")))
+	assert.Equal(t, NewCard("9c"), c)
+	assert.NotNil(t, c.UnmarshalText([]byte("9x")))
+}
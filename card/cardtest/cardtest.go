@@ -0,0 +1,18 @@
+// Package cardtest 测试中共用的牌的工具
+package cardtest
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+)
+
+// Cards 解析 s 中空格分隔的牌，解析失败时测试立即失败
+func Cards(t testing.TB, s string) []card.Card {
+	t.Helper()
+	cards, err := card.ParseCards(s)
+	if err != nil {
+		t.Fatalf("parse cards %q: %v", s, err)
+	}
+	return cards
+}
@@ -0,0 +1,251 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+type (
+	// DealStep 发牌流程中的一步，先烧牌，再按座位轮流发暗牌、明牌，最后发公共牌
+	DealStep struct {
+		Street string // 街的名字，eg. "flop" "third street"
+		Burn   bool   // 发牌前是否烧一张牌
+		Down   int    // 每个座位发的暗牌数
+		Up     int    // 每个座位发的明牌数
+		Board  int    // 公共牌数
+	}
+
+	// Procedure 某种玩法的发牌流程
+	Procedure struct {
+		Name  string
+		Steps []DealStep
+		// CommunityFallback 剩余的牌不够给每个座位发一张时，改为发一张公共牌（七张梭哈第七街）
+		CommunityFallback bool
+	}
+
+	// DealtCard 发到座位上的牌
+	DealtCard struct {
+		Card Card
+		Up   bool // 是否是明牌
+	}
+
+	// Dealer 按照发牌流程从牌堆中发牌
+	Dealer struct {
+		deck      Deck
+		procedure Procedure
+		order     []int // 发牌顺序，从庄家左手边开始
+		folded    map[int]bool
+		hands     map[int][]DealtCard
+		board     []Card
+		step      int
+	}
+)
+
+var (
+	HoldemProcedure = Procedure{
+		Name: "holdem",
+		Steps: []DealStep{
+			{Street: "preflop", Down: 2},
+			{Street: "flop", Burn: true, Board: 3},
+			{Street: "turn", Burn: true, Board: 1},
+			{Street: "river", Burn: true, Board: 1},
+		},
+	}
+
+	OmahaProcedure = Procedure{
+		Name: "omaha",
+		Steps: []DealStep{
+			{Street: "preflop", Down: 4},
+			{Street: "flop", Burn: true, Board: 3},
+			{Street: "turn", Burn: true, Board: 1},
+			{Street: "river", Burn: true, Board: 1},
+		},
+	}
+
+	StudProcedure = Procedure{
+		Name: "stud",
+		Steps: []DealStep{
+			{Street: "third street", Down: 2, Up: 1},
+			{Street: "fourth street", Burn: true, Up: 1},
+			{Street: "fifth street", Burn: true, Up: 1},
+			{Street: "sixth street", Burn: true, Up: 1},
+			{Street: "seventh street", Burn: true, Down: 1},
+		},
+		CommunityFallback: true,
+	}
+
+	DrawProcedure = Procedure{
+		Name: "draw",
+		Steps: []DealStep{
+			{Street: "predraw", Down: 5},
+		},
+	}
+
+	ErrMisdeal     = errors.New("misdeal")
+	ErrDealingDone = errors.New("no more dealing steps")
+)
+
+// NewDealer seats 为参与发牌的座位号，从 button 左手边的第一个座位开始发牌
+func NewDealer(deck Deck, procedure Procedure, seats []int, button int) (*Dealer, error) {
+	if deck == nil {
+		return nil, errors.New("nil deck")
+	}
+	if len(seats) == 0 {
+		return nil, errors.New("no seats")
+	}
+
+	order := append([]int{}, seats...)
+	sort.Ints(order)
+	for i := 1; i < len(order); i++ {
+		if order[i] == order[i-1] {
+			return nil, fmt.Errorf("duplicate seat: %d", order[i])
+		}
+	}
+	start := sort.SearchInts(order, button+1) % len(order)
+	order = append(order[start:], order[:start]...)
+
+	return &Dealer{
+		deck:      deck,
+		procedure: procedure,
+		order:     order,
+		folded:    make(map[int]bool),
+		hands:     make(map[int][]DealtCard),
+	}, nil
+}
+
+// Next 执行下一步发牌，牌不够时返回 ErrMisdeal
+func (d *Dealer) Next() (DealStep, error) {
+	if d.step >= len(d.procedure.Steps) {
+		return DealStep{}, ErrDealingDone
+	}
+	step := d.procedure.Steps[d.step]
+	d.step++
+
+	active := d.Active()
+	need := len(active)*(step.Down+step.Up) + step.Board
+	if step.Burn {
+		need++
+	}
+	if d.deck.Length() < need {
+		if !d.procedure.CommunityFallback || step.Down+step.Up != 1 || step.Board != 0 {
+			return step, fmt.Errorf("%w: deck exhausted on %s", ErrMisdeal, step.Street)
+		}
+		// 牌不够每人一张，改发一张所有人共用的公共牌
+		step = DealStep{Street: step.Street, Burn: step.Burn && d.deck.Length() > 1, Board: 1}
+	}
+
+	if step.Burn {
+		d.deck.Burn()
+	}
+	for i := 0; i < step.Down+step.Up; i++ {
+		up := i >= step.Down
+		for _, seat := range active {
+			c, ok := d.deck.Deal()
+			if !ok {
+				return step, fmt.Errorf("%w: deck exhausted on %s", ErrMisdeal, step.Street)
+			}
+			d.hands[seat] = append(d.hands[seat], DealtCard{Card: c, Up: up})
+		}
+	}
+	for i := 0; i < step.Board; i++ {
+		c, ok := d.deck.Deal()
+		if !ok {
+			return step, fmt.Errorf("%w: deck exhausted on %s", ErrMisdeal, step.Street)
+		}
+		d.board = append(d.board, c)
+	}
+	return step, nil
+}
+
+// DealTo 给指定座位补发牌，用于换牌
+func (d *Dealer) DealTo(seat int, n int, up bool) ([]Card, error) {
+	if !d.isSeated(seat) {
+		return nil, fmt.Errorf("unknown seat: %d", seat)
+	}
+	if d.deck.Length() < n {
+		return nil, fmt.Errorf("%w: deck exhausted", ErrMisdeal)
+	}
+	cards := make([]Card, 0, n)
+	for i := 0; i < n; i++ {
+		c, _ := d.deck.Deal()
+		cards = append(cards, c)
+		d.hands[seat] = append(d.hands[seat], DealtCard{Card: c, Up: up})
+	}
+	return cards, nil
+}
+
+// Discard 从座位的手牌中移除指定的牌
+func (d *Dealer) Discard(seat int, cards ...Card) error {
+	hand := d.hands[seat]
+	for _, c := range cards {
+		index := -1
+		for i, dc := range hand {
+			if dc.Card == c {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("seat %d does not hold %s", seat, c.ASCII())
+		}
+		hand = append(hand[:index:index], hand[index+1:]...)
+	}
+	d.hands[seat] = hand
+	return nil
+}
+
+// Expose 报告座位的暗牌被意外亮出，发第一轮牌时亮牌视为误发，之后亮出的牌改为明牌
+func (d *Dealer) Expose(seat int, c Card) error {
+	for i, dc := range d.hands[seat] {
+		if dc.Card != c || dc.Up {
+			continue
+		}
+		if d.step <= 1 {
+			return fmt.Errorf("%w: seat %d card exposed on %s", ErrMisdeal, seat, d.procedure.Steps[0].Street)
+		}
+		d.hands[seat][i].Up = true
+		return nil
+	}
+	return fmt.Errorf("seat %d does not hold down card %s", seat, c.ASCII())
+}
+
+// Fold 弃牌的座位不再发牌
+func (d *Dealer) Fold(seat int) {
+	d.folded[seat] = true
+}
+
+// Active 按发牌顺序返回没有弃牌的座位
+func (d *Dealer) Active() []int {
+	active := make([]int, 0, len(d.order))
+	for _, seat := range d.order {
+		if !d.folded[seat] {
+			active = append(active, seat)
+		}
+	}
+	return active
+}
+
+// Hand 座位的手牌
+func (d *Dealer) Hand(seat int) []DealtCard {
+	return append([]DealtCard{}, d.hands[seat]...)
+}
+
+// Board 公共牌
+func (d *Dealer) Board() []Card {
+	return append([]Card{}, d.board...)
+}
+
+// Done 是否已经完成所有发牌步骤
+func (d *Dealer) Done() bool {
+	return d.step >= len(d.procedure.Steps)
+}
+
+func (d *Dealer) isSeated(seat int) bool {
+	for _, s := range d.order {
+		if s == seat {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package card
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDealerHoldem(t *testing.T) {
+	cards, _ := ParseCards("AsKsQsJsTs 9s8s7s6s5s 4s3s2s Ah Kh Qh Jh Th")
+	deck, _ := NewPresetDeck(cards...)
+	dealer, err := NewDealer(deck, HoldemProcedure, []int{7, 2, 4, 9, 0}, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{7, 9, 0, 2, 4}, dealer.Active())
+
+	step, err := dealer.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "preflop", step.Street)
+	assert.Equal(t, []DealtCard{{Card: NewCard("As")}, {Card: NewCard("9s")}}, dealer.Hand(7))
+	assert.Equal(t, []DealtCard{{Card: NewCard("Ts")}, {Card: NewCard("5s")}}, dealer.Hand(4))
+
+	_, err = dealer.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, []Card{NewCard("3s"), NewCard("2s"), NewCard("Ah")}, dealer.Board())
+	dealer.Next()
+	dealer.Next()
+	assert.Equal(t, []Card{NewCard("3s"), NewCard("2s"), NewCard("Ah"), NewCard("Qh"), NewCard("Th")}, dealer.Board())
+	assert.True(t, dealer.Done())
+
+	_, err = dealer.Next()
+	assert.Equal(t, ErrDealingDone, err)
+}
+
+func TestDealerMisdeal(t *testing.T) {
+	deck := NewFiftyTwoCardsDeck()
+	seats := make([]int, 25)
+	for i := range seats {
+		seats[i] = i
+	}
+	dealer, _ := NewDealer(deck, HoldemProcedure, seats, 0)
+	dealer.Next()
+	_, err := dealer.Next()
+	assert.True(t, errors.Is(err, ErrMisdeal))
+
+	dealer, _ = NewDealer(NewFiftyTwoCardsDeck(), HoldemProcedure, []int{1, 2}, 1)
+	dealer.Next()
+	exposed := dealer.Hand(2)[0].Card
+	assert.True(t, errors.Is(dealer.Expose(2, exposed), ErrMisdeal))
+
+	dealer.Next()
+	assert.Nil(t, dealer.Expose(2, exposed))
+	assert.True(t, dealer.Hand(2)[0].Up)
+	assert.NotNil(t, dealer.Expose(2, exposed))
+
+	_, err = NewDealer(deck, HoldemProcedure, []int{1, 1}, 0)
+	assert.NotNil(t, err)
+}
+
+func TestDealerStudFallback(t *testing.T) {
+	dealer, _ := NewDealer(NewFiftyTwoCardsDeck(), StudProcedure, []int{1, 2, 3, 4, 5, 6, 7, 8}, 8)
+	for i := 0; i < 4; i++ {
+		_, err := dealer.Next()
+		assert.Nil(t, err)
+	}
+	hand := dealer.Hand(1)
+	assert.Len(t, hand, 6)
+	assert.False(t, hand[0].Up)
+	assert.False(t, hand[1].Up)
+	assert.True(t, hand[2].Up)
+
+	// 8*6+3 张之后只剩 1 张，第七街改发公共牌
+	step, err := dealer.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, step.Board)
+	assert.Len(t, dealer.Board(), 1)
+	assert.Len(t, dealer.Hand(1), 6)
+}
+
+func TestDealerDraw(t *testing.T) {
+	dealer, _ := NewDealer(NewFiftyTwoCardsDeck(), DrawProcedure, []int{1, 2}, 2)
+	dealer.Next()
+	dealer.Fold(2)
+	assert.Equal(t, []int{1}, dealer.Active())
+
+	hand := dealer.Hand(1)
+	assert.Nil(t, dealer.Discard(1, hand[0].Card, hand[1].Card))
+	assert.NotNil(t, dealer.Discard(1, hand[0].Card))
+	cards, err := dealer.DealTo(1, 2, false)
+	assert.Nil(t, err)
+	assert.Len(t, cards, 2)
+	assert.Len(t, dealer.Hand(1), 5)
+
+	_, err = dealer.DealTo(3, 1, false)
+	assert.NotNil(t, err)
+}
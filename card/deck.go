@@ -1,17 +1,19 @@
 package card
 
 import (
+	"errors"
 	"math/rand"
-	"sync/atomic"
+	"sync"
 	"time"
 )
 
 type (
+	// Deck 牌堆，所有操作都可以并发调用
 	Deck interface {
 		Shuffle()           // 洗牌
 		Deal() (Card, bool) // 发牌
 		Burn() bool         // 烧牌
-		Cut()               // 切牌，要求两堆牌数量不少于10
+		Cut() error         // 切牌，要求两堆牌数量不少于10，只能在发牌前切一次
 		Length() int        // 手牌数量
 	}
 
@@ -21,14 +23,28 @@ type (
 	}
 
 	fiftyTwoCardsDeck struct {
+		mu       sync.Mutex
 		cards    []Card
-		index    int32
-		shuffled int32
-		cutted   int32
+		fixed    []bool // 预设的位置，洗牌和切牌时保持不动
+		next     int    // 下一张要发的牌
+		burned   []Card // 烧掉的牌
+		mucked   []Card // 弃掉的牌
+		standard bool   // 是否由完整的52张牌组成
+		decks    int    // 牌靴中的副数，单副牌为 1
+		cutCard  int    // 切牌卡的位置，0 表示没有切牌卡
+		shuffled bool
+		cutted   bool
 		rand     *rand.Rand
 	}
 )
 
+var (
+	ErrCutAfterDeal  = errors.New("cannot cut after dealing")
+	ErrAlreadyCut    = errors.New("deck already cut")
+	ErrCutPresetDeck = errors.New("cannot cut a preset deck")
+	ErrCutTooFew     = errors.New("too few cards to cut")
+)
+
 var (
 	// standard52CardsDeck 52张牌，没有大小王
 	standard52CardsDeck = []Card{
@@ -52,60 +68,154 @@ var (
 
 func NewFiftyTwoCardsDeck() Deck {
 	deck := &fiftyTwoCardsDeck{
-		cards: make([]Card, len(standard52CardsDeck)),
-		index: -1,
+		cards:    make([]Card, len(standard52CardsDeck)),
+		standard: true,
+		decks:    1,
 	}
 	copy(deck.cards, standard52CardsDeck)
 	return deck
 }
 
+// NewSeededDeck 使用固定随机种子洗牌和切牌的52张牌，种子相同时发牌顺序相同
+func NewSeededDeck(seed int64) Deck {
+	deck := NewFiftyTwoCardsDeck().(*fiftyTwoCardsDeck)
+	deck.rand = rand.New(rand.NewSource(seed))
+	return deck
+}
+
 func (ft *fiftyTwoCardsDeck) Shuffle() {
-	if atomic.CompareAndSwapInt32(&ft.shuffled, 0, 1) {
-		source := rand.NewSource(time.Now().UnixNano())
-		ft.rand = rand.New(source)
-		ft.rand.Shuffle(len(ft.cards), func(i, j int) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.shuffled {
+		return
+	}
+	ft.shuffled = true
+	r := ft.random()
+	if ft.fixed == nil {
+		r.Shuffle(len(ft.cards), func(i, j int) {
 			ft.cards[i], ft.cards[j] = ft.cards[j], ft.cards[i]
 		})
+		return
 	}
+
+	// 只在未固定的位置之间洗牌
+	free := make([]int, 0, len(ft.cards))
+	for i := range ft.cards {
+		if !ft.fixed[i] {
+			free = append(free, i)
+		}
+	}
+	r.Shuffle(len(free), func(i, j int) {
+		ft.cards[free[i]], ft.cards[free[j]] = ft.cards[free[j]], ft.cards[free[i]]
+	})
 }
 
 func (ft *fiftyTwoCardsDeck) Deal() (Card, bool) {
-	index := int(atomic.AddInt32(&ft.index, 1))
-	if index > len(ft.cards)-1 {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.next >= len(ft.cards) {
 		return Card{}, false
 	}
-	return ft.cards[index], true
+	card := ft.cards[ft.next]
+	ft.next++
+	return card, true
 }
 
 func (ft *fiftyTwoCardsDeck) Burn() bool {
-	index := int(atomic.AddInt32(&ft.index, 1))
-	return index <= len(ft.cards)-1
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.next >= len(ft.cards) {
+		return false
+	}
+	ft.burned = append(ft.burned, ft.cards[ft.next])
+	ft.next++
+	return true
 }
 
-func (ft *fiftyTwoCardsDeck) Length() int {
-	current := int(atomic.LoadInt32(&ft.index))
-	if current >= len(ft.cards)-1 {
-		return 0
+// Muck 把已经发出的牌放入弃牌堆
+func (ft *fiftyTwoCardsDeck) Muck(cards ...Card) error {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	for _, card := range cards {
+		if !ft.isDealt(card) {
+			return errors.New("card not dealt: " + card.String())
+		}
+		ft.mucked = append(ft.mucked, card)
 	}
-	return len(ft.cards) - 1 - current
+	return nil
 }
 
-func (ft *fiftyTwoCardsDeck) Cut() {
-	if atomic.CompareAndSwapInt32(&ft.cutted, 0, 1) {
-		p := ft.rand.Int31n(52-10-10) + 10
-		cards := make([]Card, 0, 52)
-		cards = append(cards, ft.cards[p:]...)
-		cards = append(cards, ft.cards[:p]...)
-		ft.cards = cards
+func (ft *fiftyTwoCardsDeck) Length() int {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	return len(ft.cards) - ft.next
+}
+
+func (ft *fiftyTwoCardsDeck) Cut() error {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	switch {
+	case ft.fixed != nil: // 预设牌序的牌堆切牌会破坏预设位置
+		return ErrCutPresetDeck
+	case ft.next > 0:
+		return ErrCutAfterDeal
+	case ft.cutted:
+		return ErrAlreadyCut
+	case len(ft.cards) < 20:
+		return ErrCutTooFew
 	}
+
+	ft.cutted = true
+	p := ft.random().Intn(len(ft.cards)-10-10) + 10
+	cards := make([]Card, 0, len(ft.cards))
+	cards = append(cards, ft.cards[p:]...)
+	cards = append(cards, ft.cards[:p]...)
+	ft.cards = cards
+	return nil
 }
 
+// Range 遍历调用时刻剩余的牌，回调中可以安全地操作牌堆
 func (ft *fiftyTwoCardsDeck) Range(fn func(Card) bool) {
-	next := int(atomic.LoadInt32(&ft.index) + 1)
-	for next < len(ft.cards) {
-		if !fn(ft.cards[next]) {
+	ft.mu.Lock()
+	remaining := make([]Card, len(ft.cards)-ft.next)
+	copy(remaining, ft.cards[ft.next:])
+	ft.mu.Unlock()
+
+	for _, card := range remaining {
+		if !fn(card) {
 			break
 		}
-		next++
 	}
 }
+
+// isDealt 牌已经发出且没有全部被烧掉或弃掉，多副牌时按张数计算，调用方需持有锁
+func (ft *fiftyTwoCardsDeck) isDealt(card Card) bool {
+	var count int
+	for _, c := range ft.cards[:ft.next] {
+		if c == card {
+			count++
+		}
+	}
+	for _, pile := range [][]Card{ft.burned, ft.mucked} {
+		for _, c := range pile {
+			if c == card {
+				count--
+			}
+		}
+	}
+	return count > 0
+}
+
+// random 调用方需持有锁
+func (ft *fiftyTwoCardsDeck) random() *rand.Rand {
+	if ft.rand == nil {
+		ft.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return ft.rand
+}
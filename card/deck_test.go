@@ -2,6 +2,7 @@ package card
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,13 +44,78 @@ func TestDeckShuffle(t *testing.T) {
 	assert.NotEqualValues(t, deck.(*fiftyTwoCardsDeck).cards, standard52CardsDeck)
 }
 
+func TestSeededDeck(t *testing.T) {
+	deal := func(seed int64) []Card {
+		deck := NewSeededDeck(seed)
+		deck.Shuffle()
+		cards := make([]Card, 0, 52)
+		for c, ok := deck.Deal(); ok; c, ok = deck.Deal() {
+			cards = append(cards, c)
+		}
+		return cards
+	}
+
+	assert.Equal(t, deal(42), deal(42))
+	assert.NotEqual(t, deal(42), deal(43))
+	assert.ElementsMatch(t, standard52CardsDeck, deal(42))
+}
+
 func TestDeckCut(t *testing.T) {
 	deck := NewFiftyTwoCardsDeck()
 	deck.Shuffle()
 	fmt.Println(deck.(*fiftyTwoCardsDeck).cards)
-	deck.Cut()
+	assert.Nil(t, deck.Cut())
 	fmt.Println(deck.(*fiftyTwoCardsDeck).cards)
 	assert.Equal(t, deck.Length(), 52)
+	assert.Equal(t, ErrAlreadyCut, deck.Cut())
+}
+
+func TestDeckCutWithoutShuffle(t *testing.T) {
+	deck := NewFiftyTwoCardsDeck()
+	assert.Nil(t, deck.Cut())
+	assert.Equal(t, 52, deck.Length())
+
+	deck = NewFiftyTwoCardsDeck()
+	deck.Deal()
+	assert.Equal(t, ErrCutAfterDeal, deck.Cut())
+}
+
+func TestDeckConcurrent(t *testing.T) {
+	deck := NewFiftyTwoCardsDeck().(RangeableDeck)
+	deck.Shuffle()
+
+	var wg sync.WaitGroup
+	dealt := make(chan Card, 52)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				c, ok := deck.Deal()
+				if !ok {
+					return
+				}
+				dealt <- c
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for deck.Length() > 0 {
+			deck.Range(func(Card) bool { return true })
+			deck.Cut()
+		}
+	}()
+	wg.Wait()
+	close(dealt)
+
+	seen := make(map[Card]bool)
+	for c := range dealt {
+		assert.False(t, seen[c])
+		seen[c] = true
+	}
+	assert.Len(t, seen, 52)
 }
 
 func TestRange(t *testing.T) {
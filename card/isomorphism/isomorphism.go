@@ -0,0 +1,488 @@
+// Package isomorphism 花色同构：把只差花色置换的牌面映射到同一个标准形式，
+// 并按 Waugh 的方法给每一轮的同构类分配连续的下标
+package isomorphism
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openpoker-dev/contrib/card"
+)
+
+type (
+	// Permutation 花色置换，Permutation[原花色] = 标准花色
+	Permutation [numSuits + 1]card.Suit
+
+	// Indexer 按轮次给同构类分配下标，每一轮包括之前所有轮次的牌
+	Indexer struct {
+		rounds  []int
+		configs [][]config // 每一轮的花色配置
+		lookup  []map[string]int
+		offsets [][]uint64 // 每个配置的起始下标，最后一项为总数
+	}
+
+	// config 每个花色每一轮的牌数，花色按 profile 从大到小排列
+	config struct {
+		profiles [numSuits][]int
+		groups   []group
+		size     uint64
+	}
+
+	// group profile 相同的连续花色
+	group struct {
+		start, count int
+		suitSize     uint64 // 一个花色的不同牌点组合数
+		size         uint64 // count 个花色的多重集合数
+	}
+
+	// suitKey 一个花色在排序时使用的 profile 和牌点组合的下标
+	suitKey struct {
+		suit    card.Suit
+		profile []int
+		index   uint64
+	}
+)
+
+const (
+	numSuits = 4
+	numRanks = 13
+)
+
+var (
+	ErrInvalidCards = errors.New("invalid cards")
+	ErrInvalidIndex = errors.New("invalid index")
+
+	// Suits 标准形式中依次使用的花色
+	Suits = [numSuits]card.Suit{card.SuitSpades, card.SuitHearts, card.SuitDiamond, card.SuitClubs}
+)
+
+// NewIndexer rounds 为每一轮发的牌数，例如德州扑克为 2, 3, 1, 1
+func NewIndexer(rounds ...int) (*Indexer, error) {
+	total := 0
+	for _, n := range rounds {
+		if n <= 0 {
+			return nil, fmt.Errorf("%w: %d cards in a round", ErrInvalidCards, n)
+		}
+		total += n
+	}
+	if len(rounds) == 0 || total > numSuits*numRanks {
+		return nil, fmt.Errorf("%w: rounds %v", ErrInvalidCards, rounds)
+	}
+
+	ix := &Indexer{rounds: append([]int(nil), rounds...)}
+	for r := range rounds {
+		configs := enumerate(rounds[:r+1])
+		lookup := make(map[string]int, len(configs))
+		offsets := make([]uint64, len(configs)+1)
+		for i, c := range configs {
+			lookup[profileKey(c.profiles[:])] = i
+			offsets[i+1] = offsets[i] + c.size
+		}
+		ix.configs = append(ix.configs, configs)
+		ix.lookup = append(ix.lookup, lookup)
+		ix.offsets = append(ix.offsets, offsets)
+	}
+	return ix, nil
+}
+
+// NewHoldemIndexer 德州扑克翻牌前、翻牌、转牌和河牌的下标
+func NewHoldemIndexer() *Indexer {
+	ix, _ := NewIndexer(2, 3, 1, 1)
+	return ix
+}
+
+// Rounds 轮次数量
+func (ix *Indexer) Rounds() int {
+	return len(ix.rounds)
+}
+
+// Size 第 round 轮（从0开始）的同构类数量
+func (ix *Indexer) Size(round int) uint64 {
+	if round < 0 || round >= len(ix.rounds) {
+		return 0
+	}
+	offsets := ix.offsets[round]
+	return offsets[len(offsets)-1]
+}
+
+// Index 按轮次给出的牌的下标，给出几轮就是第几轮的下标
+func (ix *Indexer) Index(rounds ...[]card.Card) (uint64, error) {
+	if len(rounds) == 0 || len(rounds) > len(ix.rounds) {
+		return 0, fmt.Errorf("%w: %d rounds", ErrInvalidCards, len(rounds))
+	}
+	for r, cards := range rounds {
+		if len(cards) != ix.rounds[r] {
+			return 0, fmt.Errorf("%w: %d cards in round %d", ErrInvalidCards, len(cards), r)
+		}
+	}
+	keys, err := sortedKeys(rounds)
+	if err != nil {
+		return 0, err
+	}
+
+	round := len(rounds) - 1
+	var profiles [numSuits][]int
+	for i, k := range keys {
+		profiles[i] = k.profile
+	}
+	id := ix.lookup[round][profileKey(profiles[:])]
+	c := ix.configs[round][id]
+
+	var index, radix uint64 = 0, 1
+	for _, g := range c.groups {
+		var indices []uint64
+		for _, k := range keys[g.start : g.start+g.count] {
+			indices = append(indices, k.index)
+		}
+		index += multisetIndex(indices) * radix
+		radix *= g.size
+	}
+	return ix.offsets[round][id] + index, nil
+}
+
+// Unindex 第 round 轮下标为 index 的同构类的标准形式
+func (ix *Indexer) Unindex(round int, index uint64) ([][]card.Card, error) {
+	if round < 0 || round >= len(ix.rounds) || index >= ix.Size(round) {
+		return nil, fmt.Errorf("%w: %d in round %d", ErrInvalidIndex, index, round)
+	}
+	offsets := ix.offsets[round]
+	id := sort.Search(len(offsets)-1, func(i int) bool { return offsets[i+1] > index })
+	c := ix.configs[round][id]
+	index -= offsets[id]
+
+	cards := make([][]card.Card, round+1)
+	for _, g := range c.groups {
+		indices := multisetUnindex(index%g.size, g.count)
+		index /= g.size
+		for j, x := range indices {
+			for r, ranks := range unindexSuit(c.profiles[g.start+j], x) {
+				for _, rank := range ranks {
+					cards[r] = append(cards[r], card.Card{Rank: rank, Suit: Suits[g.start+j]})
+				}
+			}
+		}
+	}
+	for _, cs := range cards {
+		sortCards(cs)
+	}
+	return cards, nil
+}
+
+// Canonicalize 把按轮次给出的牌映射到标准形式，同构的牌面得到相同的结果。
+// 返回的置换把原来的牌映射到标准形式，它的逆把标准形式映射回来
+func Canonicalize(rounds ...[]card.Card) ([][]card.Card, Permutation, error) {
+	keys, err := sortedKeys(rounds)
+	if err != nil {
+		return nil, Permutation{}, err
+	}
+	var p Permutation
+	for i, k := range keys {
+		p[k.suit] = Suits[i]
+	}
+	canonical := make([][]card.Card, len(rounds))
+	for r, cards := range rounds {
+		canonical[r] = p.Apply(cards)
+		sortCards(canonical[r])
+	}
+	return canonical, p, nil
+}
+
+// Apply 置换每张牌的花色，不修改传入的牌
+func (p Permutation) Apply(cards []card.Card) []card.Card {
+	result := make([]card.Card, len(cards))
+	for i, c := range cards {
+		result[i] = card.Card{Rank: c.Rank, Suit: p[c.Suit]}
+	}
+	return result
+}
+
+// Inverse 逆置换
+func (p Permutation) Inverse() Permutation {
+	var inverse Permutation
+	for from, to := range p {
+		inverse[to] = card.Suit(from)
+	}
+	return inverse
+}
+
+// sortedKeys 检查牌并按标准形式的顺序排列四种花色
+func sortedKeys(rounds [][]card.Card) ([]suitKey, error) {
+	var used [numSuits + 1]uint16
+	sets := make([][numSuits + 1]uint16, len(rounds))
+	for r, cards := range rounds {
+		for _, c := range cards {
+			if c.Rank < card.RankTwo || c.Rank > card.RankAce || c.Suit < card.SuitHearts || c.Suit > card.SuitClubs {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidCards, c.ASCII())
+			}
+			bit := uint16(1) << (c.Rank - card.RankTwo)
+			if used[c.Suit]&bit != 0 {
+				return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidCards, c.ASCII())
+			}
+			used[c.Suit] |= bit
+			sets[r][c.Suit] |= bit
+		}
+	}
+
+	keys := make([]suitKey, numSuits)
+	for i, s := range Suits {
+		k := suitKey{suit: s, profile: make([]int, len(rounds))}
+		var seen uint16
+		var radix uint64 = 1
+		for r := range rounds {
+			set := sets[r][s]
+			k.profile[r] = popcount(set)
+			k.index += subsetIndex(compress(set, seen)) * radix
+			radix *= binomial(numRanks-popcount(seen), k.profile[r])
+			seen |= set
+		}
+		keys[i] = k
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if c := compareProfiles(keys[i].profile, keys[j].profile); c != 0 {
+			return c > 0
+		}
+		return keys[i].index < keys[j].index
+	})
+	return keys, nil
+}
+
+// enumerate 所有花色配置，四个花色的 profile 从大到小排列
+func enumerate(rounds []int) []config {
+	var profiles [][]int
+	var build func(profile []int, total int)
+	build = func(profile []int, total int) {
+		if len(profile) == len(rounds) {
+			profiles = append(profiles, append([]int(nil), profile...))
+			return
+		}
+		for n := 0; n <= rounds[len(profile)] && total+n <= numRanks; n++ {
+			build(append(profile, n), total+n)
+		}
+	}
+	build(nil, 0)
+	sort.Slice(profiles, func(i, j int) bool { return compareProfiles(profiles[i], profiles[j]) > 0 })
+
+	var configs []config
+	var chosen [numSuits][]int
+	var choose func(suit, from int, remaining []int)
+	choose = func(suit, from int, remaining []int) {
+		if suit == numSuits {
+			for _, n := range remaining {
+				if n != 0 {
+					return
+				}
+			}
+			configs = append(configs, newConfig(chosen))
+			return
+		}
+		for i := from; i < len(profiles); i++ {
+			next := make([]int, len(remaining))
+			ok := true
+			for r, n := range profiles[i] {
+				if next[r] = remaining[r] - n; next[r] < 0 {
+					ok = false
+				}
+			}
+			if ok {
+				chosen[suit] = profiles[i]
+				choose(suit+1, i, next)
+			}
+		}
+	}
+	choose(0, 0, rounds)
+	return configs
+}
+
+func newConfig(profiles [numSuits][]int) config {
+	c := config{profiles: profiles, size: 1}
+	for start := 0; start < numSuits; {
+		count := 1
+		for start+count < numSuits && compareProfiles(profiles[start], profiles[start+count]) == 0 {
+			count++
+		}
+		var suitSize uint64 = 1
+		total := 0
+		for _, n := range profiles[start] {
+			suitSize *= binomial(numRanks-total, n)
+			total += n
+		}
+		g := group{start: start, count: count, suitSize: suitSize, size: binomial64(suitSize+uint64(count)-1, count)}
+		c.groups = append(c.groups, g)
+		c.size *= g.size
+		start += count
+	}
+	return c
+}
+
+// unindexSuit 一个花色每一轮的牌点
+func unindexSuit(profile []int, index uint64) [][]card.Rank {
+	ranks := make([][]card.Rank, len(profile))
+	var seen uint16
+	for r, n := range profile {
+		radix := binomial(numRanks-popcount(seen), n)
+		set := expand(subsetUnindex(index%radix, n), seen)
+		index /= radix
+		for b := numRanks - 1; b >= 0; b-- {
+			if set&(1<<b) != 0 {
+				ranks[r] = append(ranks[r], card.RankTwo+card.Rank(b))
+			}
+		}
+		seen |= set
+	}
+	return ranks
+}
+
+// subsetIndex 集合在同样大小的集合中按 colex 顺序的下标
+func subsetIndex(set uint16) uint64 {
+	var index uint64
+	i := 0
+	for b := 0; b < numRanks; b++ {
+		if set&(1<<b) != 0 {
+			i++
+			index += binomial(b, i)
+		}
+	}
+	return index
+}
+
+func subsetUnindex(index uint64, n int) uint16 {
+	var set uint16
+	for i := n; i > 0; i-- {
+		b := i - 1
+		for binomial(b+1, i) <= index {
+			b++
+		}
+		set |= 1 << b
+		index -= binomial(b, i)
+	}
+	return set
+}
+
+// multisetIndex 从小到大排列的多重集合的下标
+func multisetIndex(sorted []uint64) uint64 {
+	var index uint64
+	for j, x := range sorted {
+		index += binomial64(x+uint64(j), j+1)
+	}
+	return index
+}
+
+func multisetUnindex(index uint64, count int) []uint64 {
+	sorted := make([]uint64, count)
+	for j := count - 1; j >= 0; j-- {
+		// 最大的 x 使 C(x+j, j+1) <= index
+		lo, hi := uint64(0), uint64(1)
+		for binomial64(hi+uint64(j), j+1) <= index {
+			hi *= 2
+		}
+		for lo+1 < hi {
+			mid := (lo + hi) / 2
+			if binomial64(mid+uint64(j), j+1) <= index {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		sorted[j] = lo
+		index -= binomial64(lo+uint64(j), j+1)
+	}
+	return sorted
+}
+
+// compress 去掉已经用过的牌点后重新编号
+func compress(set, seen uint16) uint16 {
+	var result uint16
+	i := 0
+	for b := 0; b < numRanks; b++ {
+		if seen&(1<<b) != 0 {
+			continue
+		}
+		if set&(1<<b) != 0 {
+			result |= 1 << i
+		}
+		i++
+	}
+	return result
+}
+
+// expand compress 的逆
+func expand(set, seen uint16) uint16 {
+	var result uint16
+	i := 0
+	for b := 0; b < numRanks; b++ {
+		if seen&(1<<b) != 0 {
+			continue
+		}
+		if set&(1<<i) != 0 {
+			result |= 1 << b
+		}
+		i++
+	}
+	return result
+}
+
+func binomial(n, k int) uint64 {
+	if n < 0 || k < 0 || k > n {
+		return 0
+	}
+	return binomial64(uint64(n), k)
+}
+
+func binomial64(n uint64, k int) uint64 {
+	if uint64(k) > n {
+		return 0
+	}
+	var result uint64 = 1
+	for i := 0; i < k; i++ {
+		result = result * (n - uint64(i)) / uint64(i+1)
+	}
+	return result
+}
+
+func popcount(set uint16) int {
+	n := 0
+	for ; set != 0; set &= set - 1 {
+		n++
+	}
+	return n
+}
+
+// compareProfiles 按轮次的字典序比较
+func compareProfiles(a, b []int) int {
+	for r := range a {
+		if a[r] != b[r] {
+			return a[r] - b[r]
+		}
+	}
+	return 0
+}
+
+func profileKey(profiles [][]int) string {
+	var b strings.Builder
+	for _, p := range profiles {
+		for _, n := range p {
+			b.WriteByte(byte(n))
+		}
+	}
+	return b.String()
+}
+
+// sortCards 按牌点从大到小，再按 Suits 的顺序
+func sortCards(cards []card.Card) {
+	order := func(s card.Suit) int {
+		for i, x := range Suits {
+			if x == s {
+				return i
+			}
+		}
+		return numSuits
+	}
+	sort.Slice(cards, func(i, j int) bool {
+		if cards[i].Rank != cards[j].Rank {
+			return cards[i].Rank > cards[j].Rank
+		}
+		return order(cards[i].Suit) < order(cards[j].Suit)
+	})
+}
@@ -0,0 +1,163 @@
+package isomorphism
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func cards(t *testing.T, s string) []card.Card {
+	c, err := card.ParseCards(s)
+	assert.Nil(t, err)
+	return c
+}
+
+func TestHoldemSize(t *testing.T) {
+	ix := NewHoldemIndexer()
+	assert.Equal(t, 4, ix.Rounds())
+	assert.Equal(t, uint64(169), ix.Size(0))
+	assert.Equal(t, uint64(1286792), ix.Size(1))
+	assert.Equal(t, uint64(55190538), ix.Size(2))
+	assert.Equal(t, uint64(2428287420), ix.Size(3))
+	assert.Equal(t, uint64(0), ix.Size(4))
+
+	// 公共牌作为一轮时不区分翻牌和转牌
+	turn, err := NewIndexer(2, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(13960050), turn.Size(1))
+	river, err := NewIndexer(2, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(123156254), river.Size(1))
+
+	_, err = NewIndexer()
+	assert.ErrorIs(t, err, ErrInvalidCards)
+	_, err = NewIndexer(2, 0)
+	assert.ErrorIs(t, err, ErrInvalidCards)
+	_, err = NewIndexer(50, 3)
+	assert.ErrorIs(t, err, ErrInvalidCards)
+}
+
+func TestPreflop(t *testing.T) {
+	ix := NewHoldemIndexer()
+	names := map[string]bool{}
+	for i := uint64(0); i < ix.Size(0); i++ {
+		hole, err := ix.Unindex(0, i)
+		assert.Nil(t, err)
+		index, err := ix.Index(hole[0])
+		assert.Nil(t, err)
+		assert.Equal(t, i, index)
+
+		name := hole[0][0].ASCII()[:1] + hole[0][1].ASCII()[:1]
+		if hole[0][0].Suit == hole[0][1].Suit {
+			name += "s"
+		}
+		names[name] = true
+	}
+	assert.Len(t, names, 169)
+
+	a, err := ix.Index(cards(t, "AsKs"))
+	assert.Nil(t, err)
+	b, err := ix.Index(cards(t, "KhAh"))
+	assert.Nil(t, err)
+	c, err := ix.Index(cards(t, "AsKh"))
+	assert.Nil(t, err)
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestRoundTrip(t *testing.T) {
+	ix := NewHoldemIndexer()
+	rng := rand.New(rand.NewSource(1))
+	for round := 1; round < ix.Rounds(); round++ {
+		for n := 0; n < 2000; n++ {
+			index := uint64(rng.Int63n(int64(ix.Size(round))))
+			canonical, err := ix.Unindex(round, index)
+			assert.Nil(t, err)
+			again, err := ix.Index(canonical...)
+			assert.Nil(t, err)
+			assert.Equal(t, index, again)
+		}
+	}
+	_, err := ix.Unindex(1, ix.Size(1))
+	assert.ErrorIs(t, err, ErrInvalidIndex)
+	_, err = ix.Unindex(4, 0)
+	assert.ErrorIs(t, err, ErrInvalidIndex)
+}
+
+func TestIsomorphic(t *testing.T) {
+	ix := NewHoldemIndexer()
+	a, err := ix.Index(cards(t, "AsKs"), cards(t, "7s8s9d"))
+	assert.Nil(t, err)
+	b, err := ix.Index(cards(t, "KhAh"), cards(t, "9c8h7h"))
+	assert.Nil(t, err)
+	assert.Equal(t, a, b)
+	c, err := ix.Index(cards(t, "AsKs"), cards(t, "7s8s9s"))
+	assert.Nil(t, err)
+	assert.NotEqual(t, a, c)
+
+	// 随机的花色置换不改变下标和标准形式
+	rng := rand.New(rand.NewSource(2))
+	for n := 0; n < 500; n++ {
+		deck := rng.Perm(52)
+		var rounds [][]card.Card
+		for _, count := range []int{2, 3, 1, 1} {
+			var round []card.Card
+			for _, c := range deck[:count] {
+				round = append(round, card.Card{Rank: card.RankTwo + card.Rank(c%13), Suit: Suits[c/13]})
+			}
+			deck = deck[count:]
+			rounds = append(rounds, round)
+		}
+		var p Permutation
+		for i, j := range rng.Perm(numSuits) {
+			p[Suits[i]] = Suits[j]
+		}
+		permuted := make([][]card.Card, len(rounds))
+		for r := range rounds {
+			permuted[r] = p.Apply(rounds[r])
+		}
+
+		x, err := ix.Index(rounds...)
+		assert.Nil(t, err)
+		y, err := ix.Index(permuted...)
+		assert.Nil(t, err)
+		assert.Equal(t, x, y)
+
+		canonical, _, err := Canonicalize(rounds...)
+		assert.Nil(t, err)
+		unindexed, err := ix.Unindex(3, x)
+		assert.Nil(t, err)
+		assert.Equal(t, unindexed, canonical)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	canonical, p, err := Canonicalize(cards(t, "AsKs"), cards(t, "7s8s9d"))
+	assert.Nil(t, err)
+	other, q, err := Canonicalize(cards(t, "AhKh"), cards(t, "7h8h9c"))
+	assert.Nil(t, err)
+	assert.Equal(t, canonical, other)
+	assert.Equal(t, cards(t, "AsKs"), canonical[0])
+	assert.Equal(t, cards(t, "9h8s7s"), canonical[1])
+
+	// 逆置换映射回原来的牌
+	assert.ElementsMatch(t, cards(t, "AhKh"), q.Inverse().Apply(other[0]))
+	assert.ElementsMatch(t, cards(t, "7h8h9c"), q.Inverse().Apply(other[1]))
+	assert.ElementsMatch(t, cards(t, "7s8s9d"), p.Inverse().Apply(canonical[1]))
+	for _, s := range Suits {
+		assert.Equal(t, s, p.Inverse()[p[s]])
+	}
+
+	_, _, err = Canonicalize(cards(t, "AsAs"))
+	assert.ErrorIs(t, err, ErrInvalidCards)
+	_, _, err = Canonicalize([]card.Card{{}})
+	assert.ErrorIs(t, err, ErrInvalidCards)
+
+	ix := NewHoldemIndexer()
+	_, err = ix.Index(cards(t, "AsKs"), cards(t, "7s8s"))
+	assert.ErrorIs(t, err, ErrInvalidCards)
+	_, err = ix.Index()
+	assert.ErrorIs(t, err, ErrInvalidCards)
+}
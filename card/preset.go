@@ -0,0 +1,89 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NewPresetDeck 按给定顺序构造牌堆，用于测试和牌局重放
+// 牌的顺序完全确定，Shuffle 不会改变牌序，Cut 返回 ErrCutPresetDeck
+func NewPresetDeck(cards ...Card) (RangeableDeck, error) {
+	if len(cards) == 0 {
+		return nil, errors.New("empty preset deck")
+	}
+	if err := checkPresetCards(cards); err != nil {
+		return nil, err
+	}
+
+	deck := &fiftyTwoCardsDeck{
+		cards:    make([]Card, len(cards)),
+		fixed:    make([]bool, len(cards)),
+		standard: len(cards) == len(standard52CardsDeck),
+		decks:    1,
+	}
+	copy(deck.cards, cards)
+	for i := range deck.fixed {
+		deck.fixed[i] = true
+	}
+	return deck, nil
+}
+
+// NewPartialPresetDeck 固定部分位置的 52 张牌堆，key 为发牌顺序中的位置（从 0 开始，包括烧牌）
+// 其余的牌在 Shuffle 时随机排列，Cut 返回 ErrCutPresetDeck
+func NewPartialPresetDeck(fixed map[int]Card) (RangeableDeck, error) {
+	deck := &fiftyTwoCardsDeck{
+		cards:    make([]Card, len(standard52CardsDeck)),
+		fixed:    make([]bool, len(standard52CardsDeck)),
+		standard: true,
+		decks:    1,
+	}
+
+	used := make(map[Card]bool, len(fixed))
+	for pos, card := range fixed {
+		if pos < 0 || pos >= len(deck.cards) {
+			return nil, fmt.Errorf("preset position out of range: %d", pos)
+		}
+		if !isStandardCard(card) {
+			return nil, fmt.Errorf("bad preset card at %d: %v", pos, card)
+		}
+		if used[card] {
+			return nil, errors.New("duplicate preset card: " + card.String())
+		}
+		used[card] = true
+		deck.cards[pos] = card
+		deck.fixed[pos] = true
+	}
+
+	// 剩余的牌按标准顺序填入空位
+	next := 0
+	for _, card := range standard52CardsDeck {
+		if used[card] {
+			continue
+		}
+		for deck.fixed[next] {
+			next++
+		}
+		deck.cards[next] = card
+		next++
+	}
+	return deck, nil
+}
+
+func checkPresetCards(cards []Card) error {
+	seen := make(map[Card]bool, len(cards))
+	for i, card := range cards {
+		if !isStandardCard(card) {
+			return fmt.Errorf("bad preset card at %d: %v", i, card)
+		}
+		if seen[card] {
+			return errors.New("duplicate preset card: " + card.String())
+		}
+		seen[card] = true
+	}
+	return nil
+}
+
+func isStandardCard(card Card) bool {
+	return card.Rank >= RankTwo && card.Rank <= RankAce &&
+		card.Suit >= SuitHearts && card.Suit <= SuitClubs
+}
@@ -0,0 +1,68 @@
+package card
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresetDeck(t *testing.T) {
+	cards, err := ParseCards("AsKd QhQc 2c7d9h")
+	assert.Nil(t, err)
+
+	deck, err := NewPresetDeck(cards...)
+	assert.Nil(t, err)
+	deck.Shuffle()
+	assert.Equal(t, ErrCutPresetDeck, deck.Cut())
+	assert.Equal(t, 7, deck.Length())
+
+	c, ok := deck.Deal()
+	assert.True(t, ok)
+	assert.Equal(t, NewCard("As"), c)
+	assert.True(t, deck.Burn())
+
+	var rest []Card
+	deck.Range(func(c Card) bool {
+		rest = append(rest, c)
+		return true
+	})
+	assert.Equal(t, cards[2:], rest)
+
+	_, err = NewPresetDeck(NewCard("As"), NewCard("As"))
+	assert.NotNil(t, err)
+	_, err = NewPresetDeck()
+	assert.NotNil(t, err)
+}
+
+func TestPartialPresetDeck(t *testing.T) {
+	deck, err := NewPartialPresetDeck(map[int]Card{
+		0:  NewCard("As"),
+		2:  NewCard("Ks"),
+		51: NewCard("2c"),
+	})
+	assert.Nil(t, err)
+	deck.Shuffle()
+	assert.Equal(t, ErrCutPresetDeck, deck.Cut())
+	assert.Equal(t, 52, deck.Length())
+
+	seen := make(map[Card]bool)
+	for i := 0; i < 52; i++ {
+		c, ok := deck.Deal()
+		assert.True(t, ok)
+		switch i {
+		case 0:
+			assert.Equal(t, NewCard("As"), c)
+		case 2:
+			assert.Equal(t, NewCard("Ks"), c)
+		case 51:
+			assert.Equal(t, NewCard("2c"), c)
+		}
+		seen[c] = true
+	}
+	assert.Len(t, seen, 52)
+
+	_, err = NewPartialPresetDeck(map[int]Card{0: NewCard("As"), 1: NewCard("As")})
+	assert.NotNil(t, err)
+	_, err = NewPartialPresetDeck(map[int]Card{52: NewCard("As")})
+	assert.NotNil(t, err)
+}
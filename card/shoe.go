@@ -0,0 +1,56 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+	// ShoeDeck 由多副牌组成的牌靴
+	ShoeDeck interface {
+		SnapshotDeck
+		Decks() int           // 牌的副数
+		CutCardReached() bool // 是否已经发到切牌卡
+	}
+
+	shoe struct {
+		*fiftyTwoCardsDeck
+	}
+)
+
+var (
+	_ ShoeDeck = (*shoe)(nil)
+)
+
+// NewShoe 由 decks 副 52 张牌组成的牌靴
+// penetration 为切牌卡的位置占总牌数的比例，取值 (0, 1]，为 0 时不放切牌卡
+func NewShoe(decks int, penetration float64) (ShoeDeck, error) {
+	if decks < 1 {
+		return nil, fmt.Errorf("bad number of decks: %d", decks)
+	}
+	if penetration < 0 || penetration > 1 {
+		return nil, errors.New("penetration out of range")
+	}
+
+	deck := &fiftyTwoCardsDeck{
+		cards:    make([]Card, 0, decks*len(standard52CardsDeck)),
+		standard: true,
+		decks:    decks,
+	}
+	for i := 0; i < decks; i++ {
+		deck.cards = append(deck.cards, standard52CardsDeck...)
+	}
+	deck.cutCard = int(float64(len(deck.cards)) * penetration)
+	return &shoe{fiftyTwoCardsDeck: deck}, nil
+}
+
+func (s *shoe) Decks() int {
+	return s.decks
+}
+
+func (s *shoe) CutCardReached() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cutCard > 0 && s.next >= s.cutCard
+}
@@ -0,0 +1,63 @@
+package card
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShoe(t *testing.T) {
+	shoe, err := NewShoe(6, 0.75)
+	assert.Nil(t, err)
+	shoe.Shuffle()
+	assert.Nil(t, shoe.Cut())
+	assert.Equal(t, 6, shoe.Decks())
+	assert.Equal(t, 312, shoe.Length())
+
+	counts := make(map[Card]int)
+	for i := 0; i < 312; i++ {
+		c, ok := shoe.Deal()
+		assert.True(t, ok)
+		counts[c]++
+		assert.Equal(t, i+1 >= 234, shoe.CutCardReached())
+	}
+	assert.Len(t, counts, 52)
+	for _, n := range counts {
+		assert.Equal(t, 6, n)
+	}
+
+	_, err = NewShoe(0, 0)
+	assert.NotNil(t, err)
+	_, err = NewShoe(2, 1.5)
+	assert.NotNil(t, err)
+}
+
+func TestShoeSnapshot(t *testing.T) {
+	shoe, _ := NewShoe(2, 0.5)
+	shoe.Shuffle()
+	shoe.Burn()
+	c, _ := shoe.Deal()
+	assert.Nil(t, shoe.Muck(c))
+
+	data, err := shoe.Snapshot().MarshalBinary()
+	assert.Nil(t, err)
+	var snapshot Snapshot
+	assert.Nil(t, snapshot.UnmarshalBinary(data))
+
+	restored, err := Restore(snapshot)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, restored.(ShoeDeck).Decks())
+	assert.Equal(t, shoe.Length(), restored.Length())
+
+	// 同一张牌出现三次
+	tripled := snapshot
+	tripled.Cards = append([]Card{}, snapshot.Cards...)
+	for i, replaced := 1, 0; replaced < 2; i++ {
+		if tripled.Cards[i] != tripled.Cards[0] {
+			tripled.Cards[i] = tripled.Cards[0]
+			replaced++
+		}
+	}
+	_, err = Restore(tripled)
+	assert.NotNil(t, err)
+}
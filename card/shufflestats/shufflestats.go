@@ -0,0 +1,342 @@
+// Package shufflestats 洗牌均匀性的统计检验
+package shufflestats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/openpoker-dev/contrib/card"
+)
+
+type (
+	// Config 检验参数
+	Config struct {
+		Iterations      int     // 洗牌次数
+		Alpha           float64 // 显著性水平，默认 0.001
+		PermutationSize int     // 排列检验使用的牌数，默认 5
+	}
+
+	// TestResult 单项卡方检验的结果
+	TestResult struct {
+		Name             string  `json:"name"`
+		Statistic        float64 `json:"statistic"`
+		DegreesOfFreedom int     `json:"degrees_of_freedom"`
+		PValue           float64 `json:"p_value"`
+		Passed           bool    `json:"passed"`
+		Skipped          string  `json:"skipped,omitempty"` // 跳过的原因
+	}
+
+	// Report 检验报告
+	Report struct {
+		Iterations int          `json:"iterations"`
+		DeckSize   int          `json:"deck_size"`
+		Alpha      float64      `json:"alpha"`
+		Tests      []TestResult `json:"tests"`
+		Passed     bool         `json:"passed"`
+	}
+
+	// Factory 每次返回一副新的、未洗过的牌
+	Factory func() card.Deck
+)
+
+const (
+	// 检验项的名字
+	PositionFrequency = "position-frequency"
+	PairAdjacency     = "pair-adjacency"
+	PermutationRank   = "permutation-rank"
+	CutPoint          = "cut-point"
+
+	// minCutSize 切牌时每堆至少的牌数，与 card.Deck.Cut 一致
+	minCutSize = 10
+)
+
+// Run 对 factory 产生的牌堆反复洗牌，进行位置频率、相邻对、排列序号和切牌位置的卡方检验
+func Run(factory Factory, cfg Config) (*Report, error) {
+	if cfg.Iterations <= 0 {
+		return nil, errors.New("iterations must be positive")
+	}
+	if cfg.Alpha <= 0 {
+		cfg.Alpha = 0.001
+	}
+	if cfg.PermutationSize <= 0 {
+		cfg.PermutationSize = 5
+	}
+
+	reference := dealAll(factory())
+	size := len(reference)
+	if size < 2 {
+		return nil, errors.New("deck too small")
+	}
+	if cfg.PermutationSize > size || cfg.PermutationSize > 8 {
+		return nil, fmt.Errorf("bad permutation size: %d", cfg.PermutationSize)
+	}
+	index := make(map[card.Card]int, size)
+	for i, c := range reference {
+		if _, exists := index[c]; exists {
+			return nil, errors.New("duplicate card in deck: " + c.ASCII())
+		}
+		index[c] = i
+	}
+
+	positions := make([][]int, size) // positions[牌][位置]
+	for i := range positions {
+		positions[i] = make([]int, size)
+	}
+	adjacency := make([]int, size-1)
+	permutations := make([]int, factorial(cfg.PermutationSize))
+	order := make([]int, size)
+	where := make([]int, size)
+	for n := 0; n < cfg.Iterations; n++ {
+		deck := factory()
+		deck.Shuffle()
+		cards := dealAll(deck)
+		if len(cards) != size {
+			return nil, fmt.Errorf("deck size changed: %d", len(cards))
+		}
+		for pos, c := range cards {
+			ref, exists := index[c]
+			if !exists {
+				return nil, errors.New("unknown card in deck: " + c.ASCII())
+			}
+			order[pos] = ref
+			where[ref] = pos
+			positions[ref][pos]++
+		}
+		for ref := 0; ref < size-1; ref++ {
+			if d := where[ref] - where[ref+1]; d == 1 || d == -1 {
+				adjacency[ref]++
+			}
+		}
+		permutations[permutationRank(order, cfg.PermutationSize)]++
+	}
+
+	report := &Report{
+		Iterations: cfg.Iterations,
+		DeckSize:   size,
+		Alpha:      cfg.Alpha,
+		Tests: []TestResult{
+			positionFrequency(positions, cfg.Iterations),
+			pairAdjacency(adjacency, cfg.Iterations, size),
+			uniform(PermutationRank, permutations),
+			cutPoint(factory, index, cfg.Iterations),
+		},
+		Passed: true,
+	}
+	for i := range report.Tests {
+		test := &report.Tests[i]
+		if test.Skipped != "" {
+			test.Passed = true
+			continue
+		}
+		test.PValue = chiSquareSurvival(test.Statistic, test.DegreesOfFreedom)
+		test.Passed = test.PValue >= cfg.Alpha
+		report.Passed = report.Passed && test.Passed
+	}
+	return report, nil
+}
+
+// WriteJSON 输出机器可读的报告
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+	verdict := "PASS"
+	if !r.Passed {
+		verdict = "FAIL"
+	}
+	fmt.Fprintf(&b, "%s: %d shuffles of %d cards, alpha=%g\n", verdict, r.Iterations, r.DeckSize, r.Alpha)
+	for _, test := range r.Tests {
+		if test.Skipped != "" {
+			fmt.Fprintf(&b, "  SKIP %-18s %s\n", test.Name, test.Skipped)
+			continue
+		}
+		verdict = "PASS"
+		if !test.Passed {
+			verdict = "FAIL"
+		}
+		fmt.Fprintf(&b, "  %s %-18s chi2=%.2f df=%d p=%.4f\n", verdict, test.Name, test.Statistic, test.DegreesOfFreedom, test.PValue)
+	}
+	return b.String()
+}
+
+// positionFrequency 每张牌出现在每个位置的次数应当相同
+func positionFrequency(positions [][]int, iterations int) TestResult {
+	size := len(positions)
+	expected := float64(iterations) / float64(size)
+	var statistic float64
+	for _, row := range positions {
+		for _, observed := range row {
+			d := float64(observed) - expected
+			statistic += d * d / expected
+		}
+	}
+	return TestResult{
+		Name:             PositionFrequency,
+		Statistic:        statistic,
+		DegreesOfFreedom: (size - 1) * (size - 1),
+	}
+}
+
+// pairAdjacency 洗牌前相邻的两张牌，洗牌后仍然相邻的概率为 2/n
+func pairAdjacency(adjacency []int, iterations, size int) TestResult {
+	p := 2 / float64(size)
+	expected := float64(iterations) * p
+	var statistic float64
+	for _, observed := range adjacency {
+		d := float64(observed) - expected
+		statistic += d * d / (expected * (1 - p))
+	}
+	return TestResult{
+		Name:             PairAdjacency,
+		Statistic:        statistic,
+		DegreesOfFreedom: len(adjacency),
+	}
+}
+
+// cutPoint 未洗的牌切牌后，切牌位置应当在 [10, n-10] 之间均匀分布
+func cutPoint(factory Factory, index map[card.Card]int, iterations int) TestResult {
+	size := len(index)
+	if size < 2*minCutSize+1 {
+		return TestResult{Name: CutPoint, Skipped: "deck too small to cut"}
+	}
+
+	counts := make([]int, size-2*minCutSize)
+	for n := 0; n < iterations; n++ {
+		deck := factory()
+		if err := deck.Cut(); err != nil {
+			return TestResult{Name: CutPoint, Skipped: err.Error()}
+		}
+		top, ok := deck.Deal()
+		if !ok {
+			return TestResult{Name: CutPoint, Skipped: "empty deck"}
+		}
+		p := index[top] - minCutSize
+		if p < 0 || p >= len(counts) {
+			return TestResult{Name: CutPoint, Statistic: math.Inf(1), DegreesOfFreedom: len(counts) - 1}
+		}
+		counts[p]++
+	}
+	return uniform(CutPoint, counts)
+}
+
+func uniform(name string, counts []int) TestResult {
+	var total int
+	for _, c := range counts {
+		total += c
+	}
+	expected := float64(total) / float64(len(counts))
+	var statistic float64
+	for _, observed := range counts {
+		d := float64(observed) - expected
+		statistic += d * d / expected
+	}
+	return TestResult{Name: name, Statistic: statistic, DegreesOfFreedom: len(counts) - 1}
+}
+
+func dealAll(deck card.Deck) []card.Card {
+	cards := make([]card.Card, 0, deck.Length())
+	for {
+		c, ok := deck.Deal()
+		if !ok {
+			return cards
+		}
+		cards = append(cards, c)
+	}
+}
+
+// permutationRank 洗牌前前 k 张牌在洗牌后的相对顺序，以 Lehmer 码计算序号
+func permutationRank(order []int, k int) int {
+	relative := make([]int, 0, k)
+	for _, ref := range order {
+		if ref < k {
+			relative = append(relative, ref)
+		}
+	}
+
+	var rank int
+	for i := 0; i < k; i++ {
+		smaller := 0
+		for j := i + 1; j < k; j++ {
+			if relative[j] < relative[i] {
+				smaller++
+			}
+		}
+		rank += smaller * factorial(k-1-i)
+	}
+	return rank
+}
+
+func factorial(n int) int {
+	f := 1
+	for i := 2; i <= n; i++ {
+		f *= i
+	}
+	return f
+}
+
+// chiSquareSurvival 卡方分布的右尾概率 P(X >= x)
+func chiSquareSurvival(x float64, df int) float64 {
+	if math.IsInf(x, 1) {
+		return 0
+	}
+	if x <= 0 || df <= 0 {
+		return 1
+	}
+	return regularizedGammaQ(float64(df)/2, x/2)
+}
+
+// regularizedGammaQ 正则化上不完全伽马函数，参考 Numerical Recipes gammq
+func regularizedGammaQ(a, x float64) float64 {
+	const (
+		maxIterations = 1000
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+
+	if x < a+1 { // 级数展开
+		sum := 1 / a
+		term := sum
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return 1 - sum*math.Exp(-x+a*math.Log(x)-lgamma)
+	}
+
+	// 连分式展开
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return math.Exp(-x+a*math.Log(x)-lgamma) * h
+}
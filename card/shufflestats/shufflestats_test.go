@@ -0,0 +1,62 @@
+package shufflestats
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandardDeck(t *testing.T) {
+	report, err := Run(card.NewFiftyTwoCardsDeck, Config{Iterations: 3000, Alpha: 1e-6})
+	assert.Nil(t, err)
+	t.Log(report)
+	assert.True(t, report.Passed)
+	assert.Len(t, report.Tests, 4)
+	for _, test := range report.Tests {
+		assert.Empty(t, test.Skipped)
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, report.WriteJSON(&buf))
+	var decoded Report
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report.Tests[0].Name, decoded.Tests[0].Name)
+}
+
+func TestBiasedDeck(t *testing.T) {
+	// 只洗后半部分的牌
+	factory := func() card.Deck {
+		fixed := make(map[int]card.Card)
+		cards, _ := card.ParseCards("AsAhAdAcKsKhKdKcQsQhQdQcJsJhJdJcTsThTdTc9s9h9d9c8s8h")
+		for i, c := range cards {
+			fixed[i] = c
+		}
+		deck, _ := card.NewPartialPresetDeck(fixed)
+		return deck
+	}
+	report, err := Run(factory, Config{Iterations: 1000})
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+	assert.False(t, report.Tests[0].Passed)
+	assert.NotEmpty(t, report.Tests[3].Skipped)
+
+	_, err = Run(factory, Config{})
+	assert.NotNil(t, err)
+}
+
+func TestChiSquareSurvival(t *testing.T) {
+	assert.InDelta(t, 0.05, chiSquareSurvival(3.841, 1), 1e-3)
+	assert.InDelta(t, 0.05, chiSquareSurvival(18.307, 10), 1e-3)
+	assert.InDelta(t, 0.01, chiSquareSurvival(135.807, 100), 1e-3)
+	assert.Equal(t, 1.0, chiSquareSurvival(0, 3))
+	assert.Equal(t, 0.0, chiSquareSurvival(math.Inf(1), 3))
+}
+
+func TestPermutationRank(t *testing.T) {
+	assert.Equal(t, 0, permutationRank([]int{0, 5, 1, 2}, 3))
+	assert.Equal(t, 5, permutationRank([]int{2, 1, 7, 0}, 3))
+}
@@ -0,0 +1,268 @@
+package card
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+type (
+	// SnapshotDeck 可以保存和恢复的牌堆，用于牌局的崩溃恢复
+	SnapshotDeck interface {
+		RangeableDeck
+		Muck(...Card) error // 弃牌
+		Snapshot() Snapshot // 保存牌堆状态
+	}
+
+	// Snapshot 牌堆某一时刻的完整状态
+	Snapshot struct {
+		Version  int    `json:"version"`
+		Cards    []Card `json:"cards"`           // 完整的牌序，包括已经发出的牌
+		Position int    `json:"position"`        // 已经发出（包括烧牌）的牌数
+		Burned   []Card `json:"burned"`          // 烧牌堆
+		Mucked   []Card `json:"mucked"`          // 弃牌堆
+		Fixed    []int  `json:"fixed,omitempty"` // 预设牌序的位置
+		Standard bool   `json:"standard"`        // 是否由完整的52张牌组成
+		Decks    int    `json:"decks"`           // 牌的副数
+		CutCard  int    `json:"cut_card"`        // 切牌卡的位置，0 表示没有切牌卡
+		Shuffled bool   `json:"shuffled"`
+		Cut      bool   `json:"cut"`
+	}
+)
+
+const (
+	SnapshotVersion = 1
+
+	snapshotMagic = "OPDK"
+)
+
+const (
+	snapshotFlagStandard = 1 << iota
+	snapshotFlagShuffled
+	snapshotFlagCut
+)
+
+var (
+	_ SnapshotDeck = (*fiftyTwoCardsDeck)(nil)
+
+	ErrBadSnapshot = errors.New("bad deck snapshot")
+)
+
+func (ft *fiftyTwoCardsDeck) Snapshot() Snapshot {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	s := Snapshot{
+		Version:  SnapshotVersion,
+		Cards:    append([]Card{}, ft.cards...),
+		Position: ft.next,
+		Burned:   append([]Card{}, ft.burned...),
+		Mucked:   append([]Card{}, ft.mucked...),
+		Standard: ft.standard,
+		Decks:    ft.decks,
+		CutCard:  ft.cutCard,
+		Shuffled: ft.shuffled,
+		Cut:      ft.cutted,
+	}
+	for i, fixed := range ft.fixed {
+		if fixed {
+			s.Fixed = append(s.Fixed, i)
+		}
+	}
+	return s
+}
+
+// Restore 从快照恢复牌堆，快照中有重复、缺失或者非法的牌时返回错误
+func Restore(s Snapshot) (SnapshotDeck, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	deck := &fiftyTwoCardsDeck{
+		cards:    append([]Card{}, s.Cards...),
+		next:     s.Position,
+		burned:   append([]Card{}, s.Burned...),
+		mucked:   append([]Card{}, s.Mucked...),
+		standard: s.Standard,
+		decks:    s.Decks,
+		cutCard:  s.CutCard,
+		shuffled: s.Shuffled,
+		cutted:   s.Cut,
+	}
+	if s.Fixed != nil {
+		deck.fixed = make([]bool, len(s.Cards))
+		for _, pos := range s.Fixed {
+			deck.fixed[pos] = true
+		}
+	}
+	if s.Decks > 1 || s.CutCard > 0 {
+		return &shoe{fiftyTwoCardsDeck: deck}, nil
+	}
+	return deck, nil
+}
+
+// Validate 检查快照的完整性
+func (s Snapshot) Validate() error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrBadSnapshot, s.Version)
+	}
+	if len(s.Cards) == 0 {
+		return fmt.Errorf("%w: no cards", ErrBadSnapshot)
+	}
+	if s.Decks < 1 {
+		return fmt.Errorf("%w: bad number of decks %d", ErrBadSnapshot, s.Decks)
+	}
+	if s.Standard && len(s.Cards) != s.Decks*len(standard52CardsDeck) {
+		return fmt.Errorf("%w: %d cards in %d standard decks", ErrBadSnapshot, len(s.Cards), s.Decks)
+	}
+	if s.Position < 0 || s.Position > len(s.Cards) {
+		return fmt.Errorf("%w: position %d out of range", ErrBadSnapshot, s.Position)
+	}
+	if s.CutCard < 0 || s.CutCard > len(s.Cards) {
+		return fmt.Errorf("%w: cut card %d out of range", ErrBadSnapshot, s.CutCard)
+	}
+
+	// 每张牌最多出现 Decks 次
+	counts := make(map[Card]int, len(s.Cards))
+	dealt := make(map[Card]int, s.Position)
+	for i, card := range s.Cards {
+		if !isStandardCard(card) {
+			return fmt.Errorf("%w: bad card at %d", ErrBadSnapshot, i)
+		}
+		counts[card]++
+		if counts[card] > s.Decks {
+			return fmt.Errorf("%w: duplicate card %s", ErrBadSnapshot, card.ASCII())
+		}
+		if i < s.Position {
+			dealt[card]++
+		}
+	}
+
+	// 烧牌和弃牌必须是已经发出的牌，且不能重复
+	for _, pile := range [][]Card{s.Burned, s.Mucked} {
+		for _, card := range pile {
+			dealt[card]--
+			if dealt[card] < 0 {
+				return fmt.Errorf("%w: card %s not dealt", ErrBadSnapshot, card.ASCII())
+			}
+		}
+	}
+
+	for _, pos := range s.Fixed {
+		if pos < 0 || pos >= len(s.Cards) {
+			return fmt.Errorf("%w: fixed position %d out of range", ErrBadSnapshot, pos)
+		}
+	}
+	return nil
+}
+
+// MarshalBinary 二进制格式：magic、版本、标志位、各段长度和牌，最后是 CRC32 校验和
+func (s Snapshot) MarshalBinary() ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, 16+len(s.Cards)+len(s.Burned)+len(s.Mucked)+len(s.Fixed)*2))
+	buf.WriteString(snapshotMagic)
+	buf.WriteByte(byte(s.Version))
+
+	var flags byte
+	if s.Standard {
+		flags |= snapshotFlagStandard
+	}
+	if s.Shuffled {
+		flags |= snapshotFlagShuffled
+	}
+	if s.Cut {
+		flags |= snapshotFlagCut
+	}
+	buf.WriteByte(flags)
+
+	var scratch [binary.MaxVarintLen64]byte
+	writeUvarint := func(v int) {
+		n := binary.PutUvarint(scratch[:], uint64(v))
+		buf.Write(scratch[:n])
+	}
+
+	writeUvarint(s.Decks)
+	writeUvarint(s.CutCard)
+	writeUvarint(s.Position)
+	for _, pile := range [][]Card{s.Cards, s.Burned, s.Mucked} {
+		writeUvarint(len(pile))
+		for _, card := range pile {
+			buf.WriteByte(byte(card.Rank)<<4 | byte(card.Suit))
+		}
+	}
+	writeUvarint(len(s.Fixed))
+	for _, pos := range s.Fixed {
+		writeUvarint(pos)
+	}
+
+	sum := crc32.ChecksumIEEE(buf.Bytes())
+	binary.BigEndian.PutUint32(scratch[:4], sum)
+	buf.Write(scratch[:4])
+	return buf.Bytes(), nil
+}
+
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	if len(data) < len(snapshotMagic)+2+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return fmt.Errorf("%w: bad header", ErrBadSnapshot)
+	}
+	body, trailer := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(trailer) {
+		return fmt.Errorf("%w: checksum mismatch", ErrBadSnapshot)
+	}
+
+	reader := bytes.NewReader(body[len(snapshotMagic):])
+	version, _ := reader.ReadByte()
+	flags, _ := reader.ReadByte()
+	readUvarint := func() (int, error) {
+		v, err := binary.ReadUvarint(reader)
+		if err != nil || v > uint64(len(data)) {
+			return 0, fmt.Errorf("%w: truncated", ErrBadSnapshot)
+		}
+		return int(v), nil
+	}
+
+	decoded := Snapshot{
+		Version:  int(version),
+		Standard: flags&snapshotFlagStandard != 0,
+		Shuffled: flags&snapshotFlagShuffled != 0,
+		Cut:      flags&snapshotFlagCut != 0,
+	}
+	var err error
+	for _, v := range []*int{&decoded.Decks, &decoded.CutCard, &decoded.Position} {
+		if *v, err = readUvarint(); err != nil {
+			return err
+		}
+	}
+	for _, pile := range []*[]Card{&decoded.Cards, &decoded.Burned, &decoded.Mucked} {
+		n, err := readUvarint()
+		if err != nil {
+			return err
+		}
+		*pile = make([]Card, 0, n)
+		for i := 0; i < n; i++ {
+			b, err := reader.ReadByte()
+			if err != nil {
+				return fmt.Errorf("%w: truncated", ErrBadSnapshot)
+			}
+			*pile = append(*pile, Card{Rank: Rank(b >> 4), Suit: Suit(b & 0x0f)})
+		}
+	}
+	n, err := readUvarint()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		pos, err := readUvarint()
+		if err != nil {
+			return err
+		}
+		decoded.Fixed = append(decoded.Fixed, pos)
+	}
+	if reader.Len() != 0 {
+		return fmt.Errorf("%w: trailing bytes", ErrBadSnapshot)
+	}
+
+	*s = decoded
+	return nil
+}
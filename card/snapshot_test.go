@@ -0,0 +1,102 @@
+package card
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	deck := NewFiftyTwoCardsDeck().(SnapshotDeck)
+	deck.Shuffle()
+	assert.Nil(t, deck.Cut())
+	assert.True(t, deck.Burn())
+	c1, _ := deck.Deal()
+	c2, _ := deck.Deal()
+	assert.Nil(t, deck.Muck(c1))
+	assert.NotNil(t, deck.Muck(c1))
+
+	snapshot := deck.Snapshot()
+	assert.Equal(t, 3, snapshot.Position)
+	assert.Len(t, snapshot.Burned, 1)
+	assert.Equal(t, []Card{c1}, snapshot.Mucked)
+	assert.True(t, snapshot.Shuffled)
+	assert.True(t, snapshot.Cut)
+
+	restored, err := Restore(snapshot)
+	assert.Nil(t, err)
+	assert.Equal(t, deck.Length(), restored.Length())
+	assert.Nil(t, restored.Muck(c2))
+	for {
+		expected, ok := deck.Deal()
+		actual, ok2 := restored.Deal()
+		assert.Equal(t, ok, ok2)
+		assert.Equal(t, expected, actual)
+		if !ok {
+			break
+		}
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	deck, _ := NewPartialPresetDeck(map[int]Card{0: NewCard("As")})
+	deck.Shuffle()
+	deck.Deal()
+
+	data, err := json.Marshal(deck.(SnapshotDeck).Snapshot())
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"cards":["As",`)
+
+	var decoded Snapshot
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, deck.(SnapshotDeck).Snapshot(), decoded)
+	_, err = Restore(decoded)
+	assert.Nil(t, err)
+}
+
+func TestSnapshotBinary(t *testing.T) {
+	deck := NewFiftyTwoCardsDeck().(SnapshotDeck)
+	deck.Shuffle()
+	deck.Burn()
+	c, _ := deck.Deal()
+	deck.Muck(c)
+
+	snapshot := deck.Snapshot()
+	data, err := snapshot.MarshalBinary()
+	assert.Nil(t, err)
+
+	var decoded Snapshot
+	assert.Nil(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, snapshot, decoded)
+
+	data[10] ^= 0xff
+	assert.True(t, errors.Is(decoded.UnmarshalBinary(data), ErrBadSnapshot))
+	assert.NotNil(t, decoded.UnmarshalBinary(data[:5]))
+}
+
+func TestSnapshotIntegrity(t *testing.T) {
+	snapshot := NewFiftyTwoCardsDeck().(SnapshotDeck).Snapshot()
+
+	duplicated := snapshot
+	duplicated.Cards = append([]Card{}, snapshot.Cards...)
+	duplicated.Cards[1] = duplicated.Cards[0]
+	_, err := Restore(duplicated)
+	assert.True(t, errors.Is(err, ErrBadSnapshot))
+
+	missing := snapshot
+	missing.Cards = snapshot.Cards[1:]
+	_, err = Restore(missing)
+	assert.True(t, errors.Is(err, ErrBadSnapshot))
+
+	undealt := snapshot
+	undealt.Burned = []Card{snapshot.Cards[0]}
+	_, err = Restore(undealt)
+	assert.True(t, errors.Is(err, ErrBadSnapshot))
+
+	outOfRange := snapshot
+	outOfRange.Position = 53
+	_, err = Restore(outOfRange)
+	assert.True(t, errors.Is(err, ErrBadSnapshot))
+}
@@ -0,0 +1,104 @@
+package cfr
+
+type (
+	// bestResponse 对手策略固定时 player 的最优反应
+	bestResponse struct {
+		player   int
+		strategy Strategy
+		reaches  map[string][]reach // 信息集中每个节点的对手和机会的到达概率
+		actions  map[string]int     // 已经确定的最优行动
+	}
+
+	reach struct {
+		state       State
+		probability float64
+	}
+)
+
+// BestResponse player 对另一个玩家的策略 s 做最优反应时的期望收益
+func BestResponse(g Game, s Strategy, player int) float64 {
+	br := &bestResponse{
+		player:   player,
+		strategy: s,
+		reaches:  make(map[string][]reach),
+		actions:  make(map[string]int),
+	}
+	root := g.Root()
+	br.collect(root, 1)
+	return br.value(root)
+}
+
+// Exploitability 双方最优反应收益的平均值，纳什均衡时为 0
+func Exploitability(g Game, s Strategy) float64 {
+	return (BestResponse(g, s, 0) + BestResponse(g, s, 1)) / 2
+}
+
+// collect 记录 player 每个信息集中各个节点的到达概率，不包括 player 自己的行动概率
+func (br *bestResponse) collect(st State, probability float64) {
+	switch p := st.Player(); p {
+	case Terminal:
+	case Chance:
+		for _, o := range st.Outcomes() {
+			br.collect(o.State, probability*o.Probability)
+		}
+	case br.player:
+		key := st.InfoSet()
+		br.reaches[key] = append(br.reaches[key], reach{state: st, probability: probability})
+		for i := range st.Actions() {
+			br.collect(st.Play(i), probability)
+		}
+	default:
+		for i, q := range br.strategy.Probabilities(st.InfoSet(), len(st.Actions())) {
+			if q > 0 {
+				br.collect(st.Play(i), probability*q)
+			}
+		}
+	}
+}
+
+// value player 在节点 st 的收益，完美回忆保证后面的信息集不依赖前面的选择
+func (br *bestResponse) value(st State) float64 {
+	switch p := st.Player(); p {
+	case Terminal:
+		if br.player == 0 {
+			return st.Utility()
+		}
+		return -st.Utility()
+	case Chance:
+		var v float64
+		for _, o := range st.Outcomes() {
+			v += o.Probability * br.value(o.State)
+		}
+		return v
+	case br.player:
+		return br.value(st.Play(br.action(st.InfoSet())))
+	default:
+		var v float64
+		for i, q := range br.strategy.Probabilities(st.InfoSet(), len(st.Actions())) {
+			if q > 0 {
+				v += q * br.value(st.Play(i))
+			}
+		}
+		return v
+	}
+}
+
+// action 信息集上按到达概率加权收益最大的行动
+func (br *bestResponse) action(key string) int {
+	if a, ok := br.actions[key]; ok {
+		return a
+	}
+	reaches := br.reaches[key]
+	best, bestValue := 0, 0.0
+	for i := range reaches[0].state.Actions() {
+		var v float64
+		for _, r := range reaches {
+			v += r.probability * br.value(r.state.Play(i))
+		}
+		if i == 0 || v > bestValue {
+			best, bestValue = i, v
+		}
+	}
+	br.actions[key] = best
+	return best
+}
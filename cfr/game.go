@@ -0,0 +1,76 @@
+// Package cfr 两人零和扩展式博弈的反事实遗憾最小化求解器
+package cfr
+
+const (
+	Chance   = -1 // 机会节点，例如发牌
+	Terminal = -2 // 终局
+)
+
+type (
+	// Game 两人零和的扩展式博弈，要求完美回忆
+	Game interface {
+		Root() State
+	}
+
+	// State 博弈树上的节点，Play 返回新的节点而不修改原节点
+	State interface {
+		// Player 行动的玩家 0 或 1，机会节点为 Chance，终局为 Terminal
+		Player() int
+		// Utility 终局时玩家 0 的收益，玩家 1 的收益为其相反数
+		Utility() float64
+		// Outcomes 机会节点的所有结果，概率之和为 1
+		Outcomes() []Outcome
+		// InfoSet 行动玩家的信息集，玩家无法区分的节点相同
+		InfoSet() string
+		// Actions 行动玩家的合法行动
+		Actions() []string
+		// Play 执行第 i 个行动
+		Play(i int) State
+	}
+
+	// Outcome 机会节点的一个结果
+	Outcome struct {
+		State       State
+		Probability float64
+	}
+
+	// Strategy 每个信息集上各个行动的概率，顺序与 Actions 相同
+	Strategy map[string][]float64
+)
+
+// Probabilities 信息集上的行动概率，没有记录的信息集使用均匀分布
+func (s Strategy) Probabilities(infoSet string, actions int) []float64 {
+	if p, ok := s[infoSet]; ok && len(p) == actions {
+		return p
+	}
+	p := make([]float64, actions)
+	for i := range p {
+		p[i] = 1 / float64(actions)
+	}
+	return p
+}
+
+// Value 双方都按 s 行动时玩家 0 的期望收益
+func Value(g Game, s Strategy) float64 {
+	return value(g.Root(), s)
+}
+
+func value(st State, s Strategy) float64 {
+	switch st.Player() {
+	case Terminal:
+		return st.Utility()
+	case Chance:
+		var v float64
+		for _, o := range st.Outcomes() {
+			v += o.Probability * value(o.State, s)
+		}
+		return v
+	}
+	var v float64
+	for i, p := range s.Probabilities(st.InfoSet(), len(st.Actions())) {
+		if p > 0 {
+			v += p * value(st.Play(i), s)
+		}
+	}
+	return v
+}
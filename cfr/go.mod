@@ -0,0 +1,16 @@
+module github.com/openpoker-dev/contrib/cfr
+
+go 1.18
+
+require (
+	github.com/openpoker-dev/contrib/card v0.0.1
+	github.com/stretchr/testify v1.7.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
+
+replace github.com/openpoker-dev/contrib/card => ../card
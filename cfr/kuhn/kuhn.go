@@ -0,0 +1,99 @@
+// Package kuhn 库恩扑克：三张牌 J、Q、K，每人一张，各下一个底注，只有一轮最多一次下注
+package kuhn
+
+import (
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/cfr"
+)
+
+type (
+	// Game 库恩扑克，玩家 0 先行动
+	Game struct{}
+
+	// state history 为行动序列，p 为过牌或弃牌，b 为下注或跟注
+	state struct {
+		cards   []card.Card
+		history string
+	}
+)
+
+var (
+	// Cards 缩减的牌堆
+	Cards = []card.Card{card.NewCard("Js"), card.NewCard("Qs"), card.NewCard("Ks")}
+
+	// actions 过牌或弃牌，下注或跟注
+	actions = []string{"p", "b"}
+
+	_ cfr.Game  = Game{}
+	_ cfr.State = (*state)(nil)
+)
+
+func (Game) Root() cfr.State {
+	return &state{}
+}
+
+func (s *state) Player() int {
+	switch {
+	case s.cards == nil:
+		return cfr.Chance
+	case s.terminal():
+		return cfr.Terminal
+	}
+	return len(s.history) % 2
+}
+
+func (s *state) Utility() float64 {
+	winner := 0
+	if s.cards[1].Rank > s.cards[0].Rank {
+		winner = 1
+	}
+	switch s.history {
+	case "bp":
+		winner = 0
+	case "pbp":
+		winner = 1
+	}
+	// 有人跟注时每人投入两个筹码
+	amount := 1.0
+	if s.history == "bb" || s.history == "pbb" {
+		amount = 2
+	}
+	if winner == 0 {
+		return amount
+	}
+	return -amount
+}
+
+// Outcomes 发给两个玩家的所有排列
+func (s *state) Outcomes() []cfr.Outcome {
+	outcomes := make([]cfr.Outcome, 0, 6)
+	for i := range Cards {
+		for j := range Cards {
+			if i != j {
+				outcomes = append(outcomes, cfr.Outcome{State: &state{cards: []card.Card{Cards[i], Cards[j]}}, Probability: 1.0 / 6})
+			}
+		}
+	}
+	return outcomes
+}
+
+// InfoSet 自己的牌和行动序列，例如 Kpb
+func (s *state) InfoSet() string {
+	return s.cards[s.Player()].ASCII()[:1] + s.history
+}
+
+func (s *state) Actions() []string {
+	return actions
+}
+
+func (s *state) Play(i int) cfr.State {
+	return &state{cards: s.cards, history: s.history + actions[i]}
+}
+
+func (s *state) terminal() bool {
+	switch s.history {
+	case "pp", "bp", "bb", "pbp", "pbb":
+		return true
+	}
+	return false
+}
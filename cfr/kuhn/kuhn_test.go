@@ -0,0 +1,52 @@
+package kuhn
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/cfr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameTree(t *testing.T) {
+	root := Game{}.Root()
+	assert.Equal(t, cfr.Chance, root.Player())
+	outcomes := root.Outcomes()
+	assert.Len(t, outcomes, 6)
+
+	// 玩家 0 拿 K，玩家 1 拿 J
+	st := outcomes[4].State
+	assert.Equal(t, 0, st.Player())
+	assert.Equal(t, "K", st.InfoSet())
+	st = st.Play(0)
+	assert.Equal(t, "Jp", st.InfoSet())
+	st = st.Play(1)
+	assert.Equal(t, "Kpb", st.InfoSet())
+	called := st.Play(1)
+	assert.Equal(t, cfr.Terminal, called.Player())
+	assert.Equal(t, 2.0, called.Utility())
+	folded := st.Play(0)
+	assert.Equal(t, -1.0, folded.Utility())
+}
+
+func TestSolve(t *testing.T) {
+	s := cfr.NewSolver(Game{}, cfr.CFRPlus, 0)
+	var history []float64
+	s.Train(1000, func(it cfr.Iteration) {
+		history = append(history, it.Exploitability)
+	})
+	assert.Len(t, history, 1000)
+	assert.Less(t, history[999], 0.001)
+	assert.Less(t, history[999], history[9])
+
+	strategy := s.Strategy()
+	assert.Len(t, strategy, 12)
+	// 博弈的价值为 -1/18
+	assert.InDelta(t, -1.0/18, cfr.Value(Game{}, strategy), 0.001)
+	// 拿 K 面对下注总是跟注，拿 J 面对下注总是弃牌
+	assert.InDelta(t, 1, strategy["Kb"][1], 0.01)
+	assert.InDelta(t, 1, strategy["Jb"][0], 0.01)
+
+	mc := cfr.NewSolver(Game{}, cfr.MCCFR, 1)
+	mc.Train(20000, nil)
+	assert.Less(t, cfr.Exploitability(Game{}, mc.Strategy()), 0.02)
+}
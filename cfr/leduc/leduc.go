@@ -0,0 +1,171 @@
+// Package leduc 勒杜克德州扑克：J、Q、K 各两张共六张牌，每人一张手牌，第二轮发一张公共牌
+// 每人先下一个底注，两轮下注额分别为 2 和 4，每轮最多加注两次，与公共牌成对最大，否则比较牌点
+package leduc
+
+import (
+	"strings"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/cfr"
+)
+
+type (
+	// Game 勒杜克德州扑克，玩家 0 每轮先行动
+	Game struct{}
+
+	// state rounds 为每轮的行动，f 弃牌，c 过牌或跟注，r 下注或加注
+	state struct {
+		holes   []card.Card
+		board   *card.Card
+		rounds  []string
+		spent   [2]float64 // 每个玩家投入的筹码，包括底注
+		folded  int        // 弃牌的玩家，没有时为 -1
+		dealing bool       // 第一轮结束，等待发公共牌
+	}
+)
+
+const (
+	ante     = 1
+	maxRaise = 2
+)
+
+var (
+	// Cards 缩减的牌堆
+	Cards = []card.Card{
+		card.NewCard("Js"), card.NewCard("Jh"),
+		card.NewCard("Qs"), card.NewCard("Qh"),
+		card.NewCard("Ks"), card.NewCard("Kh"),
+	}
+
+	betSizes = []float64{2, 4}
+
+	_ cfr.Game  = Game{}
+	_ cfr.State = (*state)(nil)
+)
+
+func (Game) Root() cfr.State {
+	return &state{rounds: []string{""}, spent: [2]float64{ante, ante}, folded: -1}
+}
+
+func (s *state) Player() int {
+	switch {
+	case s.holes == nil || s.dealing:
+		return cfr.Chance
+	case s.folded >= 0 || s.roundOver() && len(s.rounds) == 2:
+		return cfr.Terminal
+	}
+	return len(s.current()) % 2
+}
+
+func (s *state) Utility() float64 {
+	switch s.winner() {
+	case 0:
+		return s.spent[1]
+	case 1:
+		return -s.spent[0]
+	}
+	return 0
+}
+
+// Outcomes 开始时发两张手牌，第一轮结束后从剩下的四张牌中发一张公共牌
+func (s *state) Outcomes() []cfr.Outcome {
+	var outcomes []cfr.Outcome
+	if s.holes == nil {
+		for i := range Cards {
+			for j := range Cards {
+				if i != j {
+					next := *s
+					next.holes = []card.Card{Cards[i], Cards[j]}
+					outcomes = append(outcomes, cfr.Outcome{State: &next, Probability: 1.0 / 30})
+				}
+			}
+		}
+		return outcomes
+	}
+	for i := range Cards {
+		if Cards[i] != s.holes[0] && Cards[i] != s.holes[1] {
+			next := *s
+			next.board = &Cards[i]
+			next.dealing = false
+			next.rounds = append(append([]string{}, s.rounds...), "")
+			outcomes = append(outcomes, cfr.Outcome{State: &next, Probability: 1.0 / 4})
+		}
+	}
+	return outcomes
+}
+
+// InfoSet 自己和公共牌的牌点以及每轮的行动，花色不影响结果，例如 KQ:rc/r
+func (s *state) InfoSet() string {
+	key := s.holes[s.Player()].ASCII()[:1]
+	if s.board != nil {
+		key += s.board.ASCII()[:1]
+	}
+	return key + ":" + strings.Join(s.rounds, "/")
+}
+
+func (s *state) Actions() []string {
+	var actions []string
+	if s.facingBet() {
+		actions = append(actions, "f")
+	}
+	actions = append(actions, "c")
+	if strings.Count(s.current(), "r") < maxRaise {
+		actions = append(actions, "r")
+	}
+	return actions
+}
+
+func (s *state) Play(i int) cfr.State {
+	a := s.Actions()[i]
+	player := s.Player()
+	next := *s
+	next.rounds = append([]string{}, s.rounds...)
+	next.rounds[len(next.rounds)-1] += a
+	switch a {
+	case "f":
+		next.folded = player
+	case "c":
+		next.spent[player] = next.spent[1-player]
+	case "r":
+		next.spent[player] = next.spent[1-player] + betSizes[len(s.rounds)-1]
+	}
+	if next.folded < 0 && next.roundOver() && len(next.rounds) == 1 {
+		next.dealing = true
+	}
+	return &next
+}
+
+func (s *state) current() string {
+	return s.rounds[len(s.rounds)-1]
+}
+
+func (s *state) facingBet() bool {
+	return s.spent[0] != s.spent[1]
+}
+
+// roundOver 双方过牌或者跟注了下注
+func (s *state) roundOver() bool {
+	r := s.current()
+	return r == "cc" || len(r) >= 2 && r[len(r)-1] == 'c' && strings.Contains(r, "r")
+}
+
+// winner 赢家，平局时为 -1
+func (s *state) winner() int {
+	if s.folded >= 0 {
+		return 1 - s.folded
+	}
+	strength := func(c card.Card) int {
+		if c.Rank == s.board.Rank {
+			return 100
+		}
+		return int(c.Rank)
+	}
+	a, b := strength(s.holes[0]), strength(s.holes[1])
+	switch {
+	case a > b:
+		return 0
+	case b > a:
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,70 @@
+package leduc
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/cfr"
+	"github.com/stretchr/testify/assert"
+)
+
+// walk 遍历整棵树，返回信息集和终局的数量
+func walk(st cfr.State, infoSets map[string]bool) int {
+	switch st.Player() {
+	case cfr.Terminal:
+		return 1
+	case cfr.Chance:
+		terminals := 0
+		var total float64
+		for _, o := range st.Outcomes() {
+			total += o.Probability
+			terminals += walk(o.State, infoSets)
+		}
+		if total < 0.999999 || total > 1.000001 {
+			panic("probabilities do not sum to 1")
+		}
+		return terminals
+	}
+	infoSets[st.InfoSet()] = true
+	terminals := 0
+	for i := range st.Actions() {
+		terminals += walk(st.Play(i), infoSets)
+	}
+	return terminals
+}
+
+func TestGameTree(t *testing.T) {
+	infoSets := make(map[string]bool)
+	walk(Game{}.Root(), infoSets)
+	assert.Len(t, infoSets, 288)
+
+	// 玩家 0 拿 K，玩家 1 拿 Q，第一轮下注跟注，公共牌是 Q
+	st := Game{}.Root().Outcomes()[22].State
+	assert.Equal(t, "K:", st.InfoSet())
+	assert.Equal(t, []string{"c", "r"}, st.Actions())
+	st = st.Play(1)
+	assert.Equal(t, "Q:r", st.InfoSet())
+	assert.Equal(t, []string{"f", "c", "r"}, st.Actions())
+	st = st.Play(1)
+	assert.Equal(t, cfr.Chance, st.Player())
+	for _, o := range st.Outcomes() {
+		if o.State.InfoSet() == "KQ:rc/" {
+			st = o.State
+			break
+		}
+	}
+	st = st.Play(1).Play(2).Play(1)
+	assert.Equal(t, cfr.Terminal, st.Player())
+	// 每人投入 1 + 2 + 4 + 4 = 11，Q 与公共牌成对
+	assert.Equal(t, -11.0, st.Utility())
+}
+
+func TestSolve(t *testing.T) {
+	s := cfr.NewSolver(Game{}, cfr.CFRPlus, 0)
+	s.Train(100, nil)
+	strategy := s.Strategy()
+	assert.Len(t, strategy, 288)
+	assert.Greater(t, cfr.Exploitability(Game{}, cfr.Strategy{}), 1.0)
+	assert.Less(t, cfr.Exploitability(Game{}, strategy), 0.05)
+	// 博弈的价值约为 -0.0856
+	assert.InDelta(t, -0.0856, cfr.Value(Game{}, strategy), 0.01)
+}
@@ -0,0 +1,248 @@
+package cfr
+
+import (
+	"math/rand"
+)
+
+type (
+	// Variant 求解算法
+	Variant int
+
+	// Solver 反事实遗憾最小化求解器，两个玩家轮流更新
+	Solver struct {
+		game       Game
+		variant    Variant
+		rand       *rand.Rand
+		nodes      map[string]*node
+		touched    []*node // 本次遍历中有待更新遗憾的节点
+		iterations int
+	}
+
+	// Iteration 一次迭代之后的进度
+	Iteration struct {
+		N              int     // 已经完成的迭代次数
+		InfoSets       int     // 访问过的信息集数量
+		Exploitability float64 // 平均策略的可利用度
+	}
+
+	// node 信息集上累计的遗憾和策略
+	node struct {
+		regrets     []float64
+		strategySum []float64
+		pending     []float64 // 本次遍历的遗憾，遍历结束后再累计，保证同一次遍历中策略不变
+	}
+)
+
+const (
+	VanillaCFR Variant = iota // 遍历整棵树的 CFR
+	CFRPlus                   // CFR+：遗憾不小于 0，平均策略按迭代次数线性加权
+	MCCFR                     // 外部采样的蒙特卡洛 CFR：采样机会节点和对手的行动
+)
+
+// NewSolver seed 只用于 MCCFR 的采样
+func NewSolver(g Game, v Variant, seed int64) *Solver {
+	return &Solver{
+		game:    g,
+		variant: v,
+		rand:    rand.New(rand.NewSource(seed)),
+		nodes:   make(map[string]*node),
+	}
+}
+
+// Iterate 完成一次迭代，两个玩家各更新一次
+func (s *Solver) Iterate() {
+	s.iterations++
+	for player := 0; player < 2; player++ {
+		if s.variant == MCCFR {
+			s.sample(s.game.Root(), player)
+		} else {
+			s.traverse(s.game.Root(), player, [3]float64{1, 1, 1})
+			s.commit()
+		}
+	}
+}
+
+// Train 迭代 n 次，report 不为空时每次迭代之后报告平均策略的可利用度
+func (s *Solver) Train(n int, report func(Iteration)) {
+	for i := 0; i < n; i++ {
+		s.Iterate()
+		if report != nil {
+			report(Iteration{N: s.iterations, InfoSets: len(s.nodes), Exploitability: Exploitability(s.game, s.Strategy())})
+		}
+	}
+}
+
+// Iterations 已经完成的迭代次数
+func (s *Solver) Iterations() int {
+	return s.iterations
+}
+
+// Strategy 平均策略，收敛到纳什均衡
+func (s *Solver) Strategy() Strategy {
+	strategy := make(Strategy, len(s.nodes))
+	for key, n := range s.nodes {
+		strategy[key] = normalize(n.strategySum)
+	}
+	return strategy
+}
+
+// traverse 完整遍历，返回 player 的期望收益
+// reach 为玩家 0、玩家 1 和机会节点的到达概率
+func (s *Solver) traverse(st State, player int, reach [3]float64) float64 {
+	switch p := st.Player(); p {
+	case Terminal:
+		return utility(st, player)
+	case Chance:
+		var v float64
+		for _, o := range st.Outcomes() {
+			next := reach
+			next[2] *= o.Probability
+			v += o.Probability * s.traverse(o.State, player, next)
+		}
+		return v
+	}
+
+	p := st.Player()
+	n := s.node(st)
+	strategy := n.current()
+	values := make([]float64, len(strategy))
+	var v float64
+	for i, q := range strategy {
+		// 对手以 0 概率到达的子树不影响遗憾
+		if p != player && q == 0 {
+			continue
+		}
+		next := reach
+		next[p] *= q
+		values[i] = s.traverse(st.Play(i), player, next)
+		v += q * values[i]
+	}
+	if p != player {
+		return v
+	}
+
+	counterfactual := reach[1-p] * reach[2]
+	weight := 1.0
+	if s.variant == CFRPlus {
+		weight = float64(s.iterations)
+	}
+	if n.pending == nil {
+		n.pending = make([]float64, len(strategy))
+		s.touched = append(s.touched, n)
+	}
+	for i, q := range strategy {
+		n.pending[i] += counterfactual * (values[i] - v)
+		n.strategySum[i] += weight * reach[p] * q
+	}
+	return v
+}
+
+// commit 累计本次遍历的遗憾，CFR+ 的遗憾不小于 0
+func (s *Solver) commit() {
+	for _, n := range s.touched {
+		for i, r := range n.pending {
+			n.regrets[i] += r
+			if s.variant == CFRPlus && n.regrets[i] < 0 {
+				n.regrets[i] = 0
+			}
+		}
+		n.pending = nil
+	}
+	s.touched = s.touched[:0]
+}
+
+// sample 外部采样：机会节点和对手只采样一个行动，返回 player 的采样收益
+func (s *Solver) sample(st State, player int) float64 {
+	switch p := st.Player(); p {
+	case Terminal:
+		return utility(st, player)
+	case Chance:
+		outcomes := st.Outcomes()
+		r := s.rand.Float64()
+		for _, o := range outcomes[:len(outcomes)-1] {
+			if r < o.Probability {
+				return s.sample(o.State, player)
+			}
+			r -= o.Probability
+		}
+		return s.sample(outcomes[len(outcomes)-1].State, player)
+	}
+
+	n := s.node(st)
+	strategy := n.current()
+	if st.Player() != player {
+		// 对手的平均策略在对手的节点上累计
+		for i, q := range strategy {
+			n.strategySum[i] += q
+		}
+		return s.sample(st.Play(choose(s.rand, strategy)), player)
+	}
+
+	values := make([]float64, len(strategy))
+	var v float64
+	for i, q := range strategy {
+		values[i] = s.sample(st.Play(i), player)
+		v += q * values[i]
+	}
+	for i := range strategy {
+		n.regrets[i] += values[i] - v
+	}
+	return v
+}
+
+func (s *Solver) node(st State) *node {
+	key := st.InfoSet()
+	n, ok := s.nodes[key]
+	if !ok {
+		actions := len(st.Actions())
+		n = &node{regrets: make([]float64, actions), strategySum: make([]float64, actions)}
+		s.nodes[key] = n
+	}
+	return n
+}
+
+// current 遗憾匹配得到的当前策略
+func (n *node) current() []float64 {
+	positive := make([]float64, len(n.regrets))
+	for i, r := range n.regrets {
+		if r > 0 {
+			positive[i] = r
+		}
+	}
+	return normalize(positive)
+}
+
+// normalize 归一化，和为 0 时返回均匀分布
+func normalize(values []float64) []float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	p := make([]float64, len(values))
+	for i, v := range values {
+		if sum > 0 {
+			p[i] = v / sum
+		} else {
+			p[i] = 1 / float64(len(values))
+		}
+	}
+	return p
+}
+
+func utility(st State, player int) float64 {
+	if player == 0 {
+		return st.Utility()
+	}
+	return -st.Utility()
+}
+
+func choose(r *rand.Rand, p []float64) int {
+	x := r.Float64()
+	for i, q := range p[:len(p)-1] {
+		if x < q {
+			return i
+		}
+		x -= q
+	}
+	return len(p) - 1
+}
@@ -0,0 +1,93 @@
+package cfr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// pennies 不对称的猜硬币：玩家 1 看不到玩家 0 的选择
+	// 都选正面时玩家 0 赢 2，都选反面时赢 1，否则输 1，均衡时双方选正面的概率都是 2/5，价值 1/5
+	pennies struct{}
+
+	penniesState struct {
+		moves string
+	}
+)
+
+func (pennies) Root() State {
+	return &penniesState{}
+}
+
+func (s *penniesState) Player() int {
+	if len(s.moves) == 2 {
+		return Terminal
+	}
+	return len(s.moves)
+}
+
+func (s *penniesState) Utility() float64 {
+	switch s.moves {
+	case "HH":
+		return 2
+	case "TT":
+		return 1
+	}
+	return -1
+}
+
+func (s *penniesState) Outcomes() []Outcome {
+	return nil
+}
+
+func (s *penniesState) InfoSet() string {
+	return string(rune('0' + len(s.moves)))
+}
+
+func (s *penniesState) Actions() []string {
+	return []string{"H", "T"}
+}
+
+func (s *penniesState) Play(i int) State {
+	return &penniesState{moves: s.moves + s.Actions()[i]}
+}
+
+func TestSolverConverges(t *testing.T) {
+	// 采样的误差较大
+	tolerances := map[Variant]float64{VanillaCFR: 0.02, CFRPlus: 0.02, MCCFR: 0.05}
+	for v, tolerance := range tolerances {
+		s := NewSolver(pennies{}, v, 1)
+		var first, last Iteration
+		s.Train(2000, func(it Iteration) {
+			if it.N == 1 {
+				first = it
+			}
+			last = it
+		})
+		assert.Equal(t, 2000, s.Iterations())
+		assert.Equal(t, 2, last.InfoSets)
+		assert.Less(t, last.Exploitability, first.Exploitability, v)
+		assert.Less(t, last.Exploitability, tolerance, v)
+
+		strategy := s.Strategy()
+		assert.InDelta(t, 0.4, strategy["0"][0], tolerance, v)
+		assert.InDelta(t, 0.4, strategy["1"][0], tolerance, v)
+		assert.InDelta(t, 0.2, Value(pennies{}, strategy), tolerance, v)
+	}
+}
+
+func TestExploitability(t *testing.T) {
+	equilibrium := Strategy{"0": {0.4, 0.6}, "1": {0.4, 0.6}}
+	assert.InDelta(t, 0.2, BestResponse(pennies{}, equilibrium, 0), 1e-9)
+	assert.InDelta(t, -0.2, BestResponse(pennies{}, equilibrium, 1), 1e-9)
+	assert.InDelta(t, 0, Exploitability(pennies{}, equilibrium), 1e-9)
+
+	// 均匀策略：玩家 0 总选正面赢 1/2，玩家 1 总选反面赢 0
+	uniform := Strategy{}
+	assert.Equal(t, []float64{0.5, 0.5}, uniform.Probabilities("0", 2))
+	assert.InDelta(t, 0.25, Value(pennies{}, uniform), 1e-9)
+	assert.InDelta(t, 0.5, BestResponse(pennies{}, uniform, 0), 1e-9)
+	assert.InDelta(t, 0, BestResponse(pennies{}, uniform, 1), 1e-9)
+	assert.InDelta(t, 0.25, Exploitability(pennies{}, uniform), 1e-9)
+}
@@ -2,6 +2,7 @@ package evaluator
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/openpoker-dev/contrib/card"
@@ -18,6 +19,8 @@ type (
 	EvaluatorManager interface {
 		Register(Evaluator) error
 		Find(HandRank) Evaluator
+		// Evaluate 最大的牌型，会改变传入的牌的顺序，返回的 PokerHand.Cards 也引用传入的牌，
+		// 比较两手牌之前不能用同一个切片计算另一手牌
 		Evaluate(...card.Card) PokerHand
 	}
 
@@ -25,8 +28,9 @@ type (
 	HandRank int
 
 	PokerHand struct {
-		Rank  HandRank
-		Cards []card.Card
+		Rank   HandRank    `json:"rank"`
+		Cards  []card.Card `json:"cards"`
+		AceLow bool        `json:"ace_low,omitempty"` // A-5 低牌中比较时 A 算最小，Cards 中仍然是 card.RankAce
 	}
 
 	CompareResult int
@@ -36,6 +40,9 @@ type (
 		isRoyalFlushEvaluator bool
 	}
 
+	// fiveOfAKindEvaluator 五条的评估算法，只在多副牌时出现
+	fiveOfAKindEvaluator struct{}
+
 	// fourOfAKindEvaluator 炸弹的评估算法
 	fourOfAKindEvaluator struct{}
 
@@ -77,6 +84,7 @@ const (
 	RankFourOfAKind                   // 炸弹 eg. 2♥️2♦️2♠️2♣️4♥️
 	RankStraightFlush                 // 同花顺 eg. 9♥️T♥️J♥️Q♥️K♥️
 	RankRoyalFlush                    // 皇家同花顺 eg. T♥️J♥️Q♥️K♥️A♥️
+	RankFiveOfAKind                   // 五条，多副牌时出现 eg. 2♥️2♥️2♦️2♠️2♣️
 )
 
 const (
@@ -97,11 +105,13 @@ var (
 		RankFourOfAKind:   "Four of A Kind",
 		RankStraightFlush: "Straight Flush",
 		RankRoyalFlush:    "Royal Flush",
+		RankFiveOfAKind:   "Five of A Kind",
 	}
 )
 
 var (
 	_ Evaluator = (*straightFlushEvaluator)(nil)
+	_ Evaluator = fiveOfAKindEvaluator{}
 	_ Evaluator = fourOfAKindEvaluator{}
 	_ Evaluator = fullHouseEvaluator{}
 	_ Evaluator = flushEvaluator{}
@@ -116,6 +126,28 @@ var (
 	defaultEvaluatorManager EvaluatorManager
 )
 
+func (hr HandRank) String() string {
+	return handRankDescriptions[hr]
+}
+
+// MarshalText 序列化为牌型的名字，eg. "Two Pair"
+func (hr HandRank) MarshalText() ([]byte, error) {
+	if _, exists := handRankDescriptions[hr]; !exists {
+		return nil, fmt.Errorf("unknown hand rank: %d", int(hr))
+	}
+	return []byte(handRankDescriptions[hr]), nil
+}
+
+func (hr *HandRank) UnmarshalText(text []byte) error {
+	for rank, description := range handRankDescriptions {
+		if description == string(text) {
+			*hr = rank
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown hand rank: %q", text)
+}
+
 func (bh PokerHand) String() string {
 	output := handRankDescriptions[bh.Rank] + ": "
 	for _, card := range bh.Cards {
@@ -125,6 +157,9 @@ func (bh PokerHand) String() string {
 }
 
 func (bh PokerHand) Compare(another PokerHand) CompareResult {
+	if bh.AceLow || another.AceLow {
+		return bh.aceAsOne().Compare(another.aceAsOne())
+	}
 	if bh.Rank > another.Rank {
 		return ResultHigher
 	}
@@ -134,8 +169,8 @@ func (bh PokerHand) Compare(another PokerHand) CompareResult {
 
 	switch bh.Rank {
 	case RankHighCard, RankFlush:
-		for index, card := range bh.Cards {
-			if ret := compareTwoCards(card, another.Cards[index]); ret != 0 {
+		for i := 0; i < len(bh.Cards) && i < len(another.Cards); i++ {
+			if ret := compareTwoCards(bh.Cards[i], another.Cards[i]); ret != 0 {
 				return ret
 			}
 		}
@@ -145,13 +180,19 @@ func (bh PokerHand) Compare(another PokerHand) CompareResult {
 		if ret := compareTwoCards(bh.Cards[0], another.Cards[0]); ret != 0 {
 			return ret
 		}
-		for i := 2; i < len(bh.Cards); i++ {
+		for i := 2; i < len(bh.Cards) && i < len(another.Cards); i++ {
 			if ret := compareTwoCards(bh.Cards[i], another.Cards[i]); ret != 0 {
 				return ret
 			}
 		}
 		return ResultIdentical
 
+	case RankFullHouse:
+		if ret := compareTwoCards(bh.Cards[0], another.Cards[0]); ret != 0 {
+			return ret
+		}
+		return compareTwoCards(bh.Cards[3], another.Cards[3])
+
 	case RankTwoParis, RankFourOfAKind:
 		if ret := compareTwoCards(bh.Cards[0], another.Cards[0]); ret != 0 {
 			return ret
@@ -159,7 +200,7 @@ func (bh PokerHand) Compare(another PokerHand) CompareResult {
 		if ret := compareTwoCards(bh.Cards[2], another.Cards[2]); ret != 0 {
 			return ret
 		}
-		if len(bh.Cards) > 4 {
+		if len(bh.Cards) > 4 && len(another.Cards) > 4 {
 			return compareTwoCards(bh.Cards[4], another.Cards[4])
 		}
 		return ResultIdentical
@@ -168,14 +209,14 @@ func (bh PokerHand) Compare(another PokerHand) CompareResult {
 		if ret := compareTwoCards(bh.Cards[0], another.Cards[0]); ret != 0 {
 			return ret
 		}
-		for i := 3; i < len(bh.Cards); i++ {
+		for i := 3; i < len(bh.Cards) && i < len(another.Cards); i++ {
 			if ret := compareTwoCards(bh.Cards[i], another.Cards[i]); ret != 0 {
 				return ret
 			}
 		}
 		return ResultIdentical
 
-	case RankStraight, RankStraightFlush, RankRoyalFlush:
+	case RankStraight, RankStraightFlush, RankRoyalFlush, RankFiveOfAKind:
 		return compareTwoCards(bh.Cards[0], another.Cards[0])
 
 	default:
@@ -183,6 +224,18 @@ func (bh PokerHand) Compare(another PokerHand) CompareResult {
 	}
 }
 
+// aceAsOne 比较用的副本，A 换成 card.RankAceAsOne
+func (bh PokerHand) aceAsOne() PokerHand {
+	cards := make([]card.Card, len(bh.Cards))
+	for i, c := range bh.Cards {
+		if c.Rank == card.RankAce {
+			c.Rank = card.RankAceAsOne
+		}
+		cards[i] = c
+	}
+	return PokerHand{Rank: bh.Rank, Cards: cards}
+}
+
 func compareTwoCards(a, b card.Card) CompareResult {
 	switch {
 	case a.Rank > b.Rank:
@@ -200,6 +253,7 @@ func newDefaultEvaluatorManager() *simpleEvaluatorManager {
 		registered: make(map[HandRank]Evaluator),
 	}
 
+	em.Register(fiveOfAKindEvaluator{})
 	em.Register(&straightFlushEvaluator{isRoyalFlushEvaluator: true})
 	em.Register(&straightFlushEvaluator{isRoyalFlushEvaluator: false})
 	em.Register(fourOfAKindEvaluator{})
@@ -241,7 +295,7 @@ func (em *simpleEvaluatorManager) Evaluate(cards ...card.Card) PokerHand {
 }
 
 func (em *simpleEvaluatorManager) Find(rank HandRank) Evaluator {
-	return em.evaluators[rank]
+	return em.registered[rank]
 }
 
 func (sf *straightFlushEvaluator) MinimalCardCounts() int {
@@ -278,6 +332,14 @@ func (sf *straightFlushEvaluator) Evaluate(cards ...card.Card) (PokerHand, bool)
 	sort.Slice(flushCards, func(i, j int) bool {
 		return flushCards[i].Rank > flushCards[j].Rank
 	})
+	// 多副牌时去掉重复的牌
+	distinct := flushCards[:1]
+	for _, c := range flushCards[1:] {
+		if c.Rank != distinct[len(distinct)-1].Rank {
+			distinct = append(distinct, c)
+		}
+	}
+	flushCards = distinct
 
 	// 是否是顺子
 	var hasAce bool
@@ -344,7 +406,7 @@ func (four fourOfAKindEvaluator) Evaluate(cards ...card.Card) (PokerHand, bool)
 	Ranks := make(map[card.Rank][]card.Card)
 	for _, card := range cards {
 		Ranks[card.Rank] = append(Ranks[card.Rank], card)
-		if len(Ranks[card.Rank]) >= 4 {
+		if len(Ranks[card.Rank]) >= 4 && card.Rank > hitRank {
 			hitRank = card.Rank
 		}
 	}
@@ -353,13 +415,8 @@ func (four fourOfAKindEvaluator) Evaluate(cards ...card.Card) (PokerHand, bool)
 		return PokerHand{}, false
 	}
 	best := &PokerHand{
-		Rank: RankFourOfAKind,
-		Cards: []card.Card{
-			{Rank: hitRank, Suit: card.SuitClubs},
-			{Rank: hitRank, Suit: card.SuitDiamond},
-			{Rank: hitRank, Suit: card.SuitHearts},
-			{Rank: hitRank, Suit: card.SuitSpades},
-		},
+		Rank:  RankFourOfAKind,
+		Cards: append([]card.Card{}, Ranks[hitRank][:4]...),
 	}
 
 	var highCard card.Rank
@@ -370,10 +427,43 @@ func (four fourOfAKindEvaluator) Evaluate(cards ...card.Card) (PokerHand, bool)
 	}
 	if highCard > 0 {
 		best.Cards = append(best.Cards, Ranks[highCard][0])
+	} else if len(Ranks[hitRank]) > 4 { // 多副牌时踢脚可能和炸弹同点数
+		best.Cards = append(best.Cards, Ranks[hitRank][4])
 	}
 	return *best, true
 }
 
+func (fiveOfAKindEvaluator) MinimalCardCounts() int {
+	return 5
+}
+
+func (five fiveOfAKindEvaluator) Rank() HandRank {
+	return RankFiveOfAKind
+}
+
+func (five fiveOfAKindEvaluator) Evaluate(cards ...card.Card) (PokerHand, bool) {
+	if len(cards) < 5 {
+		return PokerHand{}, false
+	}
+
+	var hitRank card.Rank
+	Ranks := make(map[card.Rank][]card.Card)
+	for _, card := range cards {
+		Ranks[card.Rank] = append(Ranks[card.Rank], card)
+		if len(Ranks[card.Rank]) >= 5 && card.Rank > hitRank {
+			hitRank = card.Rank
+		}
+	}
+
+	if hitRank == 0 {
+		return PokerHand{}, false
+	}
+	return PokerHand{
+		Rank:  RankFiveOfAKind,
+		Cards: append([]card.Card{}, Ranks[hitRank][:5]...),
+	}, true
+}
+
 func (fh fullHouseEvaluator) MinimalCardCounts() int {
 	return 5
 }
@@ -694,7 +784,7 @@ func (high highCardEvaluator) Evaluate(cards ...card.Card) (PokerHand, bool) {
 		return *best, true
 
 	case 1:
-		best.Cards[0] = cards[0]
+		best.Cards = append(best.Cards, cards[0])
 		return *best, true
 
 	default:
@@ -710,6 +800,11 @@ func (high highCardEvaluator) Evaluate(cards ...card.Card) (PokerHand, bool) {
 	return *best, true
 }
 
+// NewEvaluatorManager 注册了所有标准牌型评估器的 EvaluatorManager
+func NewEvaluatorManager() EvaluatorManager {
+	return newDefaultEvaluatorManager()
+}
+
 func Register(e Evaluator) error {
 	return defaultEvaluatorManager.Register(e)
 }
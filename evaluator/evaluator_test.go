@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -189,6 +190,18 @@ func TestFullHouse(t *testing.T) {
 	assert.Equal(t, best.Cards[4].Rank, card.RankNine)
 }
 
+func TestCompareFullHouse(t *testing.T) {
+	em := NewEvaluatorManager()
+	cards, _ := card.ParseCards("Kh Kd Kc 2s 2h")
+	kings := em.Evaluate(cards...)
+	cards, _ = card.ParseCards("Qh Qd Qc As Ah")
+	queens := em.Evaluate(cards...)
+	cards, _ = card.ParseCards("Ks Kd Kc 3s 3h")
+	kingsOverThrees := em.Evaluate(cards...)
+	assert.Equal(t, ResultHigher, kings.Compare(queens))
+	assert.Equal(t, ResultLower, kings.Compare(kingsOverThrees))
+}
+
 func TestFlush(t *testing.T) {
 	evaluator := flushEvaluator{}
 	best, ok := evaluator.Evaluate(
@@ -432,3 +445,64 @@ func TestCompareHands(t *testing.T) {
 	assert.EqualValues(t, -1, h2.Compare(h1))
 	assert.EqualValues(t, 0, h1.Compare(h3))
 }
+
+func TestMultiDeckHands(t *testing.T) {
+	em := newDefaultEvaluatorManager()
+
+	five := em.Evaluate(
+		card.NewCard("Ts"),
+		card.NewCard("Ts"),
+		card.NewCard("Td"),
+		card.NewCard("Th"),
+		card.NewCard("Tc"),
+		card.NewCard("As"),
+		card.NewCard("Ks"),
+	)
+	assert.Equal(t, RankFiveOfAKind, five.Rank)
+	assert.Len(t, five.Cards, 5)
+
+	four := em.Evaluate(
+		card.NewCard("9s"),
+		card.NewCard("9s"),
+		card.NewCard("9d"),
+		card.NewCard("9h"),
+		card.NewCard("As"),
+		card.NewCard("2c"),
+	)
+	assert.Equal(t, RankFourOfAKind, four.Rank)
+	assert.Equal(t, []card.Card{card.NewCard("9s"), card.NewCard("9s"), card.NewCard("9d"), card.NewCard("9h"), card.NewCard("As")}, four.Cards)
+
+	straightFlush := em.Evaluate(
+		card.NewCard("9s"),
+		card.NewCard("9s"),
+		card.NewCard("8s"),
+		card.NewCard("7s"),
+		card.NewCard("6s"),
+		card.NewCard("5s"),
+	)
+	assert.Equal(t, RankStraightFlush, straightFlush.Rank)
+	assert.Equal(t, card.RankNine, straightFlush.Cards[0].Rank)
+
+	assert.Equal(t, ResultHigher, five.Compare(straightFlush))
+	assert.Equal(t, RankFiveOfAKind, em.Find(RankFiveOfAKind).Rank())
+}
+
+func TestNewEvaluatorManager(t *testing.T) {
+	em := NewEvaluatorManager()
+	hand := em.Evaluate(card.NewCard("As"), card.NewCard("Ad"), card.NewCard("Ks"))
+	assert.Equal(t, RankOnePair, hand.Rank)
+}
+
+func TestPokerHandJSON(t *testing.T) {
+	cards, _ := card.ParseCards("Kh Kd 4s 4c 8h")
+	hand := Evaluate(cards...)
+	data, err := json.Marshal(hand)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"rank":"Two Pair","cards":["Kh","Kd","4s","4c","8h"]}`, string(data))
+
+	var decoded PokerHand
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, hand, decoded)
+	assert.NotNil(t, json.Unmarshal([]byte(`{"rank":"Six Pair"}`), &decoded))
+	assert.Equal(t, "Full House", RankFullHouse.String())
+}
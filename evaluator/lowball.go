@@ -0,0 +1,132 @@
+package evaluator
+
+import (
+	"sort"
+
+	"github.com/openpoker-dev/contrib/card"
+)
+
+// LowballRule 低牌的规则
+type LowballRule int
+
+const (
+	// LowballDeuceToSeven 2-7 低牌，A 只算最大，顺子和同花都算，最好的牌是 23457
+	LowballDeuceToSeven LowballRule = iota
+	// LowballAceToFive A-5 低牌，A 只算最小，不计顺子和同花，最好的牌是 A2345
+	LowballAceToFive
+)
+
+var (
+	lowballDescriptions = map[LowballRule]string{
+		LowballDeuceToSeven: "2-7 Lowball",
+		LowballAceToFive:    "A-5 Lowball",
+	}
+)
+
+func (rule LowballRule) String() string {
+	return lowballDescriptions[rule]
+}
+
+// EvaluateLow 按低牌规则评估，返回的牌型用 PokerHand.Compare 比较时 ResultLower 表示更好
+// 超过五张牌时选出最小的五张组合，不会修改 cards
+func EvaluateLow(em EvaluatorManager, rule LowballRule, cards ...card.Card) PokerHand {
+	if len(cards) <= 5 {
+		return evaluateLowFive(em, rule, cards)
+	}
+
+	var best PokerHand
+	found := false
+	indexes := []int{0, 1, 2, 3, 4}
+	for {
+		five := make([]card.Card, 0, 5)
+		for _, i := range indexes {
+			five = append(five, cards[i])
+		}
+		if hand := evaluateLowFive(em, rule, five); !found || hand.Compare(best) == ResultLower {
+			best, found = hand, true
+		}
+
+		// 下一个组合
+		i := 4
+		for i >= 0 && indexes[i] == len(cards)-5+i {
+			i--
+		}
+		if i < 0 {
+			return best
+		}
+		indexes[i]++
+		for j := i + 1; j < 5; j++ {
+			indexes[j] = indexes[j-1] + 1
+		}
+	}
+}
+
+func evaluateLowFive(em EvaluatorManager, rule LowballRule, cards []card.Card) PokerHand {
+	if rule == LowballAceToFive {
+		return evaluateAceToFive(cards)
+	}
+
+	hand := em.Evaluate(append([]card.Card{}, cards...)...)
+	// 2-7 低牌中 A 只算最大，A2345 不是顺子
+	if (hand.Rank == RankStraight || hand.Rank == RankStraightFlush) && hand.Cards[4].Rank == card.RankAce {
+		wheel := byRank(append([]card.Card{}, cards...))
+		if hand.Rank == RankStraightFlush {
+			return PokerHand{Rank: RankFlush, Cards: wheel}
+		}
+		return PokerHand{Rank: RankHighCard, Cards: wheel}
+	}
+	return hand
+}
+
+// evaluateAceToFive 只按点数分组，A 算最小，牌按张数和点数从大到小排列
+func evaluateAceToFive(cards []card.Card) PokerHand {
+	if len(cards) == 0 {
+		return PokerHand{Rank: RankHighCard, Cards: []card.Card{}, AceLow: true}
+	}
+	groups := make(map[card.Rank][]card.Card)
+	for _, c := range cards {
+		rank := c.Rank
+		if rank == card.RankAce {
+			rank = card.RankAceAsOne
+		}
+		groups[rank] = append(groups[rank], c)
+	}
+
+	ranks := make([]card.Rank, 0, len(groups))
+	for rank := range groups {
+		ranks = append(ranks, rank)
+	}
+	sort.Slice(ranks, func(i, j int) bool {
+		a, b := groups[ranks[i]], groups[ranks[j]]
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return ranks[i] > ranks[j]
+	})
+
+	hand := PokerHand{Rank: RankHighCard, Cards: make([]card.Card, 0, len(cards)), AceLow: true}
+	for _, rank := range ranks {
+		hand.Cards = append(hand.Cards, groups[rank]...)
+	}
+
+	switch counts := len(groups[ranks[0]]); {
+	case counts >= 4:
+		hand.Rank = RankFourOfAKind
+	case counts == 3 && len(ranks) > 1 && len(groups[ranks[1]]) >= 2:
+		hand.Rank = RankFullHouse
+	case counts == 3:
+		hand.Rank = RankThreeOfAKind
+	case counts == 2 && len(ranks) > 1 && len(groups[ranks[1]]) == 2:
+		hand.Rank = RankTwoParis
+	case counts == 2:
+		hand.Rank = RankOnePair
+	}
+	return hand
+}
+
+func byRank(cards []card.Card) []card.Card {
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].Rank > cards[j].Rank
+	})
+	return cards
+}
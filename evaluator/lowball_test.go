@@ -0,0 +1,70 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func lowOf(t *testing.T, em EvaluatorManager, rule LowballRule, s string) PokerHand {
+	cards, err := card.ParseCards(s)
+	assert.Nil(t, err)
+	return EvaluateLow(em, rule, cards...)
+}
+
+func TestDeuceToSeven(t *testing.T) {
+	em := NewEvaluatorManager()
+	nuts := lowOf(t, em, LowballDeuceToSeven, "7h 5d 4c 3s 2h")
+	assert.Equal(t, RankHighCard, nuts.Rank)
+
+	// A2345 不是顺子，是 A 高
+	wheel := lowOf(t, em, LowballDeuceToSeven, "Ah 2d 3c 4s 5h")
+	assert.Equal(t, RankHighCard, wheel.Rank)
+	assert.Equal(t, card.RankAce, wheel.Cards[0].Rank)
+	assert.Equal(t, ResultLower, nuts.Compare(wheel))
+
+	// 顺子和同花都算
+	assert.Equal(t, RankStraight, lowOf(t, em, LowballDeuceToSeven, "3h 4d 5c 6s 7h").Rank)
+	assert.Equal(t, RankFlush, lowOf(t, em, LowballDeuceToSeven, "2h 4h 5h 7h 8h").Rank)
+	assert.Equal(t, RankFlush, lowOf(t, em, LowballDeuceToSeven, "Ah 2h 3h 4h 5h").Rank)
+
+	pair := lowOf(t, em, LowballDeuceToSeven, "2h 2d 3c 4s 5h")
+	assert.Equal(t, ResultHigher, pair.Compare(wheel))
+	assert.Equal(t, ResultLower, lowOf(t, em, LowballDeuceToSeven, "8h 6d 4c 3s 2h").Compare(lowOf(t, em, LowballDeuceToSeven, "8h 6d 5c 3s 2h")))
+
+	// 七张牌中选最小的五张
+	seven := lowOf(t, em, LowballDeuceToSeven, "Kh Kd 7c 5s 4h 3d 2c")
+	assert.Equal(t, ResultIdentical, seven.Compare(nuts))
+}
+
+func TestAceToFive(t *testing.T) {
+	em := NewEvaluatorManager()
+	wheel := lowOf(t, em, LowballAceToFive, "Ah 2h 3h 4h 5h")
+	assert.Equal(t, RankHighCard, wheel.Rank)
+	assert.Equal(t, card.RankFive, wheel.Cards[0].Rank)
+	assert.Equal(t, card.RankAce, wheel.Cards[4].Rank)
+	assert.True(t, wheel.AceLow)
+
+	assert.Equal(t, ResultLower, wheel.Compare(lowOf(t, em, LowballAceToFive, "6h 4d 3c 2s Ah")))
+	assert.Equal(t, ResultLower, lowOf(t, em, LowballAceToFive, "Kh Qd Jc 9s 8h").Compare(lowOf(t, em, LowballAceToFive, "Ah Ad 2c 3s 4h")))
+	assert.Equal(t, RankOnePair, lowOf(t, em, LowballAceToFive, "Ah Ad 2c 3s 4h").Rank)
+	assert.Equal(t, RankTwoParis, lowOf(t, em, LowballAceToFive, "Ah Ad 2c 2s 4h").Rank)
+	assert.Equal(t, RankFullHouse, lowOf(t, em, LowballAceToFive, "Ah Ad Ac 2s 2h").Rank)
+	assert.Equal(t, RankFourOfAKind, lowOf(t, em, LowballAceToFive, "Ah Ad Ac As 2h").Rank)
+	assert.Equal(t, "A-5 Lowball", LowballAceToFive.String())
+}
+
+func TestAceToFiveMarshal(t *testing.T) {
+	em := NewEvaluatorManager()
+	hand := lowOf(t, em, LowballAceToFive, "As 2d 3c 4h 6s")
+	data, err := json.Marshal(hand)
+	assert.Nil(t, err)
+
+	var decoded PokerHand
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, hand, decoded)
+	assert.Equal(t, ResultIdentical, decoded.Compare(hand))
+	assert.Equal(t, ResultLower, decoded.Compare(lowOf(t, em, LowballAceToFive, "7h 4d 3c 2s Ah")))
+}
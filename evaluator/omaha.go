@@ -0,0 +1,36 @@
+package evaluator
+
+import "github.com/openpoker-dev/contrib/card"
+
+// EvaluateOmaha 奥马哈的最大牌型，必须使用两张手牌和三张公共牌，不会修改 hole 和 board
+// 公共牌不足三张时返回零值
+func EvaluateOmaha(em EvaluatorManager, hole, board []card.Card) PokerHand {
+	var best PokerHand
+	found := false
+	for _, two := range combinations(hole, 2) {
+		for _, three := range combinations(board, 3) {
+			hand := em.Evaluate(append(two, three...)...)
+			if !found || hand.Compare(best) == ResultHigher {
+				best = hand
+				found = true
+			}
+		}
+	}
+	return best
+}
+
+// combinations 从 cards 中选出 k 张牌的所有组合
+func combinations(cards []card.Card, k int) [][]card.Card {
+	if k == 0 {
+		return [][]card.Card{{}}
+	}
+	var result [][]card.Card
+	for i := 0; i <= len(cards)-k; i++ {
+		for _, rest := range combinations(cards[i+1:], k-1) {
+			combo := make([]card.Card, 0, k)
+			combo = append(combo, cards[i])
+			result = append(result, append(combo, rest...))
+		}
+	}
+	return result
+}
@@ -0,0 +1,27 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateOmaha(t *testing.T) {
+	em := NewEvaluatorManager()
+	hole, _ := card.ParseCards("Ah Kh 2c 2d")
+	board, _ := card.ParseCards("Qh Jh Th 3s 2s")
+
+	// 只能用两张手牌，四张红桃里只有两张可用
+	original := append([]card.Card{}, hole...)
+	best := EvaluateOmaha(em, hole, board)
+	assert.Equal(t, RankRoyalFlush, best.Rank)
+	assert.Equal(t, original, hole)
+
+	// 手里有四张同花而公共牌只有两张同花时不成同花
+	hole, _ = card.ParseCards("Ah Kh Qh Jh")
+	board, _ = card.ParseCards("2h 3h 9c 9d 5s")
+	assert.Equal(t, RankOnePair, EvaluateOmaha(em, hole, board).Rank)
+
+	assert.Equal(t, PokerHand{}, EvaluateOmaha(em, hole, board[:2]))
+}
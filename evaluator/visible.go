@@ -0,0 +1,13 @@
+package evaluator
+
+import "github.com/openpoker-dev/contrib/card"
+
+// EvaluateVisible 评估部分亮出的牌（如梭哈的明牌）组成的最大牌面，不会修改 cards
+// 不足五张时只可能组成高牌、对子、两对、三条和炸弹，不计顺子和同花
+// 张数相同的两手明牌可以直接用 PokerHand.Compare 比较
+func EvaluateVisible(em EvaluatorManager, cards ...card.Card) PokerHand {
+	if len(cards) == 0 {
+		return PokerHand{Rank: RankHighCard, Cards: []card.Card{}}
+	}
+	return em.Evaluate(append([]card.Card{}, cards...)...)
+}
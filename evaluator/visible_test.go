@@ -0,0 +1,36 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateVisible(t *testing.T) {
+	em := NewEvaluatorManager()
+
+	one := EvaluateVisible(em, card.NewCard("7h"))
+	assert.Equal(t, PokerHand{Rank: RankHighCard, Cards: []card.Card{card.NewCard("7h")}}, one)
+	assert.Equal(t, ResultLower, one.Compare(EvaluateVisible(em, card.NewCard("8c"))))
+
+	cards, _ := card.ParseCards("Kh 9s 9d 2c")
+	pair := EvaluateVisible(em, cards...)
+	assert.Equal(t, RankOnePair, pair.Rank)
+	assert.Equal(t, []card.Card{card.NewCard("Kh"), card.NewCard("9s"), card.NewCard("9d"), card.NewCard("2c")}, cards)
+
+	// 四张同花连张不算顺子或同花
+	cards, _ = card.ParseCards("Ah Kh Qh Jh")
+	high := EvaluateVisible(em, cards...)
+	assert.Equal(t, RankHighCard, high.Rank)
+	assert.Equal(t, ResultLower, high.Compare(pair))
+
+	cards, _ = card.ParseCards("9h 9c 2d")
+	weaker, _ := card.ParseCards("9h 9c Qd 2d")
+	assert.Equal(t, ResultHigher, pair.Compare(EvaluateVisible(em, weaker...)))
+	assert.Equal(t, ResultIdentical, EvaluateVisible(em, cards...).Compare(EvaluateVisible(em, card.NewCard("9s"), card.NewCard("9d"), card.NewCard("2s"))))
+
+	trips, _ := card.ParseCards("5h 5c 5d")
+	assert.Equal(t, RankThreeOfAKind, EvaluateVisible(em, trips...).Rank)
+	assert.Equal(t, RankHighCard, EvaluateVisible(em).Rank)
+}
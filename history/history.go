@@ -0,0 +1,360 @@
+// Package history 牌局历史的结构化记录，用于纠纷处理、数据分析和导入导出
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+)
+
+type (
+	// Hand 一局牌的完整记录，JSON 格式由 Version 标识
+	Hand struct {
+		Version    int         `json:"version"`
+		ID         string      `json:"id,omitempty"`
+		Site       string      `json:"site,omitempty"`
+		Table      string      `json:"table,omitempty"`
+		Game       Game        `json:"game"`
+		Limit      Limit       `json:"limit"`
+		Currency   string      `json:"currency,omitempty"` // 为空时金额为筹码，否则为货币的最小单位（如美分）
+		SmallBlind int64       `json:"small_blind"`
+		BigBlind   int64       `json:"big_blind"`
+		Ante       int64       `json:"ante,omitempty"`
+		MaxSeats   int         `json:"max_seats,omitempty"`
+		Button     int         `json:"button"`
+		StartedAt  time.Time   `json:"started_at"`
+		Players    []Player    `json:"players"`
+		Posts      []Post      `json:"posts,omitempty"`
+		Actions    []Action    `json:"actions,omitempty"`
+		Board      []card.Card `json:"board,omitempty"`
+		Showdown   []Showdown  `json:"showdown,omitempty"`
+		Uncalled   *Uncalled   `json:"uncalled,omitempty"`
+		Pots       []Pot       `json:"pots,omitempty"`
+		Rake       int64       `json:"rake,omitempty"`
+	}
+
+	// Game 玩法
+	Game string
+
+	// Limit 下注结构
+	Limit string
+
+	// Street 下注轮
+	Street string
+
+	// ActionType 行动类型
+	ActionType string
+
+	// PostType 强制下注类型
+	PostType string
+
+	// Player 一局开始时入座的玩家
+	Player struct {
+		Seat  int         `json:"seat"`
+		Name  string      `json:"name"`
+		Stack int64       `json:"stack"`           // 开始时的筹码
+		Cards []card.Card `json:"cards,omitempty"` // 发到的手牌，未知时为空
+	}
+
+	// Post 强制下注，Dead 的筹码不计入本轮下注额
+	Post struct {
+		Seat   int      `json:"seat"`
+		Type   PostType `json:"type"`
+		Amount int64    `json:"amount"`
+		Dead   bool     `json:"dead,omitempty"`
+	}
+
+	// Action 玩家的行动
+	Action struct {
+		Street Street     `json:"street"`
+		Seat   int        `json:"seat"`
+		Type   ActionType `json:"type"`
+		Amount int64      `json:"amount,omitempty"` // 本次行动投入的筹码
+		To     int64      `json:"to,omitempty"`     // 下注和加注后本轮的总额
+		AllIn  bool       `json:"all_in,omitempty"`
+		// Discarded 换牌时换掉的牌，Drawn 为补发的牌，其他玩家的记录中可能只有张数
+		Discarded []card.Card `json:"discarded,omitempty"`
+		Drawn     []card.Card `json:"drawn,omitempty"`
+		At        time.Time   `json:"at"`
+	}
+
+	// Showdown 摊牌的座位
+	Showdown struct {
+		Seat  int                 `json:"seat"`
+		Cards []card.Card         `json:"cards"`
+		Hand  evaluator.PokerHand `json:"hand"`
+	}
+
+	// Uncalled 没有人跟注而退回的筹码
+	Uncalled struct {
+		Seat   int   `json:"seat"`
+		Amount int64 `json:"amount"`
+	}
+
+	// Pot 主池或边池的分配结果，第一个为主池
+	Pot struct {
+		Amount   int64    `json:"amount"`
+		Eligible []int    `json:"eligible,omitempty"`
+		Winners  []Winner `json:"winners"`
+	}
+
+	// Winner 赢得底池的座位
+	Winner struct {
+		Seat   int   `json:"seat"`
+		Amount int64 `json:"amount"`
+	}
+
+	// Encoder 把多局牌写成 JSON Lines，每行一局
+	Encoder struct {
+		enc *json.Encoder
+	}
+
+	// Decoder 逐局读取 JSON Lines 格式的牌局历史
+	Decoder struct {
+		dec *json.Decoder
+	}
+)
+
+// SchemaVersion 当前的 JSON 格式版本，格式不兼容时递增
+const SchemaVersion = 1
+
+const (
+	GameHoldem Game = "holdem"
+	GameOmaha  Game = "omaha"
+	GameStud   Game = "stud"
+	GameDraw   Game = "draw"
+)
+
+const (
+	LimitNo    Limit = "no-limit"
+	LimitPot   Limit = "pot-limit"
+	LimitFixed Limit = "fixed-limit"
+)
+
+const (
+	StreetPreflop  Street = "preflop"
+	StreetFlop     Street = "flop"
+	StreetTurn     Street = "turn"
+	StreetRiver    Street = "river"
+	StreetShowdown Street = "showdown"
+
+	// 七张梭哈
+	StreetThird   Street = "third street"
+	StreetFourth  Street = "fourth street"
+	StreetFifth   Street = "fifth street"
+	StreetSixth   Street = "sixth street"
+	StreetSeventh Street = "seventh street"
+
+	// 换牌，每次换牌之后是一轮下注
+	StreetPredraw    Street = "predraw"
+	StreetFirstDraw  Street = "first draw"
+	StreetSecondDraw Street = "second draw"
+	StreetThirdDraw  Street = "third draw"
+)
+
+const (
+	ActionFold    ActionType = "fold"
+	ActionCheck   ActionType = "check"
+	ActionCall    ActionType = "call"
+	ActionBet     ActionType = "bet"
+	ActionRaise   ActionType = "raise"
+	ActionBringIn ActionType = "bring-in"
+	ActionDraw    ActionType = "draw"
+)
+
+const (
+	PostAnte         PostType = "ante"
+	PostBigBlindAnte PostType = "big blind ante"
+	PostButtonAnte   PostType = "button ante"
+	PostSmallBlind   PostType = "small blind"
+	PostBigBlind     PostType = "big blind"
+	PostMissedSmall  PostType = "dead small blind"
+	PostMissedBig    PostType = "missed big blind"
+	PostStraddle     PostType = "straddle"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported hand history version")
+	ErrInvalidHand        = errors.New("invalid hand history")
+)
+
+// Player 按座位号查找玩家
+func (h *Hand) Player(seat int) (Player, bool) {
+	for _, p := range h.Players {
+		if p.Seat == seat {
+			return p, true
+		}
+	}
+	return Player{}, false
+}
+
+// Payouts 每个座位赢得的筹码，包括退回的筹码
+func (h *Hand) Payouts() map[int]int64 {
+	payouts := make(map[int]int64)
+	if h.Uncalled != nil {
+		payouts[h.Uncalled.Seat] += h.Uncalled.Amount
+	}
+	for _, pot := range h.Pots {
+		for _, w := range pot.Winners {
+			payouts[w.Seat] += w.Amount
+		}
+	}
+	return payouts
+}
+
+// Invested 每个座位投入的筹码，包括强制下注
+func (h *Hand) Invested() map[int]int64 {
+	invested := make(map[int]int64)
+	for _, p := range h.Posts {
+		invested[p.Seat] += p.Amount
+	}
+	for _, a := range h.Actions {
+		invested[a.Seat] += a.Amount
+	}
+	return invested
+}
+
+// Validate 检查记录的一致性：版本、座位、行动的座位和筹码守恒
+func (h *Hand) Validate() error {
+	if h.Version != SchemaVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, h.Version)
+	}
+	if len(h.Players) < 2 {
+		return fmt.Errorf("%w: at least two players", ErrInvalidHand)
+	}
+
+	seated := make(map[int]bool, len(h.Players))
+	for _, p := range h.Players {
+		if seated[p.Seat] {
+			return fmt.Errorf("%w: duplicate seat %d", ErrInvalidHand, p.Seat)
+		}
+		seated[p.Seat] = true
+		if p.Stack < 0 {
+			return fmt.Errorf("%w: negative stack on seat %d", ErrInvalidHand, p.Seat)
+		}
+	}
+	for _, p := range h.Posts {
+		if !seated[p.Seat] {
+			return fmt.Errorf("%w: post from empty seat %d", ErrInvalidHand, p.Seat)
+		}
+	}
+	for i, a := range h.Actions {
+		if !seated[a.Seat] {
+			return fmt.Errorf("%w: action %d from empty seat %d", ErrInvalidHand, i, a.Seat)
+		}
+		if a.Amount < 0 {
+			return fmt.Errorf("%w: action %d has negative amount", ErrInvalidHand, i)
+		}
+	}
+	for _, s := range h.Showdown {
+		if !seated[s.Seat] {
+			return fmt.Errorf("%w: showdown from empty seat %d", ErrInvalidHand, s.Seat)
+		}
+	}
+
+	// 赢得的筹码加上抽水等于所有投入的筹码
+	if len(h.Pots) > 0 {
+		var in, out int64
+		for _, amount := range h.Invested() {
+			in += amount
+		}
+		for _, amount := range h.Payouts() {
+			out += amount
+		}
+		if in != out+h.Rake {
+			return fmt.Errorf("%w: %d invested but %d paid out with %d rake", ErrInvalidHand, in, out, h.Rake)
+		}
+	}
+	return nil
+}
+
+// Clone 深拷贝
+func (h *Hand) Clone() *Hand {
+	clone := *h
+	clone.Players = nil
+	for _, p := range h.Players {
+		p.Cards = append([]card.Card(nil), p.Cards...)
+		clone.Players = append(clone.Players, p)
+	}
+	clone.Posts = append([]Post(nil), h.Posts...)
+	clone.Actions = nil
+	for _, a := range h.Actions {
+		a.Discarded = append([]card.Card(nil), a.Discarded...)
+		a.Drawn = append([]card.Card(nil), a.Drawn...)
+		clone.Actions = append(clone.Actions, a)
+	}
+	clone.Board = append([]card.Card(nil), h.Board...)
+	clone.Showdown = nil
+	for _, s := range h.Showdown {
+		s.Cards = append([]card.Card(nil), s.Cards...)
+		s.Hand.Cards = append([]card.Card(nil), s.Hand.Cards...)
+		clone.Showdown = append(clone.Showdown, s)
+	}
+	if h.Uncalled != nil {
+		uncalled := *h.Uncalled
+		clone.Uncalled = &uncalled
+	}
+	clone.Pots = nil
+	for _, pot := range h.Pots {
+		pot.Eligible = append([]int(nil), pot.Eligible...)
+		pot.Winners = append([]Winner(nil), pot.Winners...)
+		clone.Pots = append(clone.Pots, pot)
+	}
+	return &clone
+}
+
+// Marshal 序列化为 JSON，Version 为空时使用 SchemaVersion
+func Marshal(h *Hand) ([]byte, error) {
+	if h.Version == 0 {
+		clone := *h
+		clone.Version = SchemaVersion
+		h = &clone
+	}
+	return json.Marshal(h)
+}
+
+// Unmarshal 解析 JSON 并校验
+func Unmarshal(data []byte) (*Hand, error) {
+	h := &Hand{}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{enc: json.NewEncoder(w)}
+}
+
+func (e *Encoder) Encode(h *Hand) error {
+	if h.Version == 0 {
+		clone := *h
+		clone.Version = SchemaVersion
+		h = &clone
+	}
+	return e.enc.Encode(h)
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// Decode 读取并校验下一局，没有更多牌局时返回 io.EOF
+func (d *Decoder) Decode() (*Hand, error) {
+	h := &Hand{}
+	if err := d.dec.Decode(h); err != nil {
+		return nil, err
+	}
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,121 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func sample(t *testing.T) *Hand {
+	at := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	return &Hand{
+		Version:    SchemaVersion,
+		ID:         "1001",
+		Game:       GameHoldem,
+		Limit:      LimitNo,
+		SmallBlind: 5,
+		BigBlind:   10,
+		Button:     1,
+		StartedAt:  at,
+		Players: []Player{
+			{Seat: 1, Name: "alice", Stack: 1000, Cards: cardtest.Cards(t, "As Ad")},
+			{Seat: 2, Name: "bob", Stack: 1000, Cards: cardtest.Cards(t, "7c 2d")},
+		},
+		Posts: []Post{
+			{Seat: 1, Type: PostSmallBlind, Amount: 5},
+			{Seat: 2, Type: PostBigBlind, Amount: 10},
+		},
+		Actions: []Action{
+			{Street: StreetPreflop, Seat: 1, Type: ActionRaise, Amount: 25, To: 30, At: at.Add(time.Second)},
+			{Street: StreetPreflop, Seat: 2, Type: ActionCall, Amount: 20, At: at.Add(2 * time.Second)},
+			{Street: StreetFlop, Seat: 2, Type: ActionCheck, At: at.Add(3 * time.Second)},
+			{Street: StreetFlop, Seat: 1, Type: ActionBet, Amount: 40, To: 40, At: at.Add(4 * time.Second)},
+			{Street: StreetFlop, Seat: 2, Type: ActionFold, At: at.Add(5 * time.Second)},
+		},
+		Board:    cardtest.Cards(t, "Kh Qs 2s"),
+		Uncalled: &Uncalled{Seat: 1, Amount: 40},
+		Pots: []Pot{
+			{Amount: 60, Eligible: []int{1}, Winners: []Winner{{Seat: 1, Amount: 60}}},
+		},
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	h := sample(t)
+	h.Showdown = []Showdown{{Seat: 1, Cards: cardtest.Cards(t, "As Ad"), Hand: evaluator.Evaluate(cardtest.Cards(t, "As Ad Kh Qs 2s")...)}}
+	data, err := Marshal(h)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"version":1`)
+	assert.Contains(t, string(data), `"cards":["As","Ad"]`)
+	assert.Contains(t, string(data), `"rank":"One Pair"`)
+
+	decoded, err := Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, h, decoded)
+	assert.Equal(t, map[int]int64{1: 100}, decoded.Payouts())
+	assert.Equal(t, map[int]int64{1: 70, 2: 30}, decoded.Invested())
+
+	p, ok := decoded.Player(2)
+	assert.True(t, ok)
+	assert.Equal(t, "bob", p.Name)
+}
+
+func TestValidate(t *testing.T) {
+	h := sample(t)
+	h.Version = 2
+	_, err := Unmarshal([]byte(`{"version":2}`))
+	assert.True(t, errors.Is(err, ErrUnsupportedVersion))
+	assert.True(t, errors.Is(h.Validate(), ErrUnsupportedVersion))
+
+	h = sample(t)
+	h.Actions[0].Seat = 5
+	assert.True(t, errors.Is(h.Validate(), ErrInvalidHand))
+
+	h = sample(t)
+	h.Pots[0].Winners[0].Amount = 50
+	assert.True(t, errors.Is(h.Validate(), ErrInvalidHand))
+	h.Rake = 10
+	assert.Nil(t, h.Validate())
+
+	h = sample(t)
+	h.Players[1].Seat = 1
+	assert.True(t, errors.Is(h.Validate(), ErrInvalidHand))
+}
+
+func TestClone(t *testing.T) {
+	h := sample(t)
+	clone := h.Clone()
+	assert.Equal(t, h, clone)
+	clone.Players[0].Cards[0] = card.NewCard("2c")
+	clone.Uncalled.Amount = 1
+	clone.Pots[0].Winners[0].Seat = 2
+	assert.Equal(t, sample(t), h)
+}
+
+func TestStream(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	first := sample(t)
+	second := sample(t)
+	second.ID = "1002"
+	second.Version = 0
+	assert.Nil(t, enc.Encode(first))
+	assert.Nil(t, enc.Encode(second))
+
+	dec := NewDecoder(&buf)
+	h, err := dec.Decode()
+	assert.Nil(t, err)
+	assert.Equal(t, "1001", h.ID)
+	h, err = dec.Decode()
+	assert.Nil(t, err)
+	assert.Equal(t, "1002", h.ID)
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
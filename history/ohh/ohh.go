@@ -0,0 +1,613 @@
+// Package ohh Open Hand History (OHH) 格式的导入导出
+// 参见 https://hh-specs.handhistory.org ，只支持德州扑克和奥马哈
+package ohh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+)
+
+type (
+	// Document OHH 文件中的一局，手牌包在 ohh 字段中
+	Document struct {
+		OHH *Hand `json:"ohh"`
+	}
+
+	// Hand OHH 格式的一局牌
+	// 金额使用货币单位（如美元），筹码局和锦标赛为筹码数
+	Hand struct {
+		SpecVersion      string   `json:"spec_version"`
+		SiteName         string   `json:"site_name"`
+		NetworkName      string   `json:"network_name"`
+		InternalVersion  string   `json:"internal_version"`
+		Tournament       bool     `json:"tournament"`
+		GameNumber       string   `json:"game_number"`
+		StartDateUTC     string   `json:"start_date_utc"`
+		TableName        string   `json:"table_name"`
+		GameType         string   `json:"game_type"`
+		BetLimit         BetLimit `json:"bet_limit"`
+		TableSize        int      `json:"table_size"`
+		Currency         string   `json:"currency"`
+		DealerSeat       int      `json:"dealer_seat"`
+		SmallBlindAmount float64  `json:"small_blind_amount"`
+		BigBlindAmount   float64  `json:"big_blind_amount"`
+		AnteAmount       float64  `json:"ante_amount"`
+		HeroPlayerID     int      `json:"hero_player_id,omitempty"`
+		Flags            []string `json:"flags"`
+		Players          []Player `json:"players"`
+		Rounds           []Round  `json:"rounds"`
+		Pots             []Pot    `json:"pots"`
+	}
+
+	// BetLimit 下注结构，BetType 为 NL、PL 或 FL
+	BetLimit struct {
+		BetType string  `json:"bet_type"`
+		BetCap  float64 `json:"bet_cap"`
+	}
+
+	Player struct {
+		ID            int     `json:"id"`
+		Seat          int     `json:"seat"`
+		Name          string  `json:"name"`
+		Display       string  `json:"display,omitempty"`
+		StartingStack float64 `json:"starting_stack"`
+		IsSittingOut  bool    `json:"is_sitting_out,omitempty"`
+	}
+
+	// Round 一个下注轮，Cards 为本轮新发的公共牌
+	Round struct {
+		ID      int         `json:"id"`
+		Street  string      `json:"street"`
+		Cards   []card.Card `json:"cards,omitempty"`
+		Actions []Action    `json:"actions"`
+	}
+
+	// Action 玩家的行动，Amount 为本次行动投入的筹码
+	Action struct {
+		ActionNumber int         `json:"action_number"`
+		PlayerID     int         `json:"player_id"`
+		Action       string      `json:"action"`
+		Amount       float64     `json:"amount,omitempty"`
+		IsAllIn      bool        `json:"is_allin,omitempty"`
+		Cards        []card.Card `json:"cards,omitempty"`
+	}
+
+	// Pot 主池或边池，Amount 包括抽水
+	Pot struct {
+		Number     int         `json:"number"`
+		Amount     float64     `json:"amount"`
+		Rake       float64     `json:"rake"`
+		Jackpot    float64     `json:"jackpot"`
+		PlayerWins []PlayerWin `json:"player_wins"`
+	}
+
+	PlayerWin struct {
+		PlayerID  int     `json:"player_id"`
+		WinAmount float64 `json:"win_amount"`
+	}
+
+	// Encoder 把多局牌写成 OHH 文件，每局之间空一行
+	Encoder struct {
+		w io.Writer
+	}
+
+	// Decoder 逐局读取 OHH 文件
+	Decoder struct {
+		dec *json.Decoder
+		em  evaluator.EvaluatorManager
+	}
+)
+
+// SpecVersion 导出时使用的 OHH 版本
+const SpecVersion = "1.4.6"
+
+const (
+	GameHoldem = "Holdem"
+	GameOmaha  = "Omaha"
+
+	BetNoLimit    = "NL"
+	BetPotLimit   = "PL"
+	BetFixedLimit = "FL"
+
+	StreetPreflop  = "Preflop"
+	StreetFlop     = "Flop"
+	StreetTurn     = "Turn"
+	StreetRiver    = "River"
+	StreetShowdown = "Showdown"
+)
+
+const (
+	ActionDealtCard      = "Dealt Card"
+	ActionMucksCard      = "Mucks Card"
+	ActionShowsCards     = "Shows Cards"
+	ActionPostAnte       = "Post Ante"
+	ActionPostSB         = "Post SB"
+	ActionPostBB         = "Post BB"
+	ActionStraddle       = "Straddle"
+	ActionPostDead       = "Post Dead"
+	ActionPostExtraBlind = "Post Extra Blind"
+	ActionFold           = "Fold"
+	ActionCheck          = "Check"
+	ActionBet            = "Bet"
+	ActionRaise          = "Raise"
+	ActionCall           = "Call"
+	ActionAddedChips     = "Added Chips"
+	ActionSitsDown       = "Sits Down"
+	ActionStandsUp       = "Stands Up"
+	ActionAddedToPot     = "Added To Pot"
+)
+
+var (
+	ErrInvalidDocument = errors.New("invalid open hand history")
+	ErrUnsupportedGame = errors.New("unsupported game")
+
+	gameTypes = map[history.Game]string{
+		history.GameHoldem: GameHoldem,
+		history.GameOmaha:  GameOmaha,
+	}
+
+	// OHH 中的其他玩法，可以通过校验但无法转换
+	otherGameTypes = map[string]bool{"OmahaHiLo": true, "Stud": true, "StudHiLo": true, "Draw": true}
+
+	betTypes = map[history.Limit]string{
+		history.LimitNo:    BetNoLimit,
+		history.LimitPot:   BetPotLimit,
+		history.LimitFixed: BetFixedLimit,
+	}
+
+	streets = map[history.Street]string{
+		history.StreetPreflop: StreetPreflop,
+		history.StreetFlop:    StreetFlop,
+		history.StreetTurn:    StreetTurn,
+		history.StreetRiver:   StreetRiver,
+	}
+
+	// 每轮之后公共牌的张数
+	boardCards = map[string]int{StreetPreflop: 0, StreetFlop: 3, StreetTurn: 4, StreetRiver: 5}
+
+	knownStreets = map[string]bool{
+		StreetPreflop: true, StreetFlop: true, StreetTurn: true, StreetRiver: true, StreetShowdown: true,
+		"Third Street": true, "Fourth Street": true, "Fifth Street": true, "Sixth Street": true, "Seventh Street": true,
+	}
+
+	postActions = map[history.PostType]string{
+		history.PostAnte:         ActionPostAnte,
+		history.PostBigBlindAnte: ActionPostAnte,
+		history.PostButtonAnte:   ActionPostAnte,
+		history.PostSmallBlind:   ActionPostSB,
+		history.PostBigBlind:     ActionPostBB,
+		history.PostMissedSmall:  ActionPostDead,
+		history.PostMissedBig:    ActionPostExtraBlind,
+		history.PostStraddle:     ActionStraddle,
+	}
+
+	actions = map[history.ActionType]string{
+		history.ActionFold:  ActionFold,
+		history.ActionCheck: ActionCheck,
+		history.ActionCall:  ActionCall,
+		history.ActionBet:   ActionBet,
+		history.ActionRaise: ActionRaise,
+	}
+
+	knownActions = map[string]bool{
+		ActionDealtCard: true, ActionMucksCard: true, ActionShowsCards: true, ActionPostAnte: true,
+		ActionPostSB: true, ActionPostBB: true, ActionStraddle: true, ActionPostDead: true,
+		ActionPostExtraBlind: true, ActionFold: true, ActionCheck: true, ActionBet: true,
+		ActionRaise: true, ActionCall: true, ActionAddedChips: true, ActionSitsDown: true,
+		ActionStandsUp: true, ActionAddedToPot: true,
+	}
+)
+
+// FromHistory 转换为 OHH 格式，玩家的 ID 为座位号
+// 没有人跟注而退回的筹码不计入底池
+func FromHistory(h *history.Hand) (*Hand, error) {
+	game, ok := gameTypes[h.Game]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedGame, h.Game)
+	}
+	c := converter{currency: h.Currency}
+	oh := &Hand{
+		SpecVersion:      SpecVersion,
+		SiteName:         h.Site,
+		GameNumber:       h.ID,
+		StartDateUTC:     h.StartedAt.UTC().Format(time.RFC3339),
+		TableName:        h.Table,
+		GameType:         game,
+		BetLimit:         BetLimit{BetType: betTypes[h.Limit]},
+		TableSize:        h.MaxSeats,
+		Currency:         h.Currency,
+		DealerSeat:       h.Button,
+		SmallBlindAmount: c.to(h.SmallBlind),
+		BigBlindAmount:   c.to(h.BigBlind),
+		AnteAmount:       c.to(h.Ante),
+		Flags:            []string{},
+		Players:          []Player{},
+		Rounds:           []Round{},
+		Pots:             []Pot{},
+	}
+	for _, p := range h.Players {
+		oh.Players = append(oh.Players, Player{ID: p.Seat, Seat: p.Seat, Name: p.Name, StartingStack: c.to(p.Stack)})
+		if p.Seat > oh.TableSize && h.MaxSeats == 0 {
+			oh.TableSize = p.Seat
+		}
+	}
+
+	number := 0
+	add := func(r *Round, a Action) {
+		number++
+		a.ActionNumber = number
+		r.Actions = append(r.Actions, a)
+	}
+
+	for street, name := range []history.Street{history.StreetPreflop, history.StreetFlop, history.StreetTurn, history.StreetRiver} {
+		r := Round{ID: street, Street: streets[name], Actions: []Action{}}
+		if street == 0 {
+			for _, p := range h.Posts {
+				add(&r, Action{PlayerID: p.Seat, Action: postActions[p.Type], Amount: c.to(p.Amount)})
+			}
+			for _, p := range h.Players {
+				if len(p.Cards) > 0 {
+					add(&r, Action{PlayerID: p.Seat, Action: ActionDealtCard, Cards: p.Cards})
+				}
+			}
+		} else if dealt := boardCards[r.Street]; dealt <= len(h.Board) {
+			r.Cards = h.Board[boardCards[oh.Rounds[street-1].Street]:dealt]
+		}
+
+		for _, a := range h.Actions {
+			if a.Street == name {
+				add(&r, Action{PlayerID: a.Seat, Action: actions[a.Type], Amount: c.to(a.Amount), IsAllIn: a.AllIn})
+			}
+		}
+		if street > 0 && len(r.Cards) == 0 && len(r.Actions) == 0 {
+			break
+		}
+		oh.Rounds = append(oh.Rounds, r)
+	}
+
+	if len(h.Showdown) > 0 {
+		r := Round{ID: len(oh.Rounds), Street: StreetShowdown, Actions: []Action{}}
+		for _, s := range h.Showdown {
+			add(&r, Action{PlayerID: s.Seat, Action: ActionShowsCards, Cards: s.Cards})
+		}
+		oh.Rounds = append(oh.Rounds, r)
+	}
+
+	// 抽水都记在主池上
+	for i, pot := range h.Pots {
+		p := Pot{Number: i, Amount: c.to(pot.Amount), PlayerWins: []PlayerWin{}}
+		if i == 0 {
+			p.Rake = c.to(h.Rake)
+			p.Amount = c.to(pot.Amount + h.Rake)
+		}
+		for _, w := range pot.Winners {
+			p.PlayerWins = append(p.PlayerWins, PlayerWin{PlayerID: w.Seat, WinAmount: c.to(w.Amount)})
+		}
+		oh.Pots = append(oh.Pots, p)
+	}
+	return oh, nil
+}
+
+// Validate 检查必需的字段、玩法、轮次和行动的名称，以及行动和赢家的玩家 ID
+func (oh *Hand) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidDocument, fmt.Sprintf(format, args...))
+	}
+	if oh.SpecVersion == "" {
+		return invalid("missing spec_version")
+	}
+	if _, err := time.Parse(time.RFC3339, oh.StartDateUTC); err != nil {
+		return invalid("bad start_date_utc %q", oh.StartDateUTC)
+	}
+	if oh.GameType != GameHoldem && oh.GameType != GameOmaha && !otherGameTypes[oh.GameType] {
+		return invalid("unknown game_type %q", oh.GameType)
+	}
+	switch oh.BetLimit.BetType {
+	case BetNoLimit, BetPotLimit, BetFixedLimit:
+	default:
+		return invalid("unknown bet_type %q", oh.BetLimit.BetType)
+	}
+	if len(oh.Players) == 0 {
+		return invalid("no players")
+	}
+
+	ids := make(map[int]bool, len(oh.Players))
+	seats := make(map[int]bool, len(oh.Players))
+	for _, p := range oh.Players {
+		if ids[p.ID] || seats[p.Seat] {
+			return invalid("duplicate player %d on seat %d", p.ID, p.Seat)
+		}
+		if p.StartingStack < 0 {
+			return invalid("negative starting_stack for player %d", p.ID)
+		}
+		ids[p.ID], seats[p.Seat] = true, true
+	}
+
+	last := 0
+	for _, r := range oh.Rounds {
+		if !knownStreets[r.Street] {
+			return invalid("unknown street %q", r.Street)
+		}
+		for _, a := range r.Actions {
+			if !knownActions[a.Action] {
+				return invalid("unknown action %q", a.Action)
+			}
+			if !ids[a.PlayerID] {
+				return invalid("action %d from unknown player %d", a.ActionNumber, a.PlayerID)
+			}
+			if a.ActionNumber <= last {
+				return invalid("action_number %d out of order", a.ActionNumber)
+			}
+			if a.Amount < 0 {
+				return invalid("action %d has negative amount", a.ActionNumber)
+			}
+			last = a.ActionNumber
+		}
+	}
+	for _, pot := range oh.Pots {
+		if pot.Amount < 0 || pot.Rake < 0 {
+			return invalid("pot %d has negative amount", pot.Number)
+		}
+		for _, w := range pot.PlayerWins {
+			if !ids[w.PlayerID] || w.WinAmount < 0 {
+				return invalid("bad win in pot %d for player %d", pot.Number, w.PlayerID)
+			}
+		}
+	}
+	return nil
+}
+
+// ToHistory 转换为牌局历史，摊牌的牌型使用 em 评估
+// 投入的筹码比底池多出的部分作为最后一个下注或加注的玩家没有被跟注而退回的筹码
+func (oh *Hand) ToHistory(em evaluator.EvaluatorManager) (*history.Hand, error) {
+	if err := oh.Validate(); err != nil {
+		return nil, err
+	}
+	h := &history.Hand{
+		Version: history.SchemaVersion,
+		ID:      oh.GameNumber,
+		Site:    oh.SiteName,
+		Table:   oh.TableName,
+		Button:  oh.DealerSeat,
+	}
+	for game, name := range gameTypes {
+		if name == oh.GameType {
+			h.Game = game
+		}
+	}
+	if h.Game == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedGame, oh.GameType)
+	}
+	for limit, name := range betTypes {
+		if name == oh.BetLimit.BetType {
+			h.Limit = limit
+		}
+	}
+	if !oh.Tournament {
+		h.Currency = oh.Currency
+	}
+	h.MaxSeats = oh.TableSize
+	h.StartedAt, _ = time.Parse(time.RFC3339, oh.StartDateUTC)
+	h.StartedAt = h.StartedAt.UTC()
+
+	c := converter{currency: h.Currency}
+	var err error
+	if h.SmallBlind, err = c.from(oh.SmallBlindAmount); err != nil {
+		return nil, err
+	}
+	if h.BigBlind, err = c.from(oh.BigBlindAmount); err != nil {
+		return nil, err
+	}
+	if h.Ante, err = c.from(oh.AnteAmount); err != nil {
+		return nil, err
+	}
+
+	seats := make(map[int]int, len(oh.Players))
+	for _, p := range oh.Players {
+		if p.IsSittingOut {
+			continue
+		}
+		stack, err := c.from(p.StartingStack)
+		if err != nil {
+			return nil, err
+		}
+		seats[p.ID] = p.Seat
+		h.Players = append(h.Players, history.Player{Seat: p.Seat, Name: p.Name, Stack: stack})
+	}
+
+	lastAggressor := 0
+	for _, r := range oh.Rounds {
+		street := history.Street("")
+		for s, name := range streets {
+			if name == r.Street {
+				street = s
+			}
+		}
+		if street != history.StreetPreflop {
+			h.Board = append(h.Board, r.Cards...)
+		}
+		committed := make(map[int]int64)
+		for _, a := range r.Actions {
+			seat, ok := seats[a.PlayerID]
+			if !ok {
+				continue
+			}
+			amount, err := c.from(a.Amount)
+			if err != nil {
+				return nil, err
+			}
+			switch a.Action {
+			case ActionDealtCard:
+				h.Players[playerIndex(h, seat)].Cards = append([]card.Card(nil), a.Cards...)
+			case ActionShowsCards:
+				h.Showdown = append(h.Showdown, history.Showdown{Seat: seat, Cards: append([]card.Card(nil), a.Cards...)})
+			case ActionPostAnte, ActionPostDead:
+				pt := history.PostAnte
+				if a.Action == ActionPostDead {
+					pt = history.PostMissedSmall
+				}
+				h.Posts = append(h.Posts, history.Post{Seat: seat, Type: pt, Amount: amount, Dead: true})
+			case ActionPostSB, ActionPostBB, ActionStraddle, ActionPostExtraBlind:
+				pt := map[string]history.PostType{
+					ActionPostSB:         history.PostSmallBlind,
+					ActionPostBB:         history.PostBigBlind,
+					ActionStraddle:       history.PostStraddle,
+					ActionPostExtraBlind: history.PostMissedBig,
+				}[a.Action]
+				committed[seat] += amount
+				h.Posts = append(h.Posts, history.Post{Seat: seat, Type: pt, Amount: amount})
+			case ActionFold, ActionCheck, ActionCall, ActionBet, ActionRaise:
+				if street == "" {
+					return nil, fmt.Errorf("%w: action %d on %s", ErrInvalidDocument, a.ActionNumber, r.Street)
+				}
+				action := history.Action{Street: street, Seat: seat, Amount: amount, AllIn: a.IsAllIn}
+				for t, name := range actions {
+					if name == a.Action {
+						action.Type = t
+					}
+				}
+				committed[seat] += amount
+				if a.Action == ActionBet || a.Action == ActionRaise {
+					action.To = committed[seat]
+					lastAggressor = seat
+				}
+				h.Actions = append(h.Actions, action)
+			}
+		}
+	}
+
+	var inPots int64
+	for _, pot := range oh.Pots {
+		var p history.Pot
+		for _, w := range pot.PlayerWins {
+			amount, err := c.from(w.WinAmount)
+			if err != nil {
+				return nil, err
+			}
+			p.Amount += amount
+			p.Winners = append(p.Winners, history.Winner{Seat: seats[w.PlayerID], Amount: amount})
+		}
+		rake, err := c.from(pot.Rake)
+		if err != nil {
+			return nil, err
+		}
+		total, err := c.from(pot.Amount)
+		if err != nil {
+			return nil, err
+		}
+		h.Rake += rake
+		inPots += total
+		h.Pots = append(h.Pots, p)
+	}
+	if len(h.Pots) > 0 {
+		var invested int64
+		for _, amount := range h.Invested() {
+			invested += amount
+		}
+		if uncalled := invested - inPots; uncalled > 0 && lastAggressor != 0 {
+			h.Uncalled = &history.Uncalled{Seat: lastAggressor, Amount: uncalled}
+		}
+	}
+
+	for i, s := range h.Showdown {
+		if h.Game == history.GameOmaha {
+			h.Showdown[i].Hand = evaluator.EvaluateOmaha(em, s.Cards, h.Board)
+		} else {
+			h.Showdown[i].Hand = em.Evaluate(append(append([]card.Card{}, s.Cards...), h.Board...)...)
+		}
+	}
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func playerIndex(h *history.Hand, seat int) int {
+	for i, p := range h.Players {
+		if p.Seat == seat {
+			return i
+		}
+	}
+	return -1
+}
+
+// Marshal 序列化为 OHH JSON
+func Marshal(h *history.Hand) ([]byte, error) {
+	oh, err := FromHistory(h)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Document{OHH: oh})
+}
+
+// Unmarshal 解析 OHH JSON 并校验
+func Unmarshal(data []byte) (*history.Hand, error) {
+	doc := Document{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.OHH == nil {
+		return nil, fmt.Errorf("%w: missing ohh", ErrInvalidDocument)
+	}
+	return doc.OHH.ToHistory(evaluator.NewEvaluatorManager())
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+func (e *Encoder) Encode(h *history.Hand) error {
+	data, err := Marshal(h)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n', '\n'))
+	return err
+}
+
+func NewDecoder(r io.Reader, em evaluator.EvaluatorManager) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r), em: em}
+}
+
+// Decode 读取并转换下一局，没有更多牌局时返回 io.EOF
+func (d *Decoder) Decode() (*history.Hand, error) {
+	doc := Document{}
+	if err := d.dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.OHH == nil {
+		return nil, fmt.Errorf("%w: missing ohh", ErrInvalidDocument)
+	}
+	return doc.OHH.ToHistory(d.em)
+}
+
+// converter 金额的换算，货币的最小单位为百分之一
+type converter struct {
+	currency string
+}
+
+func (c converter) to(amount int64) float64 {
+	if c.currency == "" {
+		return float64(amount)
+	}
+	return float64(amount) / 100
+}
+
+func (c converter) from(amount float64) (int64, error) {
+	if c.currency != "" {
+		amount *= 100
+	}
+	rounded := math.Round(amount)
+	if math.Abs(rounded-amount) > 1e-6 {
+		return 0, fmt.Errorf("%w: amount %v is not a whole number of chips or cents", ErrInvalidDocument, amount)
+	}
+	return int64(rounded), nil
+}
@@ -0,0 +1,222 @@
+package ohh
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func sample(t *testing.T) *history.Hand {
+	return &history.Hand{
+		Version:    history.SchemaVersion,
+		ID:         "1001",
+		Site:       "openpoker",
+		Table:      "Alpha",
+		Game:       history.GameHoldem,
+		Limit:      history.LimitNo,
+		Currency:   "USD",
+		SmallBlind: 5,
+		BigBlind:   10,
+		MaxSeats:   6,
+		Button:     1,
+		StartedAt:  time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+		Players: []history.Player{
+			{Seat: 1, Name: "alice", Stack: 1000, Cards: cardtest.Cards(t, "As Ad")},
+			{Seat: 2, Name: "bob", Stack: 1000, Cards: cardtest.Cards(t, "7c 2d")},
+			{Seat: 4, Name: "carol", Stack: 500},
+		},
+		Posts: []history.Post{
+			{Seat: 1, Type: history.PostSmallBlind, Amount: 5},
+			{Seat: 2, Type: history.PostBigBlind, Amount: 10},
+			{Seat: 4, Type: history.PostMissedBig, Amount: 10},
+			{Seat: 4, Type: history.PostMissedSmall, Amount: 5, Dead: true},
+		},
+		Actions: []history.Action{
+			{Street: history.StreetPreflop, Seat: 4, Type: history.ActionFold},
+			{Street: history.StreetPreflop, Seat: 1, Type: history.ActionRaise, Amount: 25, To: 30},
+			{Street: history.StreetPreflop, Seat: 2, Type: history.ActionCall, Amount: 20},
+			{Street: history.StreetFlop, Seat: 2, Type: history.ActionCheck},
+			{Street: history.StreetFlop, Seat: 1, Type: history.ActionBet, Amount: 40, To: 40},
+			{Street: history.StreetFlop, Seat: 2, Type: history.ActionCall, Amount: 40},
+			{Street: history.StreetTurn, Seat: 2, Type: history.ActionCheck},
+			{Street: history.StreetTurn, Seat: 1, Type: history.ActionCheck},
+			{Street: history.StreetRiver, Seat: 2, Type: history.ActionBet, Amount: 930, To: 930, AllIn: true},
+			{Street: history.StreetRiver, Seat: 1, Type: history.ActionCall, Amount: 930, AllIn: true},
+		},
+		Board: cardtest.Cards(t, "Kh Qs 2s Jd 9s"),
+		Showdown: []history.Showdown{
+			{Seat: 1, Cards: cardtest.Cards(t, "As Ad"), Hand: evaluator.Evaluate(cardtest.Cards(t, "As Ad Kh Qs 2s Jd 9s")...)},
+			{Seat: 2, Cards: cardtest.Cards(t, "7c 2d"), Hand: evaluator.Evaluate(cardtest.Cards(t, "7c 2d Kh Qs 2s Jd 9s")...)},
+		},
+		Pots: []history.Pot{
+			{Amount: 1990, Winners: []history.Winner{{Seat: 1, Amount: 1990}}},
+		},
+		Rake: 25,
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	h := sample(t)
+	data, err := Marshal(h)
+	assert.Nil(t, err)
+
+	decoded, err := Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, h, decoded)
+}
+
+func TestMarshalLayout(t *testing.T) {
+	oh, err := FromHistory(sample(t))
+	assert.Nil(t, err)
+	assert.Nil(t, oh.Validate())
+
+	assert.Equal(t, "2026-10-19T08:00:00Z", oh.StartDateUTC)
+	assert.Equal(t, GameHoldem, oh.GameType)
+	assert.Equal(t, BetNoLimit, oh.BetLimit.BetType)
+	assert.Equal(t, 0.05, oh.SmallBlindAmount)
+	assert.Equal(t, Player{ID: 4, Seat: 4, Name: "carol", StartingStack: 5}, oh.Players[2])
+
+	assert.Len(t, oh.Rounds, 5)
+	preflop := oh.Rounds[0]
+	assert.Equal(t, StreetPreflop, preflop.Street)
+	assert.Equal(t, Action{ActionNumber: 1, PlayerID: 1, Action: ActionPostSB, Amount: 0.05}, preflop.Actions[0])
+	assert.Equal(t, ActionPostExtraBlind, preflop.Actions[2].Action)
+	assert.Equal(t, ActionPostDead, preflop.Actions[3].Action)
+	assert.Equal(t, Action{ActionNumber: 5, PlayerID: 1, Action: ActionDealtCard, Cards: cardtest.Cards(t, "As Ad")}, preflop.Actions[4])
+	assert.Equal(t, Action{ActionNumber: 8, PlayerID: 1, Action: ActionRaise, Amount: 0.25}, preflop.Actions[7])
+	assert.Equal(t, cardtest.Cards(t, "Kh Qs 2s"), oh.Rounds[1].Cards)
+	assert.Equal(t, cardtest.Cards(t, "Jd"), oh.Rounds[2].Cards)
+	assert.Equal(t, cardtest.Cards(t, "9s"), oh.Rounds[3].Cards)
+	assert.Equal(t, StreetShowdown, oh.Rounds[4].Street)
+	assert.Equal(t, []Pot{{Amount: 20.15, Rake: 0.25, PlayerWins: []PlayerWin{{PlayerID: 1, WinAmount: 19.9}}}}, oh.Pots)
+
+	data, err := Marshal(sample(t))
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `{"ohh":{"spec_version":"1.4.6"`)
+	assert.Contains(t, string(data), `"cards":["Kh","Qs","2s"]`)
+	assert.Contains(t, string(data), `"action":"Shows Cards"`)
+}
+
+func TestImportThirdParty(t *testing.T) {
+	// 锦标赛的金额为筹码，河牌没有被跟注的下注需要推算退回
+	data := `{"ohh": {
+		"spec_version": "1.4.6", "site_name": "ACR", "network_name": "WPN", "internal_version": "1",
+		"tournament": true, "game_number": "T42", "start_date_utc": "2021-05-01T12:00:00+02:00",
+		"table_name": "T 1", "game_type": "Omaha", "bet_limit": {"bet_type": "PL", "bet_cap": 0},
+		"table_size": 6, "currency": "USD", "dealer_seat": 3,
+		"small_blind_amount": 50, "big_blind_amount": 100, "ante_amount": 10,
+		"hero_player_id": 7, "flags": [],
+		"players": [
+			{"id": 7, "seat": 3, "name": "Hero", "starting_stack": 5000},
+			{"id": 8, "seat": 5, "name": "Villain", "starting_stack": 3000},
+			{"id": 9, "seat": 6, "name": "Away", "starting_stack": 3000, "is_sitting_out": true}
+		],
+		"rounds": [
+			{"id": 0, "street": "Preflop", "actions": [
+				{"action_number": 1, "player_id": 7, "action": "Post Ante", "amount": 10},
+				{"action_number": 2, "player_id": 8, "action": "Post Ante", "amount": 10},
+				{"action_number": 3, "player_id": 7, "action": "Post SB", "amount": 50},
+				{"action_number": 4, "player_id": 8, "action": "Post BB", "amount": 100},
+				{"action_number": 5, "player_id": 7, "action": "Dealt Card", "cards": ["Ah", "Kh", "2c", "2d"]},
+				{"action_number": 6, "player_id": 7, "action": "Raise", "amount": 250},
+				{"action_number": 7, "player_id": 8, "action": "Call", "amount": 200}
+			]},
+			{"id": 1, "street": "Flop", "cards": ["Qh", "Jh", "Th"], "actions": [
+				{"action_number": 8, "player_id": 8, "action": "Check"},
+				{"action_number": 9, "player_id": 7, "action": "Bet", "amount": 600},
+				{"action_number": 10, "player_id": 8, "action": "Fold"}
+			]}
+		],
+		"pots": [{"number": 0, "amount": 620, "rake": 0, "jackpot": 0, "player_wins": [{"player_id": 7, "win_amount": 620}]}]
+	}}`
+	h, err := Unmarshal([]byte(data))
+	assert.Nil(t, err)
+	assert.Equal(t, "ACR", h.Site)
+	assert.Equal(t, history.GameOmaha, h.Game)
+	assert.Equal(t, history.LimitPot, h.Limit)
+	assert.Equal(t, "", h.Currency)
+	assert.Equal(t, int64(10), h.Ante)
+	assert.Equal(t, time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC), h.StartedAt)
+	assert.Len(t, h.Players, 2)
+	assert.Equal(t, cardtest.Cards(t, "Ah Kh 2c 2d"), h.Players[0].Cards)
+	assert.Equal(t, history.Post{Seat: 3, Type: history.PostAnte, Amount: 10, Dead: true}, h.Posts[0])
+	assert.Equal(t, history.Action{Street: history.StreetPreflop, Seat: 3, Type: history.ActionRaise, Amount: 250, To: 300}, h.Actions[0])
+	assert.Equal(t, cardtest.Cards(t, "Qh Jh Th"), h.Board)
+	assert.Equal(t, &history.Uncalled{Seat: 3, Amount: 600}, h.Uncalled)
+	assert.Equal(t, []history.Pot{{Amount: 620, Winners: []history.Winner{{Seat: 3, Amount: 620}}}}, h.Pots)
+}
+
+func TestValidate(t *testing.T) {
+	for name, mutate := range map[string]func(*Hand){
+		"spec version": func(oh *Hand) { oh.SpecVersion = "" },
+		"start date":   func(oh *Hand) { oh.StartDateUTC = "yesterday" },
+		"game type":    func(oh *Hand) { oh.GameType = "Pineapple" },
+		"bet type":     func(oh *Hand) { oh.BetLimit.BetType = "CAP" },
+		"players":      func(oh *Hand) { oh.Players = nil },
+		"duplicate":    func(oh *Hand) { oh.Players[1].Seat = 1 },
+		"street":       func(oh *Hand) { oh.Rounds[1].Street = "Second Flop" },
+		"action":       func(oh *Hand) { oh.Rounds[1].Actions[0].Action = "Dance" },
+		"player":       func(oh *Hand) { oh.Rounds[1].Actions[0].PlayerID = 3 },
+		"order":        func(oh *Hand) { oh.Rounds[1].Actions[0].ActionNumber = 1 },
+		"amount":       func(oh *Hand) { oh.Rounds[1].Actions[1].Amount = -1 },
+		"winner":       func(oh *Hand) { oh.Pots[0].PlayerWins[0].PlayerID = 3 },
+	} {
+		oh, err := FromHistory(sample(t))
+		assert.Nil(t, err)
+		mutate(oh)
+		assert.True(t, errors.Is(oh.Validate(), ErrInvalidDocument), name)
+		_, err = oh.ToHistory(evaluator.NewEvaluatorManager())
+		assert.True(t, errors.Is(err, ErrInvalidDocument), name)
+	}
+
+	oh, _ := FromHistory(sample(t))
+	oh.GameType = "Stud"
+	assert.Nil(t, oh.Validate())
+	_, err := oh.ToHistory(evaluator.NewEvaluatorManager())
+	assert.True(t, errors.Is(err, ErrUnsupportedGame))
+
+	h := sample(t)
+	h.Game = history.GameDraw
+	_, err = FromHistory(h)
+	assert.True(t, errors.Is(err, ErrUnsupportedGame))
+
+	// 筹码不是整数
+	oh, _ = FromHistory(sample(t))
+	oh.Currency = ""
+	_, err = oh.ToHistory(evaluator.NewEvaluatorManager())
+	assert.True(t, errors.Is(err, ErrInvalidDocument))
+
+	_, err = Unmarshal([]byte(`{"hand": {}}`))
+	assert.True(t, errors.Is(err, ErrInvalidDocument))
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	h := sample(t)
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	assert.Nil(t, enc.Encode(h))
+	assert.Nil(t, enc.Encode(h))
+	assert.Equal(t, 2, strings.Count(buf.String(), "}\n\n"))
+
+	var doc Document
+	assert.Nil(t, json.Unmarshal(bytes.Split(buf.Bytes(), []byte("\n\n"))[0], &doc))
+	assert.Equal(t, "1001", doc.OHH.GameNumber)
+
+	dec := NewDecoder(&buf, evaluator.NewEvaluatorManager())
+	for i := 0; i < 2; i++ {
+		decoded, err := dec.Decode()
+		assert.Nil(t, err)
+		assert.Equal(t, h, decoded)
+	}
+	_, err := dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
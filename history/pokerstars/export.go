@@ -0,0 +1,441 @@
+// Package pokerstars PokerStars 文本格式的牌局历史
+package pokerstars
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+)
+
+type (
+	// Exporter 把牌局历史导出为 PokerStars 文本格式，牌使用 ASCII 格式，eg. [As Kd]
+	Exporter struct {
+		Hero string // 只显示该玩家的手牌，为空时显示所有已知的手牌
+	}
+
+	// writer 导出一局牌时的状态
+	writer struct {
+		w    *bufio.Writer
+		hand *history.Hand
+		err  error
+	}
+)
+
+// TimeLayout 牌局开始时间的格式，时间按 UTC 输出
+const TimeLayout = "2006/01/02 15:04:05"
+
+var (
+	ErrUnsupportedGame = errors.New("unsupported game")
+
+	gameNames = map[history.Game]string{
+		history.GameHoldem: "Hold'em",
+		history.GameOmaha:  "Omaha",
+	}
+
+	limitNames = map[history.Limit]string{
+		history.LimitNo:    "No Limit",
+		history.LimitPot:   "Pot Limit",
+		history.LimitFixed: "Limit",
+	}
+
+	currencySymbols = map[string]string{
+		"USD": "$",
+		"EUR": "€",
+		"GBP": "£",
+	}
+
+	boardStreets = []struct {
+		street history.Street
+		name   string
+		cards  int // 本街之后公共牌的张数
+	}{
+		{history.StreetPreflop, "HOLE CARDS", 0},
+		{history.StreetFlop, "FLOP", 3},
+		{history.StreetTurn, "TURN", 4},
+		{history.StreetRiver, "RIVER", 5},
+	}
+
+	summaryStreets = map[history.Street]string{
+		history.StreetPreflop: "before Flop",
+		history.StreetFlop:    "on the Flop",
+		history.StreetTurn:    "on the Turn",
+		history.StreetRiver:   "on the River",
+	}
+
+	singularRanks = map[card.Rank]string{
+		card.RankAceAsOne: "Ace", card.RankTwo: "Deuce", card.RankThree: "Three", card.RankFour: "Four",
+		card.RankFive: "Five", card.RankSix: "Six", card.RankSeven: "Seven", card.RankEight: "Eight",
+		card.RankNine: "Nine", card.RankTen: "Ten", card.RankJack: "Jack", card.RankQueen: "Queen",
+		card.RankKing: "King", card.RankAce: "Ace",
+	}
+
+	pluralRanks = map[card.Rank]string{
+		card.RankAceAsOne: "Aces", card.RankTwo: "Deuces", card.RankThree: "Threes", card.RankFour: "Fours",
+		card.RankFive: "Fives", card.RankSix: "Sixes", card.RankSeven: "Sevens", card.RankEight: "Eights",
+		card.RankNine: "Nines", card.RankTen: "Tens", card.RankJack: "Jacks", card.RankQueen: "Queens",
+		card.RankKing: "Kings", card.RankAce: "Aces",
+	}
+)
+
+// Export 导出一局牌，每局之后有两个空行，多局可以连续写入同一个文件
+func (e Exporter) Export(w io.Writer, h *history.Hand) error {
+	if _, exists := gameNames[h.Game]; !exists {
+		return fmt.Errorf("%w: %s", ErrUnsupportedGame, h.Game)
+	}
+	pw := &writer{w: bufio.NewWriter(w), hand: h}
+	pw.header()
+	pw.posts()
+	pw.streets(e.Hero)
+	pw.showdown()
+	pw.summary()
+	pw.printf("\n\n")
+	if pw.err != nil {
+		return pw.err
+	}
+	return pw.w.Flush()
+}
+
+// Format 导出为字符串
+func (e Exporter) Format(h *history.Hand) (string, error) {
+	var sb strings.Builder
+	if err := e.Export(&sb, h); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func (pw *writer) printf(format string, args ...interface{}) {
+	if pw.err != nil {
+		return
+	}
+	_, pw.err = fmt.Fprintf(pw.w, format, args...)
+}
+
+func (pw *writer) header() {
+	h := pw.hand
+	stakes := pw.amount(h.SmallBlind) + "/" + pw.amount(h.BigBlind)
+	if h.Currency != "" {
+		stakes += " " + h.Currency
+	}
+	pw.printf("PokerStars Hand #%s:  %s %s (%s) - %s UTC\n",
+		h.ID, gameNames[h.Game], limitNames[h.Limit], stakes, h.StartedAt.UTC().Format(TimeLayout))
+
+	maxSeats := h.MaxSeats
+	if maxSeats == 0 {
+		maxSeats = 6
+		for _, p := range h.Players {
+			if p.Seat > maxSeats {
+				maxSeats = 9
+			}
+		}
+	}
+	table := h.Table
+	if table == "" {
+		table = "1"
+	}
+	pw.printf("Table '%s' %d-max Seat #%d is the button\n", table, maxSeats, h.Button)
+	for _, p := range h.Players {
+		pw.printf("Seat %d: %s (%s in chips)\n", p.Seat, p.Name, pw.amount(p.Stack))
+	}
+}
+
+// posts 强制下注，同时补交的大盲和小盲合并为一行
+func (pw *writer) posts() {
+	posts := pw.hand.Posts
+	for i := 0; i < len(posts); i++ {
+		p := posts[i]
+		name := pw.name(p.Seat)
+		if p.Type == history.PostMissedBig && i+1 < len(posts) &&
+			posts[i+1].Type == history.PostMissedSmall && posts[i+1].Seat == p.Seat {
+			pw.printf("%s: posts small & big blinds %s\n", name, pw.amount(p.Amount+posts[i+1].Amount))
+			i++
+			continue
+		}
+		switch p.Type {
+		case history.PostAnte, history.PostBigBlindAnte, history.PostButtonAnte:
+			pw.printf("%s: posts the ante %s\n", name, pw.amount(p.Amount))
+		case history.PostSmallBlind, history.PostMissedSmall:
+			pw.printf("%s: posts small blind %s\n", name, pw.amount(p.Amount))
+		case history.PostBigBlind, history.PostMissedBig:
+			pw.printf("%s: posts big blind %s\n", name, pw.amount(p.Amount))
+		default:
+			pw.printf("%s: posts %s %s\n", name, p.Type, pw.amount(p.Amount))
+		}
+	}
+}
+
+// streets 按街输出公共牌和行动，没收的下注在最后一个有行动的街之后退回
+func (pw *writer) streets(hero string) {
+	h := pw.hand
+	lastStreet := history.StreetPreflop
+	if len(h.Actions) > 0 {
+		lastStreet = h.Actions[len(h.Actions)-1].Street
+	}
+
+	// 翻牌前的下注额从盲注和抓位开始
+	currentBet := int64(0)
+	for _, p := range h.Posts {
+		if !p.Dead && p.Amount > currentBet {
+			currentBet = p.Amount
+		}
+	}
+	for i, street := range boardStreets {
+		if street.cards > len(h.Board) {
+			break
+		}
+		if street.cards == 0 {
+			pw.printf("*** HOLE CARDS ***\n")
+			for _, p := range h.Players {
+				if len(p.Cards) > 0 && (hero == "" || hero == p.Name) {
+					pw.printf("Dealt to %s %s\n", p.Name, bracket(p.Cards))
+				}
+			}
+		} else {
+			previous := boardStreets[i-1].cards
+			pw.printf("*** %s *** ", street.name)
+			if previous > 0 {
+				pw.printf("%s ", bracket(h.Board[:previous]))
+			}
+			pw.printf("%s\n", bracket(h.Board[previous:street.cards]))
+			currentBet = 0
+		}
+
+		for _, a := range h.Actions {
+			if a.Street != street.street {
+				continue
+			}
+			pw.action(a, currentBet)
+			if a.To > currentBet {
+				currentBet = a.To
+			}
+		}
+		if street.street == lastStreet && h.Uncalled != nil {
+			pw.printf("Uncalled bet (%s) returned to %s\n", pw.amount(h.Uncalled.Amount), pw.name(h.Uncalled.Seat))
+		}
+	}
+	if len(h.Showdown) == 0 {
+		pw.collected()
+	}
+}
+
+func (pw *writer) action(a history.Action, currentBet int64) {
+	name := pw.name(a.Seat)
+	switch a.Type {
+	case history.ActionFold:
+		pw.printf("%s: folds", name)
+	case history.ActionCheck:
+		pw.printf("%s: checks", name)
+	case history.ActionCall:
+		pw.printf("%s: calls %s", name, pw.amount(a.Amount))
+	case history.ActionBet:
+		pw.printf("%s: bets %s", name, pw.amount(a.To))
+	case history.ActionRaise:
+		pw.printf("%s: raises %s to %s", name, pw.amount(a.To-currentBet), pw.amount(a.To))
+	case history.ActionBringIn:
+		pw.printf("%s: brings in for %s", name, pw.amount(a.Amount))
+	default:
+		pw.printf("%s: %s %s", name, a.Type, pw.amount(a.Amount))
+	}
+	if a.AllIn {
+		pw.printf(" and is all-in")
+	}
+	pw.printf("\n")
+}
+
+func (pw *writer) showdown() {
+	if len(pw.hand.Showdown) == 0 {
+		return
+	}
+	pw.printf("*** SHOW DOWN ***\n")
+	for _, s := range pw.hand.Showdown {
+		pw.printf("%s: shows %s (%s)\n", pw.name(s.Seat), bracket(s.Cards), Describe(s.Hand))
+	}
+	pw.collected()
+}
+
+// collected 赢家收下底池，有边池时从主池开始
+func (pw *writer) collected() {
+	pots := pw.hand.Pots
+	for i, pot := range pots {
+		for _, w := range pot.Winners {
+			pw.printf("%s collected %s from %s\n", pw.name(w.Seat), pw.amount(w.Amount), potName(i, len(pots)))
+		}
+	}
+}
+
+func (pw *writer) summary() {
+	h := pw.hand
+	pw.printf("*** SUMMARY ***\n")
+
+	var total int64
+	for _, pot := range h.Pots {
+		total += pot.Amount
+	}
+	pw.printf("Total pot %s ", pw.amount(total+h.Rake))
+	if len(h.Pots) > 1 {
+		for i, pot := range h.Pots {
+			pw.printf("%s %s. ", capitalize(potName(i, len(h.Pots))), pw.amount(pot.Amount))
+		}
+	}
+	pw.printf("| Rake %s\n", pw.amount(h.Rake))
+	if len(h.Board) > 0 {
+		pw.printf("Board %s\n", bracket(h.Board))
+	}
+
+	sb, bb := pw.blindSeats()
+	folded := make(map[int]history.Street)
+	for _, a := range h.Actions {
+		if a.Type == history.ActionFold {
+			folded[a.Seat] = a.Street
+		}
+	}
+	shown := make(map[int]history.Showdown)
+	for _, s := range h.Showdown {
+		shown[s.Seat] = s
+	}
+	won := make(map[int]int64)
+	for _, pot := range h.Pots {
+		for _, w := range pot.Winners {
+			won[w.Seat] += w.Amount
+		}
+	}
+	invested := h.Invested()
+
+	for _, p := range h.Players {
+		pw.printf("Seat %d: %s", p.Seat, p.Name)
+		switch p.Seat {
+		case h.Button:
+			pw.printf(" (button)")
+		}
+		switch p.Seat {
+		case sb:
+			pw.printf(" (small blind)")
+		case bb:
+			pw.printf(" (big blind)")
+		}
+
+		s, showed := shown[p.Seat]
+		street, didFold := folded[p.Seat]
+		switch {
+		case didFold:
+			pw.printf(" folded %s", summaryStreets[street])
+			if street == history.StreetPreflop && invested[p.Seat] == 0 {
+				pw.printf(" (didn't bet)")
+			}
+		case showed && won[p.Seat] > 0:
+			pw.printf(" showed %s and won (%s) with %s", bracket(s.Cards), pw.amount(won[p.Seat]), Describe(s.Hand))
+		case showed:
+			pw.printf(" showed %s and lost with %s", bracket(s.Cards), Describe(s.Hand))
+		case won[p.Seat] > 0:
+			pw.printf(" collected (%s)", pw.amount(won[p.Seat]))
+		}
+		pw.printf("\n")
+	}
+}
+
+// blindSeats 下小盲和大盲的座位，没有时为 0
+func (pw *writer) blindSeats() (int, int) {
+	var sb, bb int
+	for _, p := range pw.hand.Posts {
+		switch p.Type {
+		case history.PostSmallBlind:
+			sb = p.Seat
+		case history.PostBigBlind:
+			bb = p.Seat
+		}
+	}
+	return sb, bb
+}
+
+func (pw *writer) name(seat int) string {
+	if p, ok := pw.hand.Player(seat); ok {
+		return p.Name
+	}
+	return fmt.Sprintf("Seat %d", seat)
+}
+
+// amount 筹码按整数输出，货币按最小单位换算成两位小数
+func (pw *writer) amount(amount int64) string {
+	if pw.hand.Currency == "" {
+		return fmt.Sprintf("%d", amount)
+	}
+	return fmt.Sprintf("%s%d.%02d", currencySymbols[pw.hand.Currency], amount/100, amount%100)
+}
+
+func potName(index, pots int) string {
+	switch {
+	case pots == 1:
+		return "pot"
+	case index == 0:
+		return "main pot"
+	case pots == 2:
+		return "side pot"
+	default:
+		return fmt.Sprintf("side pot-%d", index)
+	}
+}
+
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// bracket 格式化为 [As Kd 7c]
+func bracket(cs []card.Card) string {
+	ascii := make([]string, 0, len(cs))
+	for _, c := range cs {
+		ascii = append(ascii, c.ASCII())
+	}
+	return "[" + strings.Join(ascii, " ") + "]"
+}
+
+// Describe PokerStars 的牌型描述，eg. "a pair of Aces" "two pair, Kings and Fours"
+func Describe(hand evaluator.PokerHand) string {
+	if len(hand.Cards) == 0 {
+		return ""
+	}
+	first := hand.Cards[0].Rank
+	switch hand.Rank {
+	case evaluator.RankHighCard:
+		return "high card " + singularRanks[first]
+	case evaluator.RankOnePair:
+		return "a pair of " + pluralRanks[first]
+	case evaluator.RankTwoParis:
+		return "two pair, " + pluralRanks[first] + " and " + pluralRanks[hand.Cards[2].Rank]
+	case evaluator.RankThreeOfAKind:
+		return "three of a kind, " + pluralRanks[first]
+	case evaluator.RankStraight:
+		return "a straight, " + singularRanks[lowest(hand.Cards)] + " to " + singularRanks[first]
+	case evaluator.RankFlush:
+		return "a flush, " + singularRanks[first] + " high"
+	case evaluator.RankFullHouse:
+		return "a full house, " + pluralRanks[first] + " full of " + pluralRanks[hand.Cards[3].Rank]
+	case evaluator.RankFourOfAKind:
+		return "four of a kind, " + pluralRanks[first]
+	case evaluator.RankStraightFlush:
+		return "a straight flush, " + singularRanks[lowest(hand.Cards)] + " to " + singularRanks[first]
+	case evaluator.RankRoyalFlush:
+		return "a Royal Flush"
+	default:
+		return strings.ToLower(hand.Rank.String())
+	}
+}
+
+// lowest 顺子中最小的牌，A2345 中的 A 算最小
+func lowest(cs []card.Card) card.Rank {
+	ranks := make([]card.Rank, 0, len(cs))
+	for _, c := range cs {
+		ranks = append(ranks, c.Rank)
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i] < ranks[j] })
+	if ranks[len(ranks)-1] == card.RankAce && ranks[0] == card.RankTwo {
+		return card.RankAce
+	}
+	return ranks[0]
+}
@@ -0,0 +1,167 @@
+package pokerstars
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func showdownHand(t *testing.T) *history.Hand {
+	at := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	return &history.Hand{
+		Version:    history.SchemaVersion,
+		ID:         "1001",
+		Table:      "Alpha",
+		Game:       history.GameHoldem,
+		Limit:      history.LimitNo,
+		SmallBlind: 5,
+		BigBlind:   10,
+		Button:     1,
+		StartedAt:  at,
+		Players: []history.Player{
+			{Seat: 1, Name: "alice", Stack: 1000, Cards: cardtest.Cards(t, "As Ad")},
+			{Seat: 2, Name: "bob", Stack: 1000, Cards: cardtest.Cards(t, "7c 2d")},
+		},
+		Posts: []history.Post{
+			{Seat: 1, Type: history.PostSmallBlind, Amount: 5},
+			{Seat: 2, Type: history.PostBigBlind, Amount: 10},
+		},
+		Actions: []history.Action{
+			{Street: history.StreetPreflop, Seat: 1, Type: history.ActionRaise, Amount: 25, To: 30},
+			{Street: history.StreetPreflop, Seat: 2, Type: history.ActionCall, Amount: 20},
+			{Street: history.StreetFlop, Seat: 2, Type: history.ActionCheck},
+			{Street: history.StreetFlop, Seat: 1, Type: history.ActionBet, Amount: 40, To: 40},
+			{Street: history.StreetFlop, Seat: 2, Type: history.ActionCall, Amount: 40},
+			{Street: history.StreetTurn, Seat: 2, Type: history.ActionCheck},
+			{Street: history.StreetTurn, Seat: 1, Type: history.ActionCheck},
+			{Street: history.StreetRiver, Seat: 2, Type: history.ActionBet, Amount: 930, To: 930, AllIn: true},
+			{Street: history.StreetRiver, Seat: 1, Type: history.ActionCall, Amount: 930, AllIn: true},
+		},
+		Board: cardtest.Cards(t, "Kh Qs 2s Jd 9s"),
+		Showdown: []history.Showdown{
+			{Seat: 1, Cards: cardtest.Cards(t, "As Ad"), Hand: evaluator.Evaluate(cardtest.Cards(t, "As Ad Kh Qs 2s Jd 9s")...)},
+			{Seat: 2, Cards: cardtest.Cards(t, "7c 2d"), Hand: evaluator.Evaluate(cardtest.Cards(t, "7c 2d Kh Qs 2s Jd 9s")...)},
+		},
+		Pots: []history.Pot{
+			{Amount: 2000, Eligible: []int{1, 2}, Winners: []history.Winner{{Seat: 1, Amount: 2000}}},
+		},
+	}
+}
+
+func TestExportShowdown(t *testing.T) {
+	text, err := Exporter{}.Format(showdownHand(t))
+	assert.Nil(t, err)
+	assert.Equal(t, `PokerStars Hand #1001:  Hold'em No Limit (5/10) - 2026/10/19 08:00:00 UTC
+Table 'Alpha' 6-max Seat #1 is the button
+Seat 1: alice (1000 in chips)
+Seat 2: bob (1000 in chips)
+alice: posts small blind 5
+bob: posts big blind 10
+*** HOLE CARDS ***
+Dealt to alice [As Ad]
+Dealt to bob [7c 2d]
+alice: raises 20 to 30
+bob: calls 20
+*** FLOP *** [Kh Qs 2s]
+bob: checks
+alice: bets 40
+bob: calls 40
+*** TURN *** [Kh Qs 2s] [Jd]
+bob: checks
+alice: checks
+*** RIVER *** [Kh Qs 2s Jd] [9s]
+bob: bets 930 and is all-in
+alice: calls 930 and is all-in
+*** SHOW DOWN ***
+alice: shows [As Ad] (a pair of Aces)
+bob: shows [7c 2d] (a pair of Deuces)
+alice collected 2000 from pot
+*** SUMMARY ***
+Total pot 2000 | Rake 0
+Board [Kh Qs 2s Jd 9s]
+Seat 1: alice (button) (small blind) showed [As Ad] and won (2000) with a pair of Aces
+Seat 2: bob (big blind) showed [7c 2d] and lost with a pair of Deuces
+
+
+`, text)
+}
+
+func TestExportFoldAndCurrency(t *testing.T) {
+	h := showdownHand(t)
+	h.Currency = "USD"
+	h.Players = append(h.Players, history.Player{Seat: 3, Name: "carol", Stack: 500})
+	h.Actions = []history.Action{
+		{Street: history.StreetPreflop, Seat: 3, Type: history.ActionFold},
+		{Street: history.StreetPreflop, Seat: 1, Type: history.ActionRaise, Amount: 25, To: 30},
+		{Street: history.StreetPreflop, Seat: 2, Type: history.ActionCall, Amount: 20},
+		{Street: history.StreetFlop, Seat: 2, Type: history.ActionCheck},
+		{Street: history.StreetFlop, Seat: 1, Type: history.ActionBet, Amount: 40, To: 40},
+		{Street: history.StreetFlop, Seat: 2, Type: history.ActionFold},
+	}
+	h.Board = cardtest.Cards(t, "Kh Qs 2s")
+	h.Showdown = nil
+	h.Uncalled = &history.Uncalled{Seat: 1, Amount: 40}
+	h.Pots = []history.Pot{{Amount: 58, Winners: []history.Winner{{Seat: 1, Amount: 58}}}}
+	h.Rake = 2
+
+	var buf bytes.Buffer
+	assert.Nil(t, Exporter{Hero: "alice"}.Export(&buf, h))
+	text := buf.String()
+	assert.Contains(t, text, "Hold'em No Limit ($0.05/$0.10 USD)")
+	assert.Contains(t, text, "Seat 3: carol ($5.00 in chips)")
+	assert.Contains(t, text, "Dealt to alice [As Ad]\ncarol: folds\n")
+	assert.NotContains(t, text, "Dealt to bob")
+	assert.Contains(t, text, "bob: folds\nUncalled bet ($0.40) returned to alice\nalice collected $0.58 from pot\n*** SUMMARY ***")
+	assert.Contains(t, text, "Total pot $0.60 | Rake $0.02\nBoard [Kh Qs 2s]\n")
+	assert.Contains(t, text, "Seat 1: alice (button) (small blind) collected ($0.58)\n")
+	assert.Contains(t, text, "Seat 2: bob (big blind) folded on the Flop\n")
+	assert.Contains(t, text, "Seat 3: carol folded before Flop (didn't bet)\n")
+	assert.NotContains(t, text, "TURN")
+}
+
+func TestExportSidePots(t *testing.T) {
+	h := showdownHand(t)
+	h.Pots = []history.Pot{
+		{Amount: 1500, Winners: []history.Winner{{Seat: 1, Amount: 1500}}},
+		{Amount: 500, Winners: []history.Winner{{Seat: 2, Amount: 500}}},
+	}
+	text, err := Exporter{}.Format(h)
+	assert.Nil(t, err)
+	assert.Contains(t, text, "alice collected 1500 from main pot\nbob collected 500 from side pot\n")
+	assert.Contains(t, text, "Total pot 2000 Main pot 1500. Side pot 500. | Rake 0\n")
+	assert.Contains(t, text, "Seat 2: bob (big blind) showed [7c 2d] and won (500) with a pair of Deuces")
+}
+
+func TestExportUnsupportedGame(t *testing.T) {
+	h := showdownHand(t)
+	h.Game = history.GameStud
+	_, err := Exporter{}.Format(h)
+	assert.True(t, errors.Is(err, ErrUnsupportedGame))
+}
+
+func TestDescribe(t *testing.T) {
+	for hand, expected := range map[string]string{
+		"As Kd 9c 7h 3s": "high card Ace",
+		"Ks Kd 9c 7h 3s": "a pair of Kings",
+		"Ks Kd 4c 4h 3s": "two pair, Kings and Fours",
+		"6s 6d 6c Ah 3s": "three of a kind, Sixes",
+		"As 2d 3c 4h 5s": "a straight, Ace to Five",
+		"Ts Jd Qc Kh As": "a straight, Ten to Ace",
+		"As Js 9s 7s 3s": "a flush, Ace high",
+		"As Ad Ac Kh Ks": "a full house, Aces full of Kings",
+		"9s 9d 9c 9h 3s": "four of a kind, Nines",
+		"5h 6h 7h 8h 9h": "a straight flush, Five to Nine",
+		"Th Jh Qh Kh Ah": "a Royal Flush",
+	} {
+		assert.Equal(t, expected, Describe(evaluator.Evaluate(cardtest.Cards(t, hand)...)), hand)
+	}
+	assert.Equal(t, "", Describe(evaluator.PokerHand{}))
+	assert.True(t, strings.HasPrefix(Describe(evaluator.Evaluate(cardtest.Cards(t, "2s 2d")...)), "a pair"))
+}
@@ -0,0 +1,669 @@
+package pokerstars
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+)
+
+type (
+	// Parser 逐局读取 PokerStars 格式的文本，GGPoker 使用相同的格式
+	// 出错后下一次 Next 会跳到下一局的开头继续解析
+	Parser struct {
+		scanner *bufio.Scanner
+		em      evaluator.EvaluatorManager
+		line    int
+		pending string // 已经读到的下一局的第一行
+		skip    bool   // 上一局出错，跳过剩余的行
+	}
+
+	// SyntaxError 无法解析的行
+	SyntaxError struct {
+		Line int
+		Text string
+		Err  error
+	}
+
+	// section 一局牌中的段落
+	section int
+
+	// parsing 解析中的一局牌
+	parsing struct {
+		hand      *history.Hand
+		header    int
+		section   section
+		street    history.Street
+		committed map[int]int64 // 本轮每个座位的下注额
+		shown     map[int]bool
+	}
+)
+
+const (
+	sectionSeats section = iota
+	sectionStreets
+	sectionShowdown
+	sectionSummary
+)
+
+// maxLineLength 一行的最大长度
+const maxLineLength = 1 << 20
+
+var (
+	ErrMalformedLine   = errors.New("malformed line")
+	ErrUnsupportedSite = errors.New("unsupported site")
+	ErrIncompleteHand  = errors.New("incomplete hand")
+
+	headerPatterns = []struct {
+		site    string
+		pattern *regexp.Regexp
+	}{
+		{"PokerStars", regexp.MustCompile(`^PokerStars (?:Zoom |Home Game )?(?:Hand|Game) #(\d+):\s*(.*)$`)},
+		{"GGPoker", regexp.MustCompile(`^Poker Hand #(\w+):\s*(.*)$`)},
+	}
+
+	// 其他站点的开头，目前不支持
+	foreignHeaders = []string{"#Game No :", "***** 888", "***** Hand History for Game", "Game #"}
+
+	stakesPattern   = regexp.MustCompile(`\(([^/()\s]+)/([^/()\s]+)(?: ([A-Z]{3}))?[^)]*\)`)
+	timePattern     = regexp.MustCompile(`(\d{4}/\d{1,2}/\d{1,2} \d{1,2}:\d{2}:\d{2})(?: ([A-Z]+))?`)
+	tablePattern    = regexp.MustCompile(`^Table '(.*)' (\d+)-max (?:\(Play Money\) )?Seat #(\d+) is the button`)
+	seatPattern     = regexp.MustCompile(`^Seat (\d+): (.+) \(([^ ()]+) in chips[^)]*\)(.*)$`)
+	postPattern     = regexp.MustCompile(`^posts (small blind|big blind|the ante|small & big blinds|straddle) (\S+)( and is all-in)?$`)
+	actionPattern   = regexp.MustCompile(`^(folds|checks|calls|bets|raises)(?: (\S+))?(?: to (\S+))?(?: \[(.*)\])?( and is all-in)?$`)
+	showPattern     = regexp.MustCompile(`^shows \[(.*)\](?: \(.*\))?$`)
+	streetPattern   = regexp.MustCompile(`^\*\*\* ([A-Z ]+) \*\*\*(.*)$`)
+	bracketPattern  = regexp.MustCompile(`\[([^\]]*)\]`)
+	uncalledPattern = regexp.MustCompile(`^Uncalled bet \((\S+)\) returned to (.+)$`)
+	collectPattern  = regexp.MustCompile(`^(.+) collected (\S+) from (pot|main pot|side pot(?:-(\d+))?)$`)
+	rakePattern     = regexp.MustCompile(`\| Rake (\S+)`)
+
+	// 与牌局无关的提示
+	noticePattern = regexp.MustCompile(` (?:is disconnected|is connected|has timed out(?: while disconnected)?|has returned|` +
+		`leaves the table|joins the table at seat #\d+|sits out|is sitting out|will be allowed to play after the button|` +
+		`was removed from the table.*|mucks hand|doesn't show hand|said, ".*")$`)
+
+	streetNames = map[string]history.Street{
+		"HOLE CARDS": history.StreetPreflop,
+		"FLOP":       history.StreetFlop,
+		"TURN":       history.StreetTurn,
+		"RIVER":      history.StreetRiver,
+	}
+
+	symbolCurrencies = map[string]string{
+		"$": "USD",
+		"€": "EUR",
+		"£": "GBP",
+	}
+
+	// 牌局时间使用的时区
+	timeZones = map[string]string{
+		"ET":  "America/New_York",
+		"CET": "Europe/Paris",
+		"WET": "Europe/Lisbon",
+		"AET": "Australia/Sydney",
+	}
+)
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %v: %q", e.Line, e.Err, e.Text)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// NewParser 从 r 中逐局读取牌局，摊牌的牌型使用 em 评估
+func NewParser(r io.Reader, em evaluator.EvaluatorManager) *Parser {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	return &Parser{scanner: scanner, em: em}
+}
+
+// Parse 解析文本中的所有牌局
+func Parse(r io.Reader) ([]*history.Hand, error) {
+	p := NewParser(r, evaluator.NewEvaluatorManager())
+	var hands []*history.Hand
+	for {
+		h, err := p.Next()
+		if err == io.EOF {
+			return hands, nil
+		}
+		if err != nil {
+			return hands, err
+		}
+		hands = append(hands, h)
+	}
+}
+
+// Next 读取下一局，没有更多牌局时返回 io.EOF，无法解析的行返回 *SyntaxError
+func (p *Parser) Next() (*history.Hand, error) {
+	header, err := p.header()
+	if err != nil {
+		return nil, err
+	}
+	ph, err := p.start(header)
+	if err != nil {
+		p.skip = true
+		return nil, err
+	}
+
+	for {
+		line, ok := p.next()
+		if !ok {
+			break
+		}
+		if isHeader(line) {
+			p.pending = line
+			break
+		}
+		if line == "" {
+			if ph.section == sectionSummary {
+				break
+			}
+			continue
+		}
+		if err := ph.parse(line); err != nil {
+			p.skip = true
+			return nil, &SyntaxError{Line: p.line, Text: line, Err: err}
+		}
+	}
+	if err := p.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if ph.section != sectionSummary {
+		return nil, &SyntaxError{Line: ph.header, Text: header, Err: ErrIncompleteHand}
+	}
+	return ph.finish(p.em)
+}
+
+// header 跳过空行找到一局的第一行
+func (p *Parser) header() (string, error) {
+	for {
+		line := p.pending
+		p.pending = ""
+		if line == "" {
+			var ok bool
+			if line, ok = p.next(); !ok {
+				if err := p.scanner.Err(); err != nil {
+					return "", err
+				}
+				return "", io.EOF
+			}
+		}
+		if isHeader(line) {
+			p.skip = false
+			return line, nil
+		}
+		if line == "" || p.skip {
+			continue
+		}
+		for _, prefix := range foreignHeaders {
+			if strings.HasPrefix(line, prefix) {
+				p.skip = true
+				return "", &SyntaxError{Line: p.line, Text: line, Err: ErrUnsupportedSite}
+			}
+		}
+		p.skip = true
+		return "", &SyntaxError{Line: p.line, Text: line, Err: fmt.Errorf("%w: expected hand header", ErrMalformedLine)}
+	}
+}
+
+func (p *Parser) next() (string, bool) {
+	if !p.scanner.Scan() {
+		return "", false
+	}
+	p.line++
+	line := strings.TrimSpace(p.scanner.Text())
+	if p.line == 1 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+	return line, true
+}
+
+func isHeader(line string) bool {
+	for _, header := range headerPatterns {
+		if header.pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// start 解析一局的第一行：编号、玩法、盲注和时间
+func (p *Parser) start(line string) (*parsing, error) {
+	fail := func(err error) (*parsing, error) {
+		return nil, &SyntaxError{Line: p.line, Text: line, Err: err}
+	}
+
+	h := &history.Hand{Version: history.SchemaVersion}
+	var rest string
+	for _, header := range headerPatterns {
+		if m := header.pattern.FindStringSubmatch(line); m != nil {
+			h.Site, h.ID, rest = header.site, m[1], m[2]
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(rest, "Hold'em"):
+		h.Game = history.GameHoldem
+	case strings.Contains(rest, "Omaha") && !strings.Contains(rest, "Hi/Lo"):
+		h.Game = history.GameOmaha
+	default:
+		return fail(ErrUnsupportedGame)
+	}
+	switch {
+	case strings.Contains(rest, "No Limit"):
+		h.Limit = history.LimitNo
+	case strings.Contains(rest, "Pot Limit"):
+		h.Limit = history.LimitPot
+	case strings.Contains(rest, "Limit"):
+		h.Limit = history.LimitFixed
+	default:
+		return fail(fmt.Errorf("%w: missing limit", ErrMalformedLine))
+	}
+
+	stakes := stakesPattern.FindStringSubmatch(rest)
+	if stakes == nil {
+		return fail(fmt.Errorf("%w: missing stakes", ErrMalformedLine))
+	}
+	h.Currency = stakes[3]
+	if symbol := currencySymbol(stakes[1]); symbol != "" && h.Currency == "" {
+		h.Currency = symbolCurrencies[symbol]
+	}
+	ph := &parsing{
+		hand:      h,
+		header:    p.line,
+		street:    history.StreetPreflop,
+		committed: make(map[int]int64),
+		shown:     make(map[int]bool),
+	}
+	var err error
+	if h.SmallBlind, err = ph.amount(stakes[1]); err != nil {
+		return fail(err)
+	}
+	if h.BigBlind, err = ph.amount(stakes[2]); err != nil {
+		return fail(err)
+	}
+
+	at := timePattern.FindStringSubmatch(rest)
+	if at == nil {
+		return fail(fmt.Errorf("%w: missing time", ErrMalformedLine))
+	}
+	loc := time.UTC
+	if name, ok := timeZones[at[2]]; ok {
+		if l, err := time.LoadLocation(name); err == nil {
+			loc = l
+		}
+	}
+	started, err := time.ParseInLocation("2006/1/2 15:04:05", at[1], loc)
+	if err != nil {
+		return fail(fmt.Errorf("%w: %v", ErrMalformedLine, err))
+	}
+	h.StartedAt = started.UTC()
+	return ph, nil
+}
+
+// parse 解析一局中除第一行外的一行
+func (ph *parsing) parse(line string) error {
+	h := ph.hand
+	if m := streetPattern.FindStringSubmatch(line); m != nil {
+		return ph.enter(m[1], m[2])
+	}
+
+	switch ph.section {
+	case sectionSeats:
+		if m := tablePattern.FindStringSubmatch(line); m != nil {
+			h.Table = m[1]
+			h.MaxSeats, _ = strconv.Atoi(m[2])
+			h.Button, _ = strconv.Atoi(m[3])
+			return nil
+		}
+		if m := seatPattern.FindStringSubmatch(line); m != nil {
+			return ph.seat(m)
+		}
+	case sectionSummary:
+		if m := rakePattern.FindStringSubmatch(line); m != nil {
+			rake, err := ph.amount(m[1])
+			h.Rake = rake
+			return err
+		}
+		if strings.HasPrefix(line, "Board [") || strings.HasPrefix(line, "Seat ") ||
+			strings.HasPrefix(line, "Total pot ") {
+			return nil
+		}
+		return fmt.Errorf("%w: unexpected summary line", ErrMalformedLine)
+	}
+
+	if noticePattern.MatchString(line) {
+		return nil
+	}
+	if strings.HasPrefix(line, "Dealt to ") {
+		return ph.dealt(strings.TrimPrefix(line, "Dealt to "))
+	}
+	if m := uncalledPattern.FindStringSubmatch(line); m != nil {
+		return ph.uncalled(m)
+	}
+	if m := collectPattern.FindStringSubmatch(line); m != nil {
+		return ph.collected(m)
+	}
+	if seat, rest, ok := ph.player(line, ": "); ok {
+		return ph.act(seat, rest)
+	}
+	return fmt.Errorf("%w: unexpected line", ErrMalformedLine)
+}
+
+// enter 进入新的段落，翻牌、转牌和河牌时记下公共牌
+func (ph *parsing) enter(name, rest string) error {
+	switch name {
+	case "SHOW DOWN":
+		ph.section = sectionShowdown
+		return nil
+	case "SUMMARY":
+		ph.section = sectionSummary
+		return nil
+	}
+	street, ok := streetNames[name]
+	if !ok {
+		return fmt.Errorf("%w: unsupported section %s", ErrMalformedLine, name)
+	}
+	if street != history.StreetPreflop {
+		var board []card.Card
+		for _, m := range bracketPattern.FindAllStringSubmatch(rest, -1) {
+			cs, err := parseCards(m[1])
+			if err != nil {
+				return err
+			}
+			board = append(board, cs...)
+		}
+		ph.hand.Board = board
+		ph.committed = make(map[int]int64)
+	}
+	ph.section = sectionStreets
+	ph.street = street
+	return nil
+}
+
+func (ph *parsing) seat(m []string) error {
+	if strings.Contains(m[4], "is sitting out") || strings.Contains(m[4], "out of hand") {
+		return nil
+	}
+	seat, _ := strconv.Atoi(m[1])
+	stack, err := ph.amount(m[3])
+	if err != nil {
+		return err
+	}
+	ph.hand.Players = append(ph.hand.Players, history.Player{Seat: seat, Name: m[2], Stack: stack})
+	return nil
+}
+
+// player 找到以玩家名和 sep 开头的行，名字可能包含空格，取最长的匹配
+func (ph *parsing) player(line, sep string) (int, string, bool) {
+	seat, length := 0, -1
+	for _, p := range ph.hand.Players {
+		if len(p.Name) > length && strings.HasPrefix(line, p.Name+sep) {
+			seat, length = p.Seat, len(p.Name)
+		}
+	}
+	if length < 0 {
+		return 0, "", false
+	}
+	return seat, line[length+len(sep):], true
+}
+
+func (ph *parsing) seatOf(name string) (int, error) {
+	for _, p := range ph.hand.Players {
+		if p.Name == name {
+			return p.Seat, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown player %s", ErrMalformedLine, name)
+}
+
+func (ph *parsing) dealt(rest string) error {
+	seat, cs, ok := ph.player(rest, " [")
+	if !ok {
+		// GGPoker 会给其他玩家输出不带牌的行
+		if _, err := ph.seatOf(rest); err == nil {
+			return nil
+		}
+		return fmt.Errorf("%w: unknown player", ErrMalformedLine)
+	}
+	// 第二组括号是新发的牌
+	cards, err := parseCards(strings.NewReplacer("[", "", "]", "").Replace("[" + cs))
+	if err != nil {
+		return err
+	}
+	ph.reveal(seat, cards)
+	return nil
+}
+
+// reveal 记下玩家的手牌
+func (ph *parsing) reveal(seat int, cards []card.Card) {
+	for i := range ph.hand.Players {
+		if ph.hand.Players[i].Seat == seat && len(ph.hand.Players[i].Cards) == 0 {
+			ph.hand.Players[i].Cards = cards
+		}
+	}
+}
+
+// act 玩家的强制下注、行动和亮牌
+func (ph *parsing) act(seat int, rest string) error {
+	h := ph.hand
+	if m := postPattern.FindStringSubmatch(rest); m != nil {
+		if ph.section != sectionSeats {
+			return fmt.Errorf("%w: post after hole cards", ErrMalformedLine)
+		}
+		amount, err := ph.amount(m[2])
+		if err != nil {
+			return err
+		}
+		switch m[1] {
+		case "small blind":
+			ph.post(history.Post{Seat: seat, Type: history.PostSmallBlind, Amount: amount})
+		case "big blind":
+			pt := history.PostBigBlind
+			for _, p := range h.Posts {
+				if p.Type == history.PostBigBlind {
+					pt = history.PostMissedBig
+				}
+			}
+			ph.post(history.Post{Seat: seat, Type: pt, Amount: amount})
+		case "the ante":
+			if h.Ante == 0 {
+				h.Ante = amount
+			}
+			ph.post(history.Post{Seat: seat, Type: history.PostAnte, Amount: amount, Dead: true})
+		case "small & big blinds":
+			big := h.BigBlind
+			if amount < big {
+				big = amount
+			}
+			ph.post(history.Post{Seat: seat, Type: history.PostMissedBig, Amount: big})
+			if amount > big {
+				ph.post(history.Post{Seat: seat, Type: history.PostMissedSmall, Amount: amount - big, Dead: true})
+			}
+		case "straddle":
+			ph.post(history.Post{Seat: seat, Type: history.PostStraddle, Amount: amount})
+		}
+		return nil
+	}
+
+	if m := showPattern.FindStringSubmatch(rest); m != nil {
+		cards, err := parseCards(m[1])
+		if err != nil {
+			return err
+		}
+		ph.reveal(seat, cards)
+		if !ph.shown[seat] {
+			ph.shown[seat] = true
+			h.Showdown = append(h.Showdown, history.Showdown{Seat: seat, Cards: cards})
+		}
+		return nil
+	}
+
+	m := actionPattern.FindStringSubmatch(rest)
+	if m == nil || ph.section != sectionStreets {
+		return fmt.Errorf("%w: unexpected action", ErrMalformedLine)
+	}
+	a := history.Action{Street: ph.street, Seat: seat, AllIn: m[5] != ""}
+	first, err := ph.optionalAmount(m[2])
+	if err != nil {
+		return err
+	}
+	to, err := ph.optionalAmount(m[3])
+	if err != nil {
+		return err
+	}
+	switch m[1] {
+	case "folds":
+		a.Type = history.ActionFold
+		if m[4] != "" {
+			cards, err := parseCards(m[4])
+			if err != nil {
+				return err
+			}
+			ph.reveal(seat, cards)
+		}
+	case "checks":
+		a.Type = history.ActionCheck
+	case "calls":
+		a.Type, a.Amount = history.ActionCall, first
+	case "bets":
+		a.Type, a.Amount, a.To = history.ActionBet, first, ph.committed[seat]+first
+	case "raises":
+		if to == 0 {
+			return fmt.Errorf("%w: raise without total", ErrMalformedLine)
+		}
+		a.Type, a.Amount, a.To = history.ActionRaise, to-ph.committed[seat], to
+	}
+	if a.Amount < 0 || (first == 0 && (a.Type == history.ActionCall || a.Type == history.ActionBet)) {
+		return fmt.Errorf("%w: bad amount", ErrMalformedLine)
+	}
+	ph.committed[seat] += a.Amount
+	h.Actions = append(h.Actions, a)
+	return nil
+}
+
+func (ph *parsing) post(p history.Post) {
+	if !p.Dead {
+		ph.committed[p.Seat] += p.Amount
+	}
+	ph.hand.Posts = append(ph.hand.Posts, p)
+}
+
+func (ph *parsing) uncalled(m []string) error {
+	amount, err := ph.amount(m[1])
+	if err != nil {
+		return err
+	}
+	seat, err := ph.seatOf(m[2])
+	if err != nil {
+		return err
+	}
+	ph.hand.Uncalled = &history.Uncalled{Seat: seat, Amount: amount}
+	return nil
+}
+
+// collected 赢得底池，主池的下标为 0，边池按编号排列
+func (ph *parsing) collected(m []string) error {
+	seat, err := ph.seatOf(m[1])
+	if err != nil {
+		return err
+	}
+	amount, err := ph.amount(m[2])
+	if err != nil {
+		return err
+	}
+	index := 0
+	switch {
+	case m[4] != "":
+		index, _ = strconv.Atoi(m[4])
+	case m[3] == "side pot":
+		index = 1
+	}
+	for len(ph.hand.Pots) <= index {
+		ph.hand.Pots = append(ph.hand.Pots, history.Pot{})
+	}
+	pot := &ph.hand.Pots[index]
+	pot.Amount += amount
+	pot.Winners = append(pot.Winners, history.Winner{Seat: seat, Amount: amount})
+	return nil
+}
+
+// finish 评估摊牌的牌型并校验整局
+func (ph *parsing) finish(em evaluator.EvaluatorManager) (*history.Hand, error) {
+	h := ph.hand
+	for i, s := range h.Showdown {
+		if h.Game == history.GameOmaha {
+			h.Showdown[i].Hand = evaluator.EvaluateOmaha(em, s.Cards, h.Board)
+		} else {
+			h.Showdown[i].Hand = em.Evaluate(append(append([]card.Card{}, s.Cards...), h.Board...)...)
+		}
+	}
+	if err := h.Validate(); err != nil {
+		return nil, &SyntaxError{Line: ph.header, Text: "hand #" + h.ID, Err: err}
+	}
+	return h, nil
+}
+
+// amount 筹码为整数，货币金额换算成最小单位
+func (ph *parsing) amount(s string) (int64, error) {
+	s = strings.ReplaceAll(strings.TrimPrefix(s, currencySymbol(s)), ",", "")
+	if ph.hand.Currency == "" {
+		amount, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || amount < 0 {
+			return 0, fmt.Errorf("%w: bad amount %s", ErrMalformedLine, s)
+		}
+		return amount, nil
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("%w: bad amount %s", ErrMalformedLine, s)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units < 0 {
+		return 0, fmt.Errorf("%w: bad amount %s", ErrMalformedLine, s)
+	}
+	cents, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil || cents < 0 {
+		return 0, fmt.Errorf("%w: bad amount %s", ErrMalformedLine, s)
+	}
+	return units*100 + cents, nil
+}
+
+func (ph *parsing) optionalAmount(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return ph.amount(s)
+}
+
+func currencySymbol(s string) string {
+	for symbol := range symbolCurrencies {
+		if strings.HasPrefix(s, symbol) {
+			return symbol
+		}
+	}
+	return ""
+}
+
+// parseCards 解析空格分隔的 ASCII 格式的牌
+func parseCards(s string) ([]card.Card, error) {
+	cards, err := card.ParseCards(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedLine, err)
+	}
+	return cards, nil
+}
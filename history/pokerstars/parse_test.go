@@ -0,0 +1,233 @@
+package pokerstars
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/stretchr/testify/assert"
+)
+
+const cashHand = `PokerStars Hand #208412345678:  Hold'em No Limit ($0.05/$0.10 USD) - 2020/01/14 21:30:15 ET [2020/01/14 21:30:15 ET]
+Table 'Procyon III' 6-max Seat #2 is the button
+Seat 1: Player One ($10 in chips)
+Seat 2: hero ($12.35 in chips)
+Seat 3: villain ($9.40 in chips)
+Seat 5: idle ($10 in chips) is sitting out
+villain: posts small blind $0.05
+Player One: posts big blind $0.10
+*** HOLE CARDS ***
+Dealt to hero [Ah Kh]
+hero: raises $0.20 to $0.30
+villain: calls $0.25
+Player One: folds
+idle has returned
+*** FLOP *** [Qh Jh 2c]
+villain: checks
+hero: bets $0.45
+villain: raises $0.90 to $1.35
+hero: raises $2.70 to $4.05
+villain: calls $2.70
+*** TURN *** [Qh Jh 2c] [3d]
+villain: checks
+hero: bets $8
+villain: calls $5.05 and is all-in
+Uncalled bet ($2.95) returned to hero
+*** RIVER *** [Qh Jh 2c 3d] [Th]
+*** SHOW DOWN ***
+villain: shows [Qs Qd] (three of a kind, Queens)
+hero: shows [Ah Kh] (a Royal Flush)
+hero collected $18.35 from pot
+*** SUMMARY ***
+Total pot $18.90 | Rake $0.55
+Board [Qh Jh 2c 3d Th]
+Seat 1: Player One (big blind) folded before Flop
+Seat 2: hero (button) showed [Ah Kh] and won ($18.35) with a Royal Flush
+Seat 3: villain (small blind) showed [Qs Qd] and lost with three of a kind, Queens
+
+
+
+`
+
+func TestParseCashHand(t *testing.T) {
+	hands, err := Parse(strings.NewReader(cashHand))
+	assert.Nil(t, err)
+	assert.Len(t, hands, 1)
+	h := hands[0]
+
+	assert.Equal(t, "PokerStars", h.Site)
+	assert.Equal(t, "208412345678", h.ID)
+	assert.Equal(t, history.GameHoldem, h.Game)
+	assert.Equal(t, history.LimitNo, h.Limit)
+	assert.Equal(t, "USD", h.Currency)
+	assert.Equal(t, int64(5), h.SmallBlind)
+	assert.Equal(t, int64(10), h.BigBlind)
+	assert.Equal(t, time.Date(2020, 1, 15, 2, 30, 15, 0, time.UTC), h.StartedAt)
+	assert.Equal(t, "Procyon III", h.Table)
+	assert.Equal(t, 6, h.MaxSeats)
+	assert.Equal(t, 2, h.Button)
+	assert.Equal(t, []history.Player{
+		{Seat: 1, Name: "Player One", Stack: 1000},
+		{Seat: 2, Name: "hero", Stack: 1235, Cards: cardtest.Cards(t, "Ah Kh")},
+		{Seat: 3, Name: "villain", Stack: 940, Cards: cardtest.Cards(t, "Qs Qd")},
+	}, h.Players)
+
+	assert.Equal(t, history.Action{Street: history.StreetPreflop, Seat: 2, Type: history.ActionRaise, Amount: 30, To: 30}, h.Actions[0])
+	assert.Equal(t, history.Action{Street: history.StreetPreflop, Seat: 3, Type: history.ActionCall, Amount: 25}, h.Actions[1])
+	assert.Equal(t, history.Action{Street: history.StreetFlop, Seat: 3, Type: history.ActionRaise, Amount: 135, To: 135}, h.Actions[5])
+	assert.Equal(t, history.Action{Street: history.StreetFlop, Seat: 2, Type: history.ActionRaise, Amount: 360, To: 405}, h.Actions[6])
+	assert.Equal(t, history.Action{Street: history.StreetTurn, Seat: 3, Type: history.ActionCall, Amount: 505, AllIn: true}, h.Actions[10])
+	assert.Equal(t, cardtest.Cards(t, "Qh Jh 2c 3d Th"), h.Board)
+	assert.Equal(t, &history.Uncalled{Seat: 2, Amount: 295}, h.Uncalled)
+
+	assert.Len(t, h.Showdown, 2)
+	assert.Equal(t, 3, h.Showdown[0].Seat)
+	assert.Equal(t, evaluator.RankThreeOfAKind, h.Showdown[0].Hand.Rank)
+	assert.Equal(t, evaluator.RankRoyalFlush, h.Showdown[1].Hand.Rank)
+	assert.Equal(t, []history.Pot{{Amount: 1835, Winners: []history.Winner{{Seat: 2, Amount: 1835}}}}, h.Pots)
+	assert.Equal(t, int64(55), h.Rake)
+	assert.Nil(t, h.Validate())
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	h := showdownHand(t)
+	h.Posts = append([]history.Post{
+		{Seat: 1, Type: history.PostAnte, Amount: 1, Dead: true},
+		{Seat: 2, Type: history.PostAnte, Amount: 1, Dead: true},
+	}, h.Posts...)
+	h.Ante = 1
+	h.Pots = []history.Pot{
+		{Amount: 1500, Winners: []history.Winner{{Seat: 1, Amount: 1500}}},
+		{Amount: 502, Winners: []history.Winner{{Seat: 2, Amount: 251}, {Seat: 1, Amount: 251}}},
+	}
+	text, err := Exporter{}.Format(h)
+	assert.Nil(t, err)
+
+	hands, err := Parse(strings.NewReader(text + text))
+	assert.Nil(t, err)
+	assert.Len(t, hands, 2)
+
+	h.Site = "PokerStars"
+	h.MaxSeats = 6
+	assert.Equal(t, h, hands[0])
+	assert.Equal(t, h, hands[1])
+}
+
+func TestParseDeadBlinds(t *testing.T) {
+	h := showdownHand(t)
+	h.Players = append(h.Players, history.Player{Seat: 4, Name: "dave", Stack: 500})
+	h.Posts = append(h.Posts,
+		history.Post{Seat: 4, Type: history.PostMissedBig, Amount: 10},
+		history.Post{Seat: 4, Type: history.PostMissedSmall, Amount: 5, Dead: true},
+	)
+	h.Actions = append([]history.Action{
+		{Street: history.StreetPreflop, Seat: 4, Type: history.ActionFold},
+	}, h.Actions...)
+	h.Pots[0].Amount += 15
+	h.Pots[0].Winners[0].Amount += 15
+	h.Pots[0].Eligible = nil
+
+	text, err := Exporter{}.Format(h)
+	assert.Nil(t, err)
+	assert.Contains(t, text, "dave: posts small & big blinds 15\n")
+
+	hands, err := Parse(strings.NewReader(text))
+	assert.Nil(t, err)
+	assert.Equal(t, h.Posts, hands[0].Posts)
+}
+
+func TestParseStreamWithErrors(t *testing.T) {
+	broken := strings.Replace(cashHand, "villain: checks\nhero: bets $0.45", "villain: checks\nhero: dances", 1)
+	text := cashHand + broken + "PokerStars Hand #1:  Hold'em No Limit (5/10) - 2020/01/14 21:30:15 UTC\n" + cashHand
+	p := NewParser(strings.NewReader(text), evaluator.NewEvaluatorManager())
+
+	h, err := p.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "208412345678", h.ID)
+
+	_, err = p.Next()
+	var syntax *SyntaxError
+	assert.True(t, errors.As(err, &syntax))
+	assert.True(t, errors.Is(err, ErrMalformedLine))
+	assert.Equal(t, 39+17, syntax.Line)
+	assert.Equal(t, "hero: dances", syntax.Text)
+	assert.Contains(t, err.Error(), `line 56: malformed line: unexpected action: "hero: dances"`)
+
+	// 被截断的一局
+	_, err = p.Next()
+	assert.True(t, errors.Is(err, ErrIncompleteHand))
+	assert.True(t, errors.As(err, &syntax))
+	assert.Equal(t, 39*2+1, syntax.Line)
+
+	h, err = p.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "208412345678", h.ID)
+
+	_, err = p.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestParseGGPokerOmaha(t *testing.T) {
+	text := `Poker Hand #HD12345: Omaha Pot Limit ($0.05/$0.10) - 2021/03/01 10:00:00
+Table 'Bluff' 6-max Seat #1 is the button
+Seat 1: 7a1b2c ($10 in chips)
+Seat 2: Hero ($10 in chips)
+7a1b2c: posts small blind $0.05
+Hero: posts big blind $0.10
+*** HOLE CARDS ***
+Dealt to 7a1b2c
+Dealt to Hero [Ah Kh 2c 2d]
+7a1b2c: calls $0.05
+Hero: checks
+*** FLOP *** [Qh Jh 3h]
+Hero: bets $0.20
+7a1b2c: calls $0.20
+*** TURN *** [Qh Jh 3h] [3s]
+Hero: checks
+7a1b2c: checks
+*** RIVER *** [Qh Jh 3h 3s] [9c]
+Hero: checks
+7a1b2c: checks
+*** SHOW DOWN ***
+Hero: shows [Ah Kh 2c 2d] (a flush, Ace high)
+7a1b2c: shows [9h 9d 4c 5c] (a full house, Nines full of Threes)
+7a1b2c collected $0.58 from pot
+*** SUMMARY ***
+Total pot $0.60 | Rake $0.02 | Jackpot $0 | Bingo $0
+Board [Qh Jh 3h 3s 9c]
+Seat 1: 7a1b2c (button) (small blind) showed [9h 9d 4c 5c] and won ($0.58) with a full house, Nines full of Threes
+Seat 2: Hero (big blind) showed [Ah Kh 2c 2d] and lost with a flush, Ace high
+`
+	hands, err := Parse(strings.NewReader(text))
+	assert.Nil(t, err)
+	h := hands[0]
+	assert.Equal(t, "GGPoker", h.Site)
+	assert.Equal(t, "HD12345", h.ID)
+	assert.Equal(t, history.GameOmaha, h.Game)
+	assert.Equal(t, history.LimitPot, h.Limit)
+	assert.Equal(t, "USD", h.Currency)
+	assert.Equal(t, evaluator.RankFlush, h.Showdown[0].Hand.Rank)
+	assert.Equal(t, evaluator.RankFullHouse, h.Showdown[1].Hand.Rank)
+	assert.Equal(t, int64(2), h.Rake)
+}
+
+func TestParseUnsupported(t *testing.T) {
+	_, err := Parse(strings.NewReader("#Game No : 123456789\n***** 888poker Hand History for Game 123456789 *****\n"))
+	assert.True(t, errors.Is(err, ErrUnsupportedSite))
+
+	_, err = Parse(strings.NewReader("PokerStars Hand #1:  Razz Limit (10/20) - 2020/01/14 21:30:15 ET\n"))
+	assert.True(t, errors.Is(err, ErrUnsupportedGame))
+
+	_, err = Parse(strings.NewReader("hello\n"))
+	assert.True(t, errors.Is(err, ErrMalformedLine))
+
+	// 筹码不守恒
+	broken := strings.Replace(cashHand, "hero collected $18.35", "hero collected $18.36", 1)
+	_, err = Parse(strings.NewReader(broken))
+	assert.True(t, errors.Is(err, history.ErrInvalidHand))
+}
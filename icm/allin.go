@@ -0,0 +1,159 @@
+package icm
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+)
+
+type (
+	// AllIn 两个玩家全下摊牌的场景
+	AllIn struct {
+		Stacks  []float64 // 这手牌开始时每个玩家的筹码，包括已经放入底池的
+		Hero    int
+		Villain int
+		Posted  float64 // 英雄已经放入底池的筹码，弃牌时输给对手
+		Dead    float64 // 其他玩家放入底池的筹码，已经从他们的筹码中扣除
+		Win     float64 // 英雄摊牌获胜的概率
+		Tie     float64 // 平分底池的概率
+	}
+)
+
+// Outcomes 英雄赢、输、平分和弃牌之后每个玩家的筹码
+func (a AllIn) Outcomes() (win, lose, tie, fold []float64, err error) {
+	n := len(a.Stacks)
+	if a.Hero < 0 || a.Hero >= n || a.Villain < 0 || a.Villain >= n || a.Hero == a.Villain ||
+		a.Posted < 0 || a.Posted > a.Stacks[a.Hero] || a.Dead < 0 ||
+		a.Win < 0 || a.Tie < 0 || a.Win+a.Tie > 1 {
+		return nil, nil, nil, nil, fmt.Errorf("%w: all-in %+v", ErrInvalidInput, a)
+	}
+	risk := a.Stacks[a.Hero]
+	if a.Stacks[a.Villain] < risk {
+		risk = a.Stacks[a.Villain]
+	}
+	outcome := func(hero, villain float64) []float64 {
+		stacks := append([]float64(nil), a.Stacks...)
+		stacks[a.Hero] += hero
+		stacks[a.Villain] += villain
+		return stacks
+	}
+	win = outcome(risk+a.Dead, -risk)
+	lose = outcome(-risk, risk+a.Dead)
+	tie = outcome(a.Dead/2, a.Dead/2)
+	fold = outcome(-a.Posted, a.Posted+a.Dead)
+	return win, lose, tie, fold, nil
+}
+
+// EV 英雄全下（或跟注全下）和弃牌的奖金期望
+func (a AllIn) EV(payouts []float64) (allIn, fold float64, err error) {
+	ew, el, et, ef, err := a.equities(payouts)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a.Win*ew + a.Tie*et + (1-a.Win-a.Tie)*el, ef, nil
+}
+
+// RequiredEquity 全下不亏于弃牌所需的最低胜率，平局算一半
+func (a AllIn) RequiredEquity(payouts []float64) (float64, error) {
+	ew, el, _, ef, err := a.equities(payouts)
+	if err != nil {
+		return 0, err
+	}
+	if ew == el {
+		return 0, nil
+	}
+	return (ef - el) / (ew - el), nil
+}
+
+// equities 四种结局下英雄的奖金期望
+func (a AllIn) equities(payouts []float64) (win, lose, tie, fold float64, err error) {
+	outcomes := make([][]float64, 4)
+	if outcomes[0], outcomes[1], outcomes[2], outcomes[3], err = a.Outcomes(); err != nil {
+		return
+	}
+	values := make([]float64, 4)
+	for i, stacks := range outcomes {
+		equity, err := Equity(stacks, payouts)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		values[i] = equity[a.Hero]
+	}
+	return values[0], values[1], values[2], values[3], nil
+}
+
+// ShowdownOdds 用牌型计算得到 hero 对 villain 摊牌的胜率和平局率
+// 缺少的公共牌不超过两张时精确枚举，否则随机模拟 trials 次
+func ShowdownOdds(em evaluator.EvaluatorManager, hero, villain, board []card.Card, trials int, rng *rand.Rand) (win, tie float64, err error) {
+	if len(hero) != 2 || len(villain) != 2 || len(board) > 5 {
+		return 0, 0, fmt.Errorf("%w: %d/%d hole cards, %d board cards", ErrInvalidInput, len(hero), len(villain), len(board))
+	}
+	used := map[card.Card]bool{}
+	for _, c := range append(append(append([]card.Card(nil), hero...), villain...), board...) {
+		if used[c] {
+			return 0, 0, fmt.Errorf("%w: duplicate card %s", ErrInvalidInput, c.ASCII())
+		}
+		used[c] = true
+	}
+	var deck []card.Card
+	for _, s := range []card.Suit{card.SuitHearts, card.SuitDiamond, card.SuitSpades, card.SuitClubs} {
+		for r := card.RankTwo; r <= card.RankAce; r++ {
+			if c := (card.Card{Rank: r, Suit: s}); !used[c] {
+				deck = append(deck, c)
+			}
+		}
+	}
+
+	full := append(append([]card.Card(nil), board...), make([]card.Card, 5-len(board))...)
+	heroCards, villainCards := make([]card.Card, 7), make([]card.Card, 7)
+	var won, tied, total float64
+	showdown := func() {
+		// 两手牌使用各自的切片，见 EvaluatorManager.Evaluate
+		copy(heroCards, hero)
+		copy(heroCards[2:], full)
+		h := em.Evaluate(heroCards...)
+		copy(villainCards, villain)
+		copy(villainCards[2:], full)
+		v := em.Evaluate(villainCards...)
+		switch h.Compare(v) {
+		case evaluator.ResultHigher:
+			won++
+		case evaluator.ResultIdentical:
+			tied++
+		}
+		total++
+	}
+
+	switch missing := 5 - len(board); {
+	case missing == 0:
+		showdown()
+	case missing == 1:
+		for _, c := range deck {
+			full[4] = c
+			showdown()
+		}
+	case missing == 2:
+		for i := range deck {
+			for j := i + 1; j < len(deck); j++ {
+				full[3], full[4] = deck[i], deck[j]
+				showdown()
+			}
+		}
+	default:
+		if trials <= 0 {
+			return 0, 0, fmt.Errorf("%w: %d trials", ErrInvalidInput, trials)
+		}
+		for t := 0; t < trials; t++ {
+			// 部分洗牌得到缺少的公共牌
+			for j := 0; j < missing; j++ {
+				k := j + rng.Intn(len(deck)-j)
+				deck[j], deck[k] = deck[k], deck[j]
+				full[len(board)+j] = deck[j]
+			}
+			showdown()
+		}
+	}
+	return won / total, tied / total, nil
+}
@@ -0,0 +1,81 @@
+package icm
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllInOutcomes(t *testing.T) {
+	a := AllIn{Stacks: []float64{1000, 3000, 2000}, Hero: 0, Villain: 1, Posted: 100, Dead: 50}
+	win, lose, tie, fold, err := a.Outcomes()
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{2050, 2000, 2000}, win)
+	assert.Equal(t, []float64{0, 4050, 2000}, lose)
+	assert.Equal(t, []float64{1025, 3025, 2000}, tie)
+	assert.Equal(t, []float64{900, 3150, 2000}, fold)
+
+	a.Win = 0.8
+	a.Tie = 0.3
+	_, _, _, _, err = a.Outcomes()
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, _, err = AllIn{Stacks: []float64{1, 1}, Hero: 0, Villain: 0}.EV([]float64{1})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestAllInEV(t *testing.T) {
+	payouts := []float64{50, 30, 20}
+	a := AllIn{Stacks: []float64{1000, 1000, 8000}, Hero: 0, Villain: 1, Win: 0.5}
+	allIn, fold, err := a.EV(payouts)
+	assert.Nil(t, err)
+	// 没有底池时弃牌不亏，翻硬币全下在 ICM 下亏损
+	assert.Less(t, allIn, fold)
+	required, err := a.RequiredEquity(payouts)
+	assert.Nil(t, err)
+	assert.Greater(t, required, 0.5)
+
+	// 胜率达到所需胜率时两者相等
+	a.Win = required
+	allIn, fold, err = a.EV(payouts)
+	assert.Nil(t, err)
+	assert.InDelta(t, fold, allIn, 1e-9)
+
+	// 只剩奖金相同的名次时筹码没有价值
+	a = AllIn{Stacks: []float64{1000, 1000}, Hero: 0, Villain: 1, Win: 0.3}
+	required, err = a.RequiredEquity([]float64{10, 10})
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, required)
+}
+
+func TestShowdownOdds(t *testing.T) {
+	em := evaluator.NewEvaluatorManager()
+	rng := rand.New(rand.NewSource(1))
+	win, tie, err := ShowdownOdds(em, cardtest.Cards(t, "AsAh"), cardtest.Cards(t, "KsKh"), cardtest.Cards(t, "2c7d9hJsQd"), 0, rng)
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, win)
+	assert.Equal(t, 0.0, tie)
+
+	// 河牌只有两张 K 能让对手反超
+	win, tie, err = ShowdownOdds(em, cardtest.Cards(t, "AsAh"), cardtest.Cards(t, "KsKh"), cardtest.Cards(t, "2c7d9hJc"), 0, rng)
+	assert.Nil(t, err)
+	assert.InDelta(t, 42.0/44, win, 1e-9)
+	assert.Equal(t, 0.0, tie)
+
+	// 同样的牌平分
+	win, tie, err = ShowdownOdds(em, cardtest.Cards(t, "AsKs"), cardtest.Cards(t, "AhKh"), cardtest.Cards(t, "2c7d9c"), 0, rng)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, win)
+	assert.Equal(t, 1.0, tie)
+
+	win, _, err = ShowdownOdds(em, cardtest.Cards(t, "AsAh"), cardtest.Cards(t, "KsKh"), nil, 4000, rng)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.82, win, 0.03)
+
+	_, _, err = ShowdownOdds(em, cardtest.Cards(t, "AsAh"), cardtest.Cards(t, "AsKh"), nil, 100, rng)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, _, err = ShowdownOdds(em, cardtest.Cards(t, "AsAh"), cardtest.Cards(t, "KsKh"), nil, 0, rng)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
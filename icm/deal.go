@@ -0,0 +1,63 @@
+package icm
+
+import (
+	"fmt"
+)
+
+type (
+	// Deal 锦标赛达成协议时的分配建议
+	Deal struct {
+		ICM        []float64 // 按 ICM 分配全部奖金
+		ChipChop   []float64 // 按筹码比例分配
+		Guaranteed []float64 // 留下 Leftover 继续争夺时每个玩家现在拿到的奖金
+		Leftover   float64   // 留给冠军的奖金
+	}
+)
+
+// ChipChop 按筹码分配：每个玩家先拿最低的剩余奖金，其余按筹码比例分配
+func ChipChop(stacks, payouts []float64) ([]float64, error) {
+	if _, err := split(stacks, payouts, func(s, p []float64) ([]float64, error) { return s, nil }); err != nil {
+		return nil, err
+	}
+	var total, pool float64
+	for _, s := range stacks {
+		total += s
+	}
+	for _, p := range payouts[:min(len(stacks), len(payouts))] {
+		pool += p
+	}
+	// 奖金名次少于玩家时最低保证为 0
+	var floor float64
+	if len(payouts) >= len(stacks) {
+		floor = payouts[len(stacks)-1]
+	}
+	pool -= floor * float64(len(stacks))
+
+	chop := make([]float64, len(stacks))
+	for i, s := range stacks {
+		chop[i] = floor + pool*s/total
+	}
+	return chop, nil
+}
+
+// SuggestDeal 建议的分配方案：从冠军奖金中留出 leftover 继续比赛，其余按 ICM 现在分配
+// 继续比赛时每个玩家的期望不变
+func SuggestDeal(stacks, payouts []float64, leftover float64) (*Deal, error) {
+	if len(payouts) == 0 || leftover < 0 || leftover > payouts[0] {
+		return nil, fmt.Errorf("%w: leftover %g", ErrInvalidInput, leftover)
+	}
+	full, err := Equity(stacks, payouts)
+	if err != nil {
+		return nil, err
+	}
+	chop, err := ChipChop(stacks, payouts)
+	if err != nil {
+		return nil, err
+	}
+	reduced := append([]float64{payouts[0] - leftover}, payouts[1:]...)
+	guaranteed, err := Equity(stacks, reduced)
+	if err != nil {
+		return nil, err
+	}
+	return &Deal{ICM: full, ChipChop: chop, Guaranteed: guaranteed, Leftover: leftover}, nil
+}
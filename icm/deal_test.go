@@ -0,0 +1,44 @@
+package icm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChipChop(t *testing.T) {
+	chop, err := ChipChop([]float64{5000, 3000, 2000}, []float64{50, 30, 20})
+	assert.Nil(t, err)
+	// 每人先拿 20，剩下的 40 按筹码分配
+	assert.InDeltaSlice(t, []float64{40, 32, 28}, chop, 1e-9)
+
+	// 奖金名次少于玩家时没有保底
+	chop, err = ChipChop([]float64{3, 1}, []float64{100})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{75, 25}, chop, 1e-9)
+
+	_, err = ChipChop([]float64{-1, 1}, []float64{100})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestSuggestDeal(t *testing.T) {
+	stacks, payouts := []float64{5000, 3000, 2000}, []float64{50, 30, 20}
+	deal, err := SuggestDeal(stacks, payouts, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 10.0, deal.Leftover)
+	assert.InDelta(t, 90, sum(deal.Guaranteed), 1e-9)
+	assert.InDelta(t, 100, sum(deal.ICM), 1e-9)
+	assert.InDelta(t, 100, sum(deal.ChipChop), 1e-9)
+	// 保证的奖金加上争夺剩余奖金的期望等于 ICM
+	for i, s := range stacks {
+		assert.InDelta(t, deal.ICM[i], deal.Guaranteed[i]+deal.Leftover*s/10000, 1e-9)
+	}
+	// 筹码领先者按筹码分配拿得更多
+	assert.Greater(t, deal.ChipChop[0], deal.ICM[0])
+	assert.Less(t, deal.ChipChop[2], deal.ICM[2])
+
+	_, err = SuggestDeal(stacks, payouts, 60)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = SuggestDeal(stacks, nil, 0)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -0,0 +1,224 @@
+// Package icm 独立筹码模型：由筹码和奖金结构计算锦标赛的奖金期望
+package icm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+type (
+	// Model 名次的概率模型
+	Model int
+)
+
+const (
+	// Harville 每个名次由剩下的玩家按筹码比例争夺，即 Malmuth-Harville 模型
+	Harville Model = iota
+	// MalmuthWeitzman 从最后一名开始，被淘汰的概率与筹码成反比
+	MalmuthWeitzman
+)
+
+const (
+	// MaxStates 精确计算时允许的最大状态数
+	MaxStates = 1 << 20
+	// DefaultTrials Equity 超出精确计算范围时的模拟次数
+	DefaultTrials = 20000
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid icm input")
+	ErrTooLarge     = errors.New("too many players for exact icm")
+)
+
+// Equity 按 Malmuth-Harville 模型计算每个玩家的奖金期望
+// payouts 为从第一名开始的奖金，超出精确计算范围时使用蒙特卡洛模拟
+func Equity(stacks, payouts []float64) ([]float64, error) {
+	equity, err := Exact(Harville, stacks, payouts)
+	if errors.Is(err, ErrTooLarge) {
+		return MonteCarlo(Harville, stacks, payouts, DefaultTrials, rand.New(rand.NewSource(1)))
+	}
+	return equity, err
+}
+
+// Exact 精确计算奖金期望，筹码为 0 的玩家已经淘汰，平分最后的名次
+func Exact(m Model, stacks, payouts []float64) ([]float64, error) {
+	return split(stacks, payouts, func(stacks, payouts []float64) ([]float64, error) {
+		if m == MalmuthWeitzman {
+			return exactMalmuthWeitzman(stacks, payouts)
+		}
+		return exactHarville(stacks, payouts)
+	})
+}
+
+// MonteCarlo 按模型随机生成 trials 次名次来估计奖金期望
+func MonteCarlo(m Model, stacks, payouts []float64, trials int, rng *rand.Rand) ([]float64, error) {
+	if trials <= 0 {
+		return nil, fmt.Errorf("%w: %d trials", ErrInvalidInput, trials)
+	}
+	return split(stacks, payouts, func(stacks, payouts []float64) ([]float64, error) {
+		n := len(stacks)
+		equity := make([]float64, n)
+		order := make([]int, n)
+		keys := make([]float64, n)
+		for t := 0; t < trials; t++ {
+			// 指数分布的时钟：Harville 中速率与筹码成正比的先到达者名次靠前，
+			// Malmuth-Weitzman 中速率与筹码成反比的先到达者先被淘汰
+			for i, s := range stacks {
+				order[i] = i
+				e := rng.ExpFloat64()
+				if m == MalmuthWeitzman {
+					keys[i] = -e * s
+				} else {
+					keys[i] = e / s
+				}
+			}
+			sort.Slice(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+			for place, i := range order[:min(n, len(payouts))] {
+				equity[i] += payouts[place]
+			}
+		}
+		for i := range equity {
+			equity[i] /= float64(trials)
+		}
+		return equity, nil
+	})
+}
+
+// split 检查输入，淘汰的玩家平分最后的名次，其他玩家按 fn 计算
+func split(stacks, payouts []float64, fn func(stacks, payouts []float64) ([]float64, error)) ([]float64, error) {
+	if len(stacks) == 0 || len(stacks) > 64 {
+		return nil, fmt.Errorf("%w: %d players", ErrInvalidInput, len(stacks))
+	}
+	var alive []int
+	for i, s := range stacks {
+		if s < 0 || math.IsNaN(s) || math.IsInf(s, 0) {
+			return nil, fmt.Errorf("%w: stack %g", ErrInvalidInput, s)
+		}
+		if s > 0 {
+			alive = append(alive, i)
+		}
+	}
+	for _, p := range payouts {
+		if p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+			return nil, fmt.Errorf("%w: payout %g", ErrInvalidInput, p)
+		}
+	}
+	if len(alive) == 0 {
+		return nil, fmt.Errorf("%w: no chips", ErrInvalidInput)
+	}
+
+	equity := make([]float64, len(stacks))
+	m := len(alive)
+	if m < len(stacks) {
+		var shared float64
+		for place := m; place < len(stacks) && place < len(payouts); place++ {
+			shared += payouts[place]
+		}
+		for i, s := range stacks {
+			if s == 0 {
+				equity[i] = shared / float64(len(stacks)-m)
+			}
+		}
+	}
+
+	live := make([]float64, m)
+	for j, i := range alive {
+		live[j] = stacks[i]
+	}
+	result, err := fn(live, payouts[:min(m, len(payouts))])
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range alive {
+		equity[i] = result[j]
+	}
+	return equity, nil
+}
+
+// exactHarville 按名次从前往后，状态为已经排定名次的玩家集合
+func exactHarville(stacks, payouts []float64) ([]float64, error) {
+	n, k := len(stacks), len(payouts)
+	if states(n, k) > MaxStates {
+		return nil, fmt.Errorf("%w: %d players, %d places", ErrTooLarge, n, k)
+	}
+	var total float64
+	for _, s := range stacks {
+		total += s
+	}
+
+	equity := make([]float64, n)
+	level := map[uint64]float64{0: 1}
+	for place := 0; place < k; place++ {
+		next := make(map[uint64]float64, len(level)*(n-place))
+		for mask, p := range level {
+			rest := total
+			for i, s := range stacks {
+				if mask&(1<<i) != 0 {
+					rest -= s
+				}
+			}
+			for i, s := range stacks {
+				if mask&(1<<i) == 0 {
+					q := p * s / rest
+					equity[i] += q * payouts[place]
+					next[mask|1<<i] += q
+				}
+			}
+		}
+		level = next
+	}
+	return equity, nil
+}
+
+// exactMalmuthWeitzman 从最后一名往前，状态为还没有被淘汰的玩家集合
+func exactMalmuthWeitzman(stacks, payouts []float64) ([]float64, error) {
+	n := len(stacks)
+	if states(n, n) > MaxStates {
+		return nil, fmt.Errorf("%w: %d players", ErrTooLarge, n)
+	}
+
+	equity := make([]float64, n)
+	level := map[uint64]float64{1<<n - 1: 1}
+	for remaining := n; remaining > 0; remaining-- {
+		next := make(map[uint64]float64, len(level)*remaining)
+		for mask, p := range level {
+			var inverse float64
+			for i, s := range stacks {
+				if mask&(1<<i) != 0 {
+					inverse += 1 / s
+				}
+			}
+			for i, s := range stacks {
+				if mask&(1<<i) != 0 {
+					q := p * (1 / s) / inverse
+					if remaining <= len(payouts) {
+						equity[i] += q * payouts[remaining-1]
+					}
+					next[mask&^(1<<i)] += q
+				}
+			}
+		}
+		level = next
+	}
+	return equity, nil
+}
+
+// states 排定前 k 个名次的状态数
+func states(n, k int) float64 {
+	var total, c float64 = 0, 1
+	for m := 0; m <= k; m++ {
+		total += c
+		c = c * float64(n-m) / float64(m+1)
+	}
+	return total
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,96 @@
+package icm
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sum(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestEquity(t *testing.T) {
+	// 两人时按筹码比例分配第一名和第二名的差额
+	equity, err := Equity([]float64{3000, 1000}, []float64{70, 30})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{60, 40}, equity, 1e-9)
+
+	// 经典的三人例子
+	equity, err = Equity([]float64{5000, 3000, 2000}, []float64{50, 30, 20})
+	assert.Nil(t, err)
+	assert.InDelta(t, 38.393, equity[0], 1e-3)
+	assert.InDelta(t, 32.750, equity[1], 1e-3)
+	assert.InDelta(t, 28.857, equity[2], 1e-3)
+	assert.InDelta(t, 100, sum(equity), 1e-9)
+
+	// 筹码相同时平分
+	equity, err = Equity([]float64{1, 1, 1, 1}, []float64{50, 30, 20})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{25, 25, 25, 25}, equity, 1e-9)
+
+	// 淘汰的玩家平分最后的名次
+	equity, err = Equity([]float64{100, 0, 0}, []float64{50, 30, 20})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{50, 25, 25}, equity, 1e-9)
+
+	_, err = Equity([]float64{100, -1}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = Equity([]float64{0, 0}, []float64{1})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = Equity([]float64{1, 1}, []float64{-1})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func TestEquityLargeField(t *testing.T) {
+	stacks := make([]float64, 60)
+	for i := range stacks {
+		stacks[i] = float64(1000 + 100*i)
+	}
+	payouts := []float64{30, 20, 15, 10, 8, 7, 5, 5}
+
+	_, err := Exact(Harville, stacks, payouts)
+	assert.ErrorIs(t, err, ErrTooLarge)
+	equity, err := Equity(stacks, payouts)
+	assert.Nil(t, err)
+	assert.InDelta(t, 100, sum(equity), 1e-9)
+	// 筹码越多期望越高
+	assert.Greater(t, equity[59], equity[30])
+	assert.Greater(t, equity[30], equity[0])
+}
+
+func TestMalmuthWeitzman(t *testing.T) {
+	// 两人时两种模型一致
+	equity, err := Exact(MalmuthWeitzman, []float64{3000, 1000}, []float64{70, 30})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{60, 40}, equity, 1e-9)
+
+	stacks, payouts := []float64{5000, 3000, 2000}, []float64{50, 30, 20}
+	equity, err = Exact(MalmuthWeitzman, stacks, payouts)
+	assert.Nil(t, err)
+	assert.InDelta(t, 100, sum(equity), 1e-9)
+	assert.Greater(t, equity[0], equity[1])
+	assert.Greater(t, equity[1], equity[2])
+	harville, _ := Exact(Harville, stacks, payouts)
+	assert.NotEqual(t, harville, equity)
+}
+
+func TestMonteCarlo(t *testing.T) {
+	stacks := []float64{5000, 3000, 2000, 1500, 500}
+	payouts := []float64{50, 30, 20}
+	for _, m := range []Model{Harville, MalmuthWeitzman} {
+		exact, err := Exact(m, stacks, payouts)
+		assert.Nil(t, err)
+		estimate, err := MonteCarlo(m, stacks, payouts, 50000, rand.New(rand.NewSource(1)))
+		assert.Nil(t, err)
+		assert.InDeltaSlice(t, exact, estimate, 0.5)
+	}
+
+	_, err := MonteCarlo(Harville, stacks, payouts, 0, rand.New(rand.NewSource(1)))
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
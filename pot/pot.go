@@ -0,0 +1,302 @@
+// Package pot 主池和边池的计算与分配
+package pot
+
+import (
+	"sort"
+
+	"github.com/openpoker-dev/contrib/evaluator"
+)
+
+type (
+	// Ledger 记录每个座位在每一街投入的筹码
+	Ledger struct {
+		contributions map[int]map[int]int64 // 座位 -> 街 -> 筹码
+		dead          map[int]int64         // 前注、死小盲等不算作下注的筹码
+		folded        map[int]bool
+	}
+
+	// Pot 主池或边池
+	Pot struct {
+		Amount   int64
+		Eligible []int // 有资格争夺底池的座位，按座位号排序
+	}
+
+	// Uncalled 没有人跟注而退回的筹码
+	Uncalled struct {
+		Seat   int
+		Amount int64
+	}
+
+	// Award 底池的分配结果
+	Award struct {
+		Pot
+		Winners []int
+		Shares  map[int]int64 // 每个赢家分到的筹码
+	}
+
+	// Distributor 按牌型分配底池，零头从庄家左手边第一个赢家开始每人一个
+	Distributor struct {
+		Button int
+		Low    bool // 低牌玩法，牌型小的赢
+	}
+)
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		contributions: make(map[int]map[int]int64),
+		dead:          make(map[int]int64),
+		folded:        make(map[int]bool),
+	}
+}
+
+// Contribute 座位在 street 投入筹码
+func (l *Ledger) Contribute(seat, street int, amount int64) {
+	if amount == 0 {
+		return
+	}
+	if l.contributions[seat] == nil {
+		l.contributions[seat] = make(map[int]int64)
+	}
+	l.contributions[seat][street] += amount
+}
+
+// ContributeDead 座位投入死钱，计入底池但不算作下注，
+// 不参与退回未跟注的筹码和边池的分层
+func (l *Ledger) ContributeDead(seat int, amount int64) {
+	if amount == 0 {
+		return
+	}
+	l.dead[seat] += amount
+}
+
+// Fold 弃牌的座位投入的筹码留在底池中，但不能再赢得底池
+func (l *Ledger) Fold(seat int) {
+	l.folded[seat] = true
+}
+
+func (l *Ledger) Folded(seat int) bool {
+	return l.folded[seat]
+}
+
+// Total 座位下注的总筹码，不包括死钱
+func (l *Ledger) Total(seat int) int64 {
+	var total int64
+	for _, amount := range l.contributions[seat] {
+		total += amount
+	}
+	return total
+}
+
+// Dead 座位投入的死钱
+func (l *Ledger) Dead(seat int) int64 {
+	return l.dead[seat]
+}
+
+// Street 座位在 street 投入的筹码
+func (l *Ledger) Street(seat, street int) int64 {
+	return l.contributions[seat][street]
+}
+
+// Seats 投入过筹码的座位，包括只投入死钱的，按座位号排序
+func (l *Ledger) Seats() []int {
+	seats := make([]int, 0, len(l.contributions))
+	for seat := range l.contributions {
+		seats = append(seats, seat)
+	}
+	for seat := range l.dead {
+		if l.contributions[seat] == nil {
+			seats = append(seats, seat)
+		}
+	}
+	sort.Ints(seats)
+	return seats
+}
+
+// ReturnUncalled 投入最多的座位超出第二多的部分没有人跟注，从最后一街中扣除并返回
+func (l *Ledger) ReturnUncalled() (Uncalled, bool) {
+	var top, second int64
+	topSeat := -1
+	for _, seat := range l.Seats() {
+		total := l.Total(seat)
+		switch {
+		case total > top:
+			second = top
+			top = total
+			topSeat = seat
+		case total > second:
+			second = total
+		}
+	}
+	if topSeat < 0 || top == second {
+		return Uncalled{}, false
+	}
+
+	excess := top - second
+	streets := make([]int, 0, len(l.contributions[topSeat]))
+	for street := range l.contributions[topSeat] {
+		streets = append(streets, street)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(streets)))
+	remaining := excess
+	for _, street := range streets {
+		take := l.contributions[topSeat][street]
+		if take > remaining {
+			take = remaining
+		}
+		l.contributions[topSeat][street] -= take
+		remaining -= take
+		if remaining == 0 {
+			break
+		}
+	}
+	return Uncalled{Seat: topSeat, Amount: excess}, true
+}
+
+// Pots 按下注的筹码分层，返回主池和边池，主池在前
+// 弃牌座位投入的筹码并入对应层的底池，资格相同的相邻底池合并
+// 死钱归入所有没弃牌的座位都有资格争夺的最底层
+func (l *Ledger) Pots() []Pot {
+	seats := l.Seats()
+	var pots []Pot
+	dead := Pot{}
+	for _, seat := range seats {
+		dead.Amount += l.dead[seat]
+		if !l.folded[seat] {
+			dead.Eligible = append(dead.Eligible, seat)
+		}
+	}
+	if dead.Amount > 0 {
+		pots = append(pots, dead)
+	}
+
+	levels := make([]int64, 0, len(seats))
+	for _, seat := range seats {
+		if !l.folded[seat] {
+			levels = append(levels, l.Total(seat))
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	distinct := levels[:0]
+	for _, level := range levels {
+		if level > 0 && (len(distinct) == 0 || level > distinct[len(distinct)-1]) {
+			distinct = append(distinct, level)
+		}
+	}
+	levels = distinct
+
+	var floor int64
+	for i, level := range levels {
+		if i == len(levels)-1 { // 最高一层包括弃牌座位多投入的筹码
+			level = 0
+			for _, seat := range seats {
+				if total := l.Total(seat); total > level {
+					level = total
+				}
+			}
+		}
+
+		pot := Pot{}
+		for _, seat := range seats {
+			total := l.Total(seat)
+			if total > floor {
+				pot.Amount += min64(total, level) - floor
+			}
+			if !l.folded[seat] && total >= levels[i] {
+				pot.Eligible = append(pot.Eligible, seat)
+			}
+		}
+		floor = level
+
+		if n := len(pots); n > 0 && equalSeats(pots[n-1].Eligible, pot.Eligible) {
+			pots[n-1].Amount += pot.Amount
+			continue
+		}
+		pots = append(pots, pot)
+	}
+	return pots
+}
+
+// Distribute 把每个底池分给有资格的座位中牌型最大的，hands 为摊牌座位的牌型
+// 只有一个有资格的座位时不需要牌型
+func (d Distributor) Distribute(pots []Pot, hands map[int]evaluator.PokerHand) ([]Award, map[int]int64) {
+	awards := make([]Award, 0, len(pots))
+	payouts := make(map[int]int64)
+	for _, pot := range pots {
+		award := Award{Pot: pot, Winners: d.winners(pot.Eligible, hands), Shares: make(map[int]int64)}
+		if len(award.Winners) == 0 {
+			continue
+		}
+
+		share := pot.Amount / int64(len(award.Winners))
+		odd := pot.Amount % int64(len(award.Winners))
+		for _, winner := range d.fromButton(award.Winners) {
+			amount := share
+			if odd > 0 {
+				amount++
+				odd--
+			}
+			award.Shares[winner] = amount
+			payouts[winner] += amount
+		}
+		awards = append(awards, award)
+	}
+	return awards, payouts
+}
+
+// fromButton 按从庄家左手边开始的顺序排列座位号
+func (d Distributor) fromButton(seats []int) []int {
+	ordered := append([]int{}, seats...)
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if (a > d.Button) != (b > d.Button) {
+			return a > d.Button
+		}
+		return a < b
+	})
+	return ordered
+}
+
+func (d Distributor) winners(eligible []int, hands map[int]evaluator.PokerHand) []int {
+	if len(eligible) <= 1 {
+		return eligible
+	}
+	better := evaluator.ResultHigher
+	if d.Low {
+		better = evaluator.ResultLower
+	}
+	var result []int
+	var best evaluator.PokerHand
+	for _, seat := range eligible {
+		hand, exists := hands[seat]
+		if !exists {
+			continue
+		}
+		switch {
+		case len(result) == 0 || hand.Compare(best) == better:
+			result = []int{seat}
+			best = hand
+		case hand.Compare(best) == evaluator.ResultIdentical:
+			result = append(result, seat)
+		}
+	}
+	return result
+}
+
+func equalSeats(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,145 @@
+package pot
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func hand(s string) evaluator.PokerHand {
+	cards, _ := card.ParseCards(s)
+	return evaluator.Evaluate(cards...)
+}
+
+func TestSidePots(t *testing.T) {
+	ledger := NewLedger()
+	ledger.Contribute(1, 0, 50)  // 全下
+	ledger.Contribute(2, 0, 100) // 全下
+	ledger.Contribute(3, 0, 100)
+	ledger.Contribute(3, 1, 200)
+	ledger.Contribute(4, 0, 100)
+	ledger.Contribute(4, 1, 150) // 全下
+	ledger.Contribute(5, 0, 20)
+	ledger.Fold(5)
+
+	uncalled, ok := ledger.ReturnUncalled()
+	assert.True(t, ok)
+	assert.Equal(t, Uncalled{Seat: 3, Amount: 50}, uncalled)
+	assert.Equal(t, int64(150), ledger.Street(3, 1))
+	_, ok = ledger.ReturnUncalled()
+	assert.False(t, ok)
+
+	pots := ledger.Pots()
+	assert.Equal(t, []Pot{
+		{Amount: 220, Eligible: []int{1, 2, 3, 4}},
+		{Amount: 150, Eligible: []int{2, 3, 4}},
+		{Amount: 300, Eligible: []int{3, 4}},
+	}, pots)
+
+	awards, payouts := Distributor{Button: 5}.Distribute(pots, map[int]evaluator.PokerHand{
+		1: hand("KsKh KdQc7s5d3c"),
+		2: hand("AsAh KdQc7s5d3c"),
+		3: hand("Js9h KdQc7s5d3c"),
+		4: hand("7h6h KdQc7s5d3c"),
+	})
+	assert.Equal(t, []int{1}, awards[0].Winners)
+	assert.Equal(t, []int{2}, awards[1].Winners)
+	assert.Equal(t, []int{4}, awards[2].Winners)
+	assert.Equal(t, map[int]int64{1: 220, 2: 150, 4: 300}, payouts)
+}
+
+func TestFoldedDeadMoney(t *testing.T) {
+	ledger := NewLedger()
+	ledger.Contribute(1, 0, 30)
+	ledger.Fold(1)
+	ledger.Contribute(2, 0, 100)
+	ledger.Contribute(3, 0, 100)
+	ledger.Contribute(4, 0, 200)
+	ledger.Fold(4)
+
+	_, ok := ledger.ReturnUncalled()
+	assert.True(t, ok)
+	assert.Equal(t, []Pot{{Amount: 330, Eligible: []int{2, 3}}}, ledger.Pots())
+}
+
+func TestOddChips(t *testing.T) {
+	pots := []Pot{{Amount: 11, Eligible: []int{1, 4, 7}}}
+	board := " 5h6s7h8s9d"
+	awards, payouts := Distributor{Button: 4}.Distribute(pots, map[int]evaluator.PokerHand{
+		1: hand("2c2d" + board),
+		4: hand("3c3d" + board),
+		7: hand("4c4d" + board),
+	})
+	assert.Equal(t, []int{1, 4, 7}, awards[0].Winners)
+	// 庄家左手边的座位 7 和座位 1 各多分一个
+	assert.Equal(t, map[int]int64{7: 4, 1: 4, 4: 3}, payouts)
+	assert.Equal(t, payouts, awards[0].Shares)
+}
+
+func TestSingleEligible(t *testing.T) {
+	ledger := NewLedger()
+	ledger.Contribute(1, 0, 1)
+	ledger.Contribute(2, 0, 2)
+	ledger.Fold(1)
+	uncalled, _ := ledger.ReturnUncalled()
+	assert.Equal(t, Uncalled{Seat: 2, Amount: 1}, uncalled)
+
+	_, payouts := Distributor{}.Distribute(ledger.Pots(), nil)
+	assert.Equal(t, map[int]int64{2: 2}, payouts)
+}
+
+func TestLowDistribution(t *testing.T) {
+	pots := []Pot{{Amount: 30, Eligible: []int{1, 2, 3}}}
+	hands := map[int]evaluator.PokerHand{
+		1: hand("7h5d4c3s2h"),
+		2: hand("8h6d4c3s2d"),
+		3: hand("KhKdKcQsQh"),
+	}
+	_, payouts := Distributor{Low: true}.Distribute(pots, hands)
+	assert.Equal(t, map[int]int64{1: 30}, payouts)
+
+	_, payouts = Distributor{}.Distribute(pots, hands)
+	assert.Equal(t, map[int]int64{3: 30}, payouts)
+}
+
+func TestDeadMoney(t *testing.T) {
+	// 死钱不参与退回未跟注的筹码
+	ledger := NewLedger()
+	ledger.ContributeDead(3, 10)
+	ledger.Contribute(2, 0, 10)
+	ledger.Contribute(3, 0, 10)
+	ledger.Fold(1)
+	_, ok := ledger.ReturnUncalled()
+	assert.False(t, ok)
+	assert.Equal(t, []Pot{{Amount: 30, Eligible: []int{2, 3}}}, ledger.Pots())
+	assert.Equal(t, int64(10), ledger.Total(3))
+	assert.Equal(t, int64(10), ledger.Dead(3))
+
+	// 死钱不参与边池的分层
+	ledger = NewLedger()
+	for _, seat := range []int{1, 2, 3} {
+		ledger.ContributeDead(seat, 10)
+	}
+	ledger.Contribute(1, 0, 5) // 全下
+	ledger.Contribute(2, 0, 100)
+	ledger.Contribute(3, 0, 100)
+	assert.Equal(t, []Pot{
+		{Amount: 45, Eligible: []int{1, 2, 3}},
+		{Amount: 190, Eligible: []int{2, 3}},
+	}, ledger.Pots())
+
+	// 只投入了死钱就全下的座位只能赢死钱
+	ledger = NewLedger()
+	for _, seat := range []int{1, 2, 3} {
+		ledger.ContributeDead(seat, 5)
+	}
+	ledger.Contribute(2, 0, 50)
+	ledger.Contribute(3, 0, 50)
+	assert.Equal(t, []int{1, 2, 3}, ledger.Seats())
+	assert.Equal(t, []Pot{
+		{Amount: 15, Eligible: []int{1, 2, 3}},
+		{Amount: 100, Eligible: []int{2, 3}},
+	}, ledger.Pots())
+}
@@ -0,0 +1,197 @@
+package preflop
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/openpoker-dev/contrib/card"
+)
+
+var (
+	// sklanskyGroups Sklansky-Malmuth 的起手牌分组，从第 1 组开始
+	sklanskyGroups = []string{
+		"AA, KK, QQ, JJ, AKs",
+		"TT, AQs, AJs, KQs, AKo",
+		"99, JTs, QJs, KJs, ATs, AQo",
+		"T9s, KQo, 88, QTs, 98s, J9s, AJo, KTs",
+		"77, 87s, Q9s, T8s, KJo, QJo, JTo, 76s, 97s, A9s-A2s, 65s",
+		"66, ATo, 55, 86s, KTo, QTo, 54s, K9s, J8s, 75s",
+		"44, J9o, 64s, T9o, 53s, 33, 98o, 43s, 22, K8s-K2s, T7s, Q8s",
+		"87o, A9o, Q9o, 76o, 42s, 32s, 96s, 85s, J8o, J7s, 65o, 54o, 74s, K9o, T8o",
+	}
+
+	loadRanking sync.Once
+	ranking     []Hand
+	ranks       [NumHands]int
+
+	loadSklansky sync.Once
+	sklansky     [NumHands]int
+)
+
+// Classify 两张手牌所属的等价类，两张牌相同或者不是标准的牌时返回错误
+func Classify(a, b card.Card) (Hand, error) {
+	for _, c := range []card.Card{a, b} {
+		if c.Rank < card.RankTwo || c.Rank > card.RankAce || c.Suit < card.SuitHearts || c.Suit > card.SuitClubs {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidHand, c.ASCII())
+		}
+	}
+	if a == b {
+		return 0, fmt.Errorf("%w: duplicate %s", ErrInvalidHand, a.ASCII())
+	}
+	return HandOf(a, b), nil
+}
+
+// LiveCombos 不包括 dead 中任何一张牌的具体组合
+func (h Hand) LiveCombos(dead ...card.Card) [][2]card.Card {
+	var live [][2]card.Card
+	for _, c := range h.Combos() {
+		if !contains(dead, c[0]) && !contains(dead, c[1]) {
+			live = append(live, c)
+		}
+	}
+	return live
+}
+
+// NumLiveCombos 不包括 dead 中任何一张牌的组合数量
+func (h Hand) NumLiveCombos(dead ...card.Card) int {
+	return len(h.LiveCombos(dead...))
+}
+
+// Ranking 按对一个随机对手的胜率从高到低排列的起手牌
+func Ranking() []Hand {
+	loadRanking.Do(func() {
+		ranking = Hands()
+		table := MultiwayEquity()
+		sort.SliceStable(ranking, func(i, j int) bool {
+			return table.Equity(ranking[i], 1) > table.Equity(ranking[j], 1)
+		})
+		for i, h := range ranking {
+			ranks[h] = i + 1
+		}
+	})
+	return append([]Hand(nil), ranking...)
+}
+
+// Rank 在 Ranking 中的名次，最好的 AA 为 1
+func (h Hand) Rank() int {
+	Ranking()
+	return ranks[h]
+}
+
+// Chen Bill Chen 的起手牌打分，半分向上取整
+func (h Hand) Chen() int {
+	high, low := h.Ranks()
+	// 以半分为单位计算
+	points := func(r card.Rank) int {
+		switch r {
+		case card.RankAce:
+			return 20
+		case card.RankKing:
+			return 16
+		case card.RankQueen:
+			return 14
+		case card.RankJack:
+			return 12
+		}
+		return int(r)
+	}
+	score := points(high)
+	if h.Pair() {
+		score *= 2
+		if score < 10 {
+			score = 10
+		}
+		return score / 2
+	}
+	if h.Suited() {
+		score += 4
+	}
+	gap := int(high-low) - 1
+	score -= []int{0, 2, 4, 8, 10}[min(gap, 4)]
+	if gap <= 1 && high < card.RankQueen {
+		score += 2
+	}
+	return int(math.Ceil(float64(score) / 2))
+}
+
+// SklanskyGroup Sklansky-Malmuth 分组，从 1 到 8，不在分组中的返回 9
+func (h Hand) SklanskyGroup() int {
+	loadSklansky.Do(func() {
+		for v := range sklansky {
+			sklansky[v] = len(sklanskyGroups) + 1
+		}
+		// 从最后一组开始填，同一手牌出现在多个分组时取最小的分组
+		for i := len(sklanskyGroups) - 1; i >= 0; i-- {
+			r, _ := ParseRange(sklanskyGroups[i]) // 固定的分组，TestSklanskyGroup 保证能够解析
+			for v, weight := range r {
+				if weight > 0 {
+					sklansky[v] = i + 1
+				}
+			}
+		}
+	})
+	return sklansky[h]
+}
+
+// SklanskyChubukov 单挑时小盲亮牌全下、大盲知道手牌后最优跟注，
+// 全下仍然不亏于弃牌的最大有效筹码，以大盲为单位，胜率来自内置的单挑胜率表。
+// 永远不亏时返回 +Inf
+func (h Hand) SklanskyChubukov() float64 {
+	table := HeadsUpEquity()
+	hole := h.Combos()[0]
+	weights := make([]float64, NumHands)
+	var total float64
+	for _, v := range Hands() {
+		weights[v] = float64(v.NumLiveCombos(hole[0], hole[1]))
+		total += weights[v]
+	}
+	// 全下相对于弃牌的收益：大盲跟注需要的胜率为 (S-1)/2S
+	profit := func(stack float64) float64 {
+		ev := 0.0
+		for _, v := range Hands() {
+			e := table.Equity(h, v)
+			if 1-e > (stack-1)/(2*stack) {
+				ev += weights[v] * (2*stack*e - stack)
+			} else {
+				ev += weights[v]
+			}
+		}
+		return ev/total + 0.5
+	}
+
+	const limit = 1e6
+	if profit(limit) >= 0 {
+		return math.Inf(1)
+	}
+	lo, hi := 1.0, limit
+	if profit(lo) < 0 {
+		return lo
+	}
+	for hi-lo > 1e-3 {
+		mid := (lo + hi) / 2
+		if profit(mid) >= 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+func contains(cards []card.Card, c card.Card) bool {
+	for _, x := range cards {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
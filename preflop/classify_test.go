@@ -0,0 +1,108 @@
+package preflop
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		name string
+		row  int
+		col  int
+	}{
+		{"As", "Kh", "AKo", 1, 0},
+		{"Kh", "Ah", "AKs", 0, 1},
+		{"7d", "7c", "77", 7, 7},
+		{"9s", "Td", "T9o", 5, 4},
+		{"2c", "3c", "32s", 11, 12},
+	} {
+		h, err := Classify(card.NewCard(c.a), card.NewCard(c.b))
+		assert.Nil(t, err)
+		assert.Equal(t, c.name, h.String())
+		assert.Equal(t, c.row, h.Row())
+		assert.Equal(t, c.col, h.Col())
+		assert.Equal(t, Hand(c.row*13+c.col), h)
+	}
+
+	_, err := Classify(card.NewCard("As"), card.NewCard("As"))
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	_, err = Classify(card.Card{}, card.NewCard("As"))
+	assert.ErrorIs(t, err, ErrInvalidHand)
+}
+
+func TestLiveCombos(t *testing.T) {
+	hand := func(s string) Hand {
+		h, err := ParseHand(s)
+		assert.Nil(t, err)
+		return h
+	}
+	assert.Equal(t, 6, hand("AA").NumLiveCombos())
+	assert.Equal(t, 3, hand("AA").NumLiveCombos(card.NewCard("As")))
+	assert.Equal(t, 1, hand("AA").NumLiveCombos(card.NewCard("As"), card.NewCard("Ah")))
+	assert.Equal(t, 3, hand("AKs").NumLiveCombos(card.NewCard("Ks"), card.NewCard("2c")))
+	assert.Equal(t, 9, hand("AKo").NumLiveCombos(card.NewCard("Ks")))
+	assert.Equal(t, 12, hand("AKo").NumLiveCombos(card.NewCard("Qs")))
+	for _, c := range hand("AKo").LiveCombos(card.NewCard("Ks")) {
+		assert.NotEqual(t, card.NewCard("Ks"), c[1])
+	}
+}
+
+func TestRanking(t *testing.T) {
+	ranking := Ranking()
+	assert.Len(t, ranking, NumHands)
+	assert.Equal(t, "AA", ranking[0].String())
+	assert.Equal(t, "KK", ranking[1].String())
+	for i, h := range ranking {
+		assert.Equal(t, i+1, h.Rank())
+	}
+	// 32o 是最差的起手牌
+	assert.Equal(t, "32o", ranking[NumHands-1].String())
+	aks, _ := ParseHand("AKs")
+	assert.Less(t, aks.Rank(), 10)
+}
+
+func TestChen(t *testing.T) {
+	for name, score := range map[string]int{
+		"AA": 20, "KK": 16, "TT": 10, "55": 5, "22": 5,
+		"AKs": 12, "AKo": 10, "JTs": 9, "T9o": 6, "72o": -1, "A2o": 5, "54s": 6, "Q5o": 2,
+	} {
+		h, err := ParseHand(name)
+		assert.Nil(t, err)
+		assert.Equal(t, score, h.Chen(), name)
+	}
+}
+
+func TestSklanskyGroup(t *testing.T) {
+	for name, group := range map[string]int{
+		"AA": 1, "AKs": 1, "AKo": 2, "AQo": 3, "KQo": 4, "A5s": 5, "ATo": 6, "K2s": 7, "A9o": 8, "72o": 9, "A8o": 9,
+	} {
+		h, err := ParseHand(name)
+		assert.Nil(t, err)
+		assert.Equal(t, group, h.SklanskyGroup(), name)
+	}
+	counts := map[int]int{}
+	for _, h := range Hands() {
+		counts[h.SklanskyGroup()]++
+	}
+	assert.Equal(t, []int{5, 5, 6, 8, 18, 10, 18, 15}, []int{counts[1], counts[2], counts[3], counts[4], counts[5], counts[6], counts[7], counts[8]})
+}
+
+func TestSklanskyChubukov(t *testing.T) {
+	hand := func(s string) Hand {
+		h, err := ParseHand(s)
+		assert.Nil(t, err)
+		return h
+	}
+	assert.True(t, math.IsInf(hand("AA").SklanskyChubukov(), 1))
+	assert.InDelta(t, 477, hand("KK").SklanskyChubukov(), 40)
+	assert.InDelta(t, 1.7, hand("72o").SklanskyChubukov(), 0.2)
+	// 更好的牌可以在更深的筹码下全下
+	assert.Greater(t, hand("KK").SklanskyChubukov(), hand("QQ").SklanskyChubukov())
+	assert.Greater(t, hand("AKs").SklanskyChubukov(), hand("AKo").SklanskyChubukov())
+	assert.Greater(t, hand("A2s").SklanskyChubukov(), hand("72o").SklanskyChubukov())
+}
@@ -0,0 +1,63 @@
+// equitygen 生成 preflop 包内置的翻牌前胜率表，或者用牌型计算重新生成并与内置的表比较
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/openpoker-dev/contrib/preflop"
+)
+
+func main() {
+	boards := flag.Int("boards", 30000, "number of random boards")
+	seed := flag.Int64("seed", 1, "random seed")
+	dir := flag.String("dir", ".", "output directory")
+	verify := flag.Float64("verify", 0, "compare with the embedded tables instead of writing, fail above this difference")
+	flag.Parse()
+
+	headsUp, multiway := preflop.GenerateEquity(*boards, *seed)
+	if *verify > 0 {
+		if diff := compare(headsUp, multiway); diff > *verify {
+			fmt.Fprintf(os.Stderr, "max difference %.4f exceeds %.4f\n", diff, *verify)
+			os.Exit(1)
+		}
+		return
+	}
+	for name, table := range map[string]interface{ MarshalBinary() ([]byte, error) }{
+		"equity_headsup.bin":  headsUp,
+		"equity_multiway.bin": multiway,
+	} {
+		data, err := table.MarshalBinary()
+		if err == nil {
+			err = os.WriteFile(filepath.Join(*dir, name), data, 0o644)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// compare 与内置的表比较，打印并返回最大差值
+func compare(headsUp *preflop.EquityTable, multiway *preflop.MultiwayTable) float64 {
+	var diff float64
+	embedded := preflop.HeadsUpEquity()
+	for _, a := range preflop.Hands() {
+		for _, b := range preflop.Hands() {
+			diff = math.Max(diff, math.Abs(headsUp.Equity(a, b)-embedded.Equity(a, b)))
+		}
+	}
+	fmt.Printf("heads-up max difference %.4f\n", diff)
+
+	var multi float64
+	for _, h := range preflop.Hands() {
+		for n := 1; n <= preflop.MaxOpponents; n++ {
+			multi = math.Max(multi, math.Abs(multiway.Equity(h, n)-preflop.MultiwayEquity().Equity(h, n)))
+		}
+	}
+	fmt.Printf("multiway max difference %.4f\n", multi)
+	return math.Max(diff, multi)
+}
@@ -0,0 +1,108 @@
+package preflop
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+)
+
+type (
+	// Equity 两种起手牌全下到河牌时的胜率
+	Equity interface {
+		// Equity a 对 b 的胜率，平局算一半，对所有不冲突的组合取平均
+		Equity(a, b Hand) float64
+	}
+
+	// EquityTable 两两之间的胜率表，EquityTable[a][b] + EquityTable[b][a] = 1
+	EquityTable [NumHands][NumHands]float64
+)
+
+var (
+	_ Equity = (*EquityTable)(nil)
+)
+
+func (t *EquityTable) Equity(a, b Hand) float64 {
+	return t[a][b]
+}
+
+// SimulateEquity 用蒙特卡洛模拟计算胜率表，每对起手牌模拟 trials 次
+// 每对起手牌使用由 seed 得到的独立随机数，结果与并发数无关
+func SimulateEquity(trials int, seed int64) *EquityTable {
+	t := &EquityTable{}
+	pairs := make(chan [2]Hand)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			em := evaluator.NewEvaluatorManager()
+			for p := range pairs {
+				a, b := p[0], p[1]
+				rng := rand.New(rand.NewSource(seed + int64(a)*NumHands + int64(b)))
+				e := simulate(em, rng, a, b, trials)
+				t[a][b], t[b][a] = e, 1-e
+			}
+		}()
+	}
+	for a := Hand(0); a < NumHands; a++ {
+		t[a][a] = 0.5
+		for b := a + 1; b < NumHands; b++ {
+			pairs <- [2]Hand{a, b}
+		}
+	}
+	close(pairs)
+	wg.Wait()
+	return t
+}
+
+// simulate 随机选取不冲突的组合和五张公共牌，返回 a 的胜率
+func simulate(em evaluator.EvaluatorManager, rng *rand.Rand, a, b Hand, trials int) float64 {
+	combosA, combosB := a.Combos(), b.Combos()
+	deck := make([]card.Card, 0, 52)
+	for _, s := range suits {
+		for r := card.RankTwo; r <= card.RankAce; r++ {
+			deck = append(deck, card.Card{Rank: r, Suit: s})
+		}
+	}
+
+	var won float64
+	board := make([]card.Card, 0, 5)
+	cardsA, cardsB := make([]card.Card, 7), make([]card.Card, 7)
+	for i := 0; i < trials; i++ {
+		x := combosA[rng.Intn(len(combosA))]
+		y := combosB[rng.Intn(len(combosB))]
+		for conflicts(x, y) {
+			y = combosB[rng.Intn(len(combosB))]
+		}
+		// 部分洗牌得到五张公共牌
+		board = board[:0]
+		for j := 0; len(board) < 5; j++ {
+			k := j + rng.Intn(len(deck)-j)
+			deck[j], deck[k] = deck[k], deck[j]
+			if c := deck[j]; c != x[0] && c != x[1] && c != y[0] && c != y[1] {
+				board = append(board, c)
+			}
+		}
+		// 两手牌使用各自的切片，见 EvaluatorManager.Evaluate
+		copy(cardsA, x[:])
+		copy(cardsA[2:], board)
+		ha := em.Evaluate(cardsA...)
+		copy(cardsB, y[:])
+		copy(cardsB[2:], board)
+		hb := em.Evaluate(cardsB...)
+		switch ha.Compare(hb) {
+		case evaluator.ResultHigher:
+			won++
+		case evaluator.ResultIdentical:
+			won += 0.5
+		}
+	}
+	return won / float64(trials)
+}
+
+func conflicts(x, y [2]card.Card) bool {
+	return x[0] == y[0] || x[0] == y[1] || x[1] == y[0] || x[1] == y[1]
+}
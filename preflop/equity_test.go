@@ -0,0 +1,41 @@
+package preflop
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulate(t *testing.T) {
+	em := evaluator.NewEvaluatorManager()
+	rng := rand.New(rand.NewSource(1))
+	hand := func(s string) Hand {
+		h, err := ParseHand(s)
+		assert.Nil(t, err)
+		return h
+	}
+	for _, c := range []struct {
+		a, b   string
+		equity float64
+	}{
+		{"AA", "KK", 0.82},
+		{"AKs", "QQ", 0.46},
+		{"72o", "AA", 0.12},
+		{"AKo", "AKo", 0.5},
+	} {
+		assert.InDelta(t, c.equity, simulate(em, rng, hand(c.a), hand(c.b), 2000), 0.03, c.a+" vs "+c.b)
+	}
+}
+
+func TestSimulateEquity(t *testing.T) {
+	table := SimulateEquity(1, 7)
+	for a := range table {
+		assert.Equal(t, 0.5, table[a][a])
+		for b := range table {
+			assert.Equal(t, 1.0, table.Equity(Hand(a), Hand(b))+table.Equity(Hand(b), Hand(a)))
+		}
+	}
+	assert.Equal(t, table, SimulateEquity(1, 7))
+}
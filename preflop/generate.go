@@ -0,0 +1,311 @@
+package preflop
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+)
+
+type (
+	// MultiwayTable 每种起手牌对 1 到 MaxOpponents 个随机对手全下到河牌的胜率
+	// MultiwayTable[h][n-1] 为对 n 个对手的胜率，平局按人数平分
+	MultiwayTable [NumHands][MaxOpponents]float64
+
+	// tally 整数累加的胜负，与累加顺序无关
+	tally struct {
+		headsUp [NumHands][NumHands]int64 // 赢记2，平局记1
+		pairs   [NumHands][NumHands]int64
+		multi   [NumHands][MaxOpponents]int64 // 以 shares 为单位
+		samples [NumHands]int64
+	}
+
+	// combo 一个具体的两张牌组合
+	combo struct {
+		cards [2]int
+		mask  uint64
+		hand  Hand
+	}
+)
+
+const (
+	// MaxOpponents 多人胜率表的最大对手数
+	MaxOpponents = 9
+
+	// shares 1 到 10 的最小公倍数，平局时可以整除
+	shares = 2520
+	// quantum 序列化时胜率的精度
+	quantum = math.MaxUint16
+)
+
+var (
+	deck   = newDeck()
+	combos = newCombos()
+)
+
+// Equity hand 对 opponents 个随机对手的胜率，opponents 超出范围时返回 0
+func (t *MultiwayTable) Equity(h Hand, opponents int) float64 {
+	if opponents < 1 || opponents > MaxOpponents {
+		return 0
+	}
+	return t[h][opponents-1]
+}
+
+// GenerateEquity 随机生成 boards 组公共牌，在每组公共牌上比较所有组合的牌力，
+// 得到单挑胜率表和多人胜率表。每组公共牌使用由 seed 得到的独立随机数，结果与并发数无关
+func GenerateEquity(boards int, seed int64) (*EquityTable, *MultiwayTable) {
+	jobs := make(chan int)
+	results := make(chan *tally)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			em := evaluator.NewEvaluatorManager()
+			t := &tally{}
+			for b := range jobs {
+				t.board(em, rand.New(rand.NewSource(seed+int64(b))))
+			}
+			results <- t
+		}()
+	}
+	go func() {
+		for b := 0; b < boards; b++ {
+			jobs <- b
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	total := &tally{}
+	for t := range results {
+		total.merge(t)
+	}
+	return total.tables()
+}
+
+// board 随机一组公共牌，累加所有组合之间以及对随机对手的胜负
+func (t *tally) board(em evaluator.EvaluatorManager, rng *rand.Rand) {
+	cards := append([]int(nil), deck...)
+	for i := 0; i < 5; i++ {
+		k := i + rng.Intn(len(cards)-i)
+		cards[i], cards[k] = cards[k], cards[i]
+	}
+	var boardMask uint64
+	board := make([]card.Card, 5)
+	for i, c := range cards[:5] {
+		boardMask |= 1 << c
+		board[i] = cardOf(c)
+	}
+
+	// 按牌力排序得到每个组合的强度，相同牌力强度相同
+	var valid []int
+	hands := make([]evaluator.PokerHand, NumCombos)
+	// 每个组合使用各自的七张牌，见 EvaluatorManager.Evaluate
+	buffer := make([]card.Card, 7*NumCombos)
+	for i, c := range combos {
+		if c.mask&boardMask != 0 {
+			continue
+		}
+		valid = append(valid, i)
+		seven := buffer[7*i : 7*i+7 : 7*i+7]
+		seven[0], seven[1] = cardOf(c.cards[0]), cardOf(c.cards[1])
+		copy(seven[2:], board)
+		hands[i] = em.Evaluate(seven...)
+	}
+	sort.Slice(valid, func(a, b int) bool {
+		return hands[valid[a]].Compare(hands[valid[b]]) == evaluator.ResultLower
+	})
+	var strength [NumCombos]int
+	for k := 1; k < len(valid); k++ {
+		strength[valid[k]] = strength[valid[k-1]]
+		if hands[valid[k]].Compare(hands[valid[k-1]]) != evaluator.ResultIdentical {
+			strength[valid[k]]++
+		}
+	}
+
+	for a, x := range valid {
+		cx := combos[x]
+		for _, y := range valid[a+1:] {
+			cy := combos[y]
+			if cx.mask&cy.mask != 0 {
+				continue
+			}
+			score := int64(1)
+			switch {
+			case strength[x] > strength[y]:
+				score = 2
+			case strength[x] < strength[y]:
+				score = 0
+			}
+			t.headsUp[cx.hand][cy.hand] += score
+			t.headsUp[cy.hand][cx.hand] += 2 - score
+			t.pairs[cx.hand][cy.hand]++
+			t.pairs[cy.hand][cx.hand]++
+		}
+	}
+
+	// 每个组合随机抽取对手，依次增加对手数量
+	rest := cards[5:]
+	others := make([]int, 0, len(rest))
+	for _, x := range valid {
+		cx := combos[x]
+		others = others[:0]
+		for _, c := range rest {
+			if cx.mask&(1<<c) == 0 {
+				others = append(others, c)
+			}
+		}
+		best, ties := -1, 0
+		for n := 0; n < MaxOpponents; n++ {
+			for i := 2 * n; i < 2*n+2; i++ {
+				k := i + rng.Intn(len(others)-i)
+				others[i], others[k] = others[k], others[i]
+			}
+			s := strength[comboIndex(others[2*n], others[2*n+1])]
+			switch {
+			case s > best:
+				best, ties = s, 1
+			case s == best:
+				ties++
+			}
+			switch {
+			case strength[x] > best:
+				t.multi[cx.hand][n] += shares
+			case strength[x] == best:
+				t.multi[cx.hand][n] += shares / int64(ties+1)
+			}
+		}
+		t.samples[cx.hand]++
+	}
+}
+
+func (t *tally) merge(o *tally) {
+	for a := range t.headsUp {
+		for b := range t.headsUp[a] {
+			t.headsUp[a][b] += o.headsUp[a][b]
+			t.pairs[a][b] += o.pairs[a][b]
+		}
+		for n := range t.multi[a] {
+			t.multi[a][n] += o.multi[a][n]
+		}
+		t.samples[a] += o.samples[a]
+	}
+}
+
+func (t *tally) tables() (*EquityTable, *MultiwayTable) {
+	headsUp, multi := &EquityTable{}, &MultiwayTable{}
+	for a := range headsUp {
+		headsUp[a][a] = 0.5
+		for b := a + 1; b < NumHands; b++ {
+			if t.pairs[a][b] > 0 {
+				headsUp[a][b] = float64(t.headsUp[a][b]) / float64(2*t.pairs[a][b])
+				headsUp[b][a] = 1 - headsUp[a][b]
+			}
+		}
+		for n := range multi[a] {
+			if t.samples[a] > 0 {
+				multi[a][n] = float64(t.multi[a][n]) / float64(shares*t.samples[a])
+			}
+		}
+	}
+	return headsUp, multi
+}
+
+// MarshalBinary 每个胜率量化为两字节，只保存上三角
+func (t *EquityTable) MarshalBinary() ([]byte, error) {
+	data := make([]byte, NumHands*(NumHands-1))
+	i := 0
+	for a := range t {
+		for b := a + 1; b < NumHands; b++ {
+			binary.LittleEndian.PutUint16(data[i:], quantize(t[a][b]))
+			i += 2
+		}
+	}
+	return data, nil
+}
+
+func (t *EquityTable) UnmarshalBinary(data []byte) error {
+	if len(data) != NumHands*(NumHands-1) {
+		return fmt.Errorf("invalid equity table: %d bytes", len(data))
+	}
+	for a := range t {
+		t[a][a] = 0.5
+		for b := a + 1; b < NumHands; b++ {
+			t[a][b] = float64(binary.LittleEndian.Uint16(data)) / quantum
+			t[b][a] = 1 - t[a][b]
+			data = data[2:]
+		}
+	}
+	return nil
+}
+
+// MarshalBinary 每个胜率量化为两字节
+func (t *MultiwayTable) MarshalBinary() ([]byte, error) {
+	data := make([]byte, NumHands*MaxOpponents*2)
+	for h := range t {
+		for n, e := range t[h] {
+			binary.LittleEndian.PutUint16(data[(h*MaxOpponents+n)*2:], quantize(e))
+		}
+	}
+	return data, nil
+}
+
+func (t *MultiwayTable) UnmarshalBinary(data []byte) error {
+	if len(data) != NumHands*MaxOpponents*2 {
+		return fmt.Errorf("invalid multiway table: %d bytes", len(data))
+	}
+	for h := range t {
+		for n := range t[h] {
+			t[h][n] = float64(binary.LittleEndian.Uint16(data)) / quantum
+			data = data[2:]
+		}
+	}
+	return nil
+}
+
+func quantize(e float64) uint16 {
+	return uint16(math.Round(math.Max(0, math.Min(1, e)) * quantum))
+}
+
+// newDeck 52张牌的编号：花色乘以13加上表格下标
+func newDeck() []int {
+	deck := make([]int, 52)
+	for i := range deck {
+		deck[i] = i
+	}
+	return deck
+}
+
+func cardOf(c int) card.Card {
+	return card.Card{Rank: rank(c % 13), Suit: suits[c/13]}
+}
+
+func newCombos() []combo {
+	list := make([]combo, 0, NumCombos)
+	for a := 0; a < 52; a++ {
+		for b := a + 1; b < 52; b++ {
+			list = append(list, combo{
+				cards: [2]int{a, b},
+				mask:  1<<a | 1<<b,
+				hand:  HandOf(cardOf(a), cardOf(b)),
+			})
+		}
+	}
+	return list
+}
+
+// comboIndex 两张牌在 combos 中的下标
+func comboIndex(a, b int) int {
+	if a > b {
+		a, b = b, a
+	}
+	return a*(103-a)/2 + b - a - 1
+}
@@ -0,0 +1,60 @@
+package preflop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComboIndex(t *testing.T) {
+	assert.Len(t, combos, NumCombos)
+	for i, c := range combos {
+		assert.Equal(t, i, comboIndex(c.cards[0], c.cards[1]))
+		assert.Equal(t, i, comboIndex(c.cards[1], c.cards[0]))
+	}
+}
+
+func TestGenerateEquity(t *testing.T) {
+	headsUp, multiway := GenerateEquity(20, 3)
+	for a := range headsUp {
+		assert.Equal(t, 0.5, headsUp[a][a])
+		for b := range headsUp {
+			assert.InDelta(t, 1.0, headsUp.Equity(Hand(a), Hand(b))+headsUp.Equity(Hand(b), Hand(a)), 1e-12)
+		}
+		for n := 1; n <= MaxOpponents; n++ {
+			e := multiway.Equity(Hand(a), n)
+			assert.True(t, e >= 0 && e <= 1)
+		}
+	}
+	assert.Equal(t, 0.0, multiway.Equity(0, 0))
+	assert.Equal(t, 0.0, multiway.Equity(0, MaxOpponents+1))
+
+	again, _ := GenerateEquity(20, 3)
+	assert.Equal(t, headsUp, again)
+}
+
+func TestMarshalBinary(t *testing.T) {
+	headsUp, multiway := GenerateEquity(5, 1)
+
+	data, err := headsUp.MarshalBinary()
+	assert.Nil(t, err)
+	var decoded EquityTable
+	assert.Nil(t, decoded.UnmarshalBinary(data))
+	for a := range headsUp {
+		for b := range headsUp {
+			assert.InDelta(t, headsUp[a][b], decoded[a][b], 1.0/quantum)
+		}
+	}
+	assert.Error(t, decoded.UnmarshalBinary(data[1:]))
+
+	data, err = multiway.MarshalBinary()
+	assert.Nil(t, err)
+	var multi MultiwayTable
+	assert.Nil(t, multi.UnmarshalBinary(data))
+	for h := range multiway {
+		for n := range multiway[h] {
+			assert.InDelta(t, multiway[h][n], multi[h][n], 1.0/quantum)
+		}
+	}
+	assert.Error(t, multi.UnmarshalBinary(nil))
+}
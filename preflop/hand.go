@@ -0,0 +1,154 @@
+// Package preflop 德州扑克的起手牌：169种等价类、范围和翻牌前胜率
+package preflop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openpoker-dev/contrib/card"
+)
+
+type (
+	// Hand 起手牌的等价类，是 13x13 表格的下标：第 i 行第 j 列按 A 到 2 排列，
+	// 对角线是对子，右上方是同花，左下方是不同花
+	Hand int
+)
+
+const (
+	// NumHands 起手牌等价类的数量
+	NumHands = 169
+	// NumCombos 两张手牌的组合数量
+	NumCombos = 1326
+
+	rankLetters = "AKQJT98765432"
+)
+
+var (
+	ErrInvalidHand = errors.New("invalid starting hand")
+
+	suits = []card.Suit{card.SuitSpades, card.SuitHearts, card.SuitDiamond, card.SuitClubs}
+)
+
+// NewHand 由两个牌点和是否同花得到等价类，对子忽略 suited
+func NewHand(a, b card.Rank, suited bool) Hand {
+	i, j := index(a), index(b)
+	if i > j {
+		i, j = j, i
+	}
+	if i != j && !suited {
+		i, j = j, i
+	}
+	return Hand(i*13 + j)
+}
+
+// HandOf 两张手牌所属的等价类
+func HandOf(a, b card.Card) Hand {
+	return NewHand(a.Rank, b.Rank, a.Suit == b.Suit)
+}
+
+// ParseHand 解析 AA、AKs、T9o 格式的起手牌
+func ParseHand(s string) (Hand, error) {
+	if len(s) < 2 || len(s) > 3 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidHand, s)
+	}
+	i, j := strings.IndexByte(rankLetters, s[0]), strings.IndexByte(rankLetters, s[1])
+	if i < 0 || j < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidHand, s)
+	}
+	switch {
+	case i == j && len(s) == 2:
+		return Hand(i*13 + j), nil
+	case i != j && len(s) == 3 && (s[2] == 's' || s[2] == 'o'):
+		return NewHand(rank(i), rank(j), s[2] == 's'), nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidHand, s)
+}
+
+// Hands 按表格顺序排列的所有起手牌
+func Hands() []Hand {
+	hands := make([]Hand, NumHands)
+	for i := range hands {
+		hands[i] = Hand(i)
+	}
+	return hands
+}
+
+func (h Hand) String() string {
+	high, low := h.Ranks()
+	s := string(rankLetters[index(high)]) + string(rankLetters[index(low)])
+	switch {
+	case h.Pair():
+		return s
+	case h.Suited():
+		return s + "s"
+	}
+	return s + "o"
+}
+
+// Row 在 13x13 表格中的行，从0开始
+func (h Hand) Row() int {
+	return int(h) / 13
+}
+
+// Col 在 13x13 表格中的列，从0开始
+func (h Hand) Col() int {
+	return int(h) % 13
+}
+
+// Ranks 大的牌点和小的牌点
+func (h Hand) Ranks() (card.Rank, card.Rank) {
+	i, j := h.Row(), h.Col()
+	if i > j {
+		i, j = j, i
+	}
+	return rank(i), rank(j)
+}
+
+// Pair 是否对子
+func (h Hand) Pair() bool {
+	return h.Row() == h.Col()
+}
+
+// Suited 是否同花
+func (h Hand) Suited() bool {
+	return h.Row() < h.Col()
+}
+
+// Combos 所有具体的两张牌组合，对子6种，同花4种，不同花12种
+func (h Hand) Combos() [][2]card.Card {
+	high, low := h.Ranks()
+	var combos [][2]card.Card
+	for i, a := range suits {
+		for j, b := range suits {
+			switch {
+			case h.Pair() && i < j, h.Suited() && i == j, !h.Pair() && !h.Suited() && i != j:
+				combos = append(combos, [2]card.Card{{Rank: high, Suit: a}, {Rank: low, Suit: b}})
+			}
+		}
+	}
+	return combos
+}
+
+// NumCombos 具体组合的数量
+func (h Hand) NumCombos() int {
+	switch {
+	case h.Pair():
+		return 6
+	case h.Suited():
+		return 4
+	}
+	return 12
+}
+
+// index 牌点在表格中的下标，A 为0，2 为12
+func index(r card.Rank) int {
+	if r == card.RankAceAsOne {
+		r = card.RankAce
+	}
+	return int(card.RankAce - r)
+}
+
+func rank(i int) card.Rank {
+	return card.RankAce - card.Rank(i)
+}
@@ -0,0 +1,60 @@
+package preflop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHand(t *testing.T) {
+	for _, s := range []string{"AA", "AKs", "AKo", "T9o", "72s", "22"} {
+		h, err := ParseHand(s)
+		assert.Nil(t, err)
+		assert.Equal(t, s, h.String())
+	}
+
+	aa, _ := ParseHand("AA")
+	assert.Equal(t, Hand(0), aa)
+	assert.True(t, aa.Pair())
+	aks, _ := ParseHand("AKs")
+	assert.Equal(t, 0, aks.Row())
+	assert.Equal(t, 1, aks.Col())
+	assert.True(t, aks.Suited())
+	ako, _ := ParseHand("AKo")
+	assert.Equal(t, 1, ako.Row())
+	assert.Equal(t, 0, ako.Col())
+	assert.False(t, ako.Suited())
+	high, low := ako.Ranks()
+	assert.Equal(t, card.RankAce, high)
+	assert.Equal(t, card.RankKing, low)
+	twos, _ := ParseHand("22")
+	assert.Equal(t, Hand(NumHands-1), twos)
+
+	assert.Equal(t, aks, NewHand(card.RankKing, card.RankAce, true))
+	assert.Equal(t, aa, NewHand(card.RankAce, card.RankAceAsOne, true))
+	assert.Equal(t, ako, HandOf(card.NewCard("Kd"), card.NewCard("As")))
+	assert.Equal(t, aks, HandOf(card.NewCard("Kd"), card.NewCard("Ad")))
+
+	for _, s := range []string{"", "A", "AAs", "AK", "AKx", "XY", "AKso"} {
+		_, err := ParseHand(s)
+		assert.True(t, errors.Is(err, ErrInvalidHand), s)
+	}
+}
+
+func TestHandCombos(t *testing.T) {
+	total := 0
+	seen := make(map[[2]card.Card]bool)
+	for _, h := range Hands() {
+		combos := h.Combos()
+		assert.Len(t, combos, h.NumCombos(), h.String())
+		for _, c := range combos {
+			assert.Equal(t, h, HandOf(c[0], c[1]))
+			assert.False(t, seen[c])
+			seen[c] = true
+		}
+		total += len(combos)
+	}
+	assert.Equal(t, NumCombos, total)
+}
@@ -0,0 +1,202 @@
+package preflop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Range 每种起手牌的频率，取值范围 [0, 1]
+	Range [NumHands]float64
+)
+
+var (
+	ErrInvalidRange = errors.New("invalid range")
+)
+
+// ParseRange 解析以逗号分隔的范围，例如 "22+, A2s+, KTo+, K9s-K6s, 88-55, AK"
+// 不写 s 或 o 的非对子同时包括同花和不同花
+func ParseRange(s string) (Range, error) {
+	var r Range
+	for _, token := range strings.Split(s, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		hands, err := parseToken(token)
+		if err != nil {
+			return Range{}, err
+		}
+		for _, h := range hands {
+			r[h] = 1
+		}
+	}
+	return r, nil
+}
+
+// String 频率不低于一半的起手牌的简写，对子在前，然后是同花和不同花
+func (r Range) String() string {
+	var parts []string
+	// 对子按牌点从大到小
+	parts = append(parts, runs(r, 13, func(i int) Hand { return Hand(i*13 + i) }, func(from, to Hand) string {
+		switch {
+		case from == to:
+			return from.String()
+		case from == 0:
+			return to.String() + "+"
+		}
+		return from.String() + "-" + to.String()
+	})...)
+	for _, suited := range []bool{true, false} {
+		for high := 0; high < 12; high++ {
+			hand := func(k int) Hand { return NewHand(rank(high), rank(high+1+k), suited) }
+			parts = append(parts, runs(r, 12-high, hand, func(from, to Hand) string {
+				switch {
+				case from == to:
+					return from.String()
+				case from == hand(0):
+					return to.String() + "+"
+				}
+				return from.String() + "-" + to.String()
+			})...)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Chart 13x13 的表格，包括的起手牌显示名字，其他显示点
+func (r Range) Chart() string {
+	var b strings.Builder
+	for i := 0; i < 13; i++ {
+		cells := make([]string, 13)
+		for j := range cells {
+			h := Hand(i*13 + j)
+			cells[j] = "."
+			if r[h] >= 0.5 {
+				cells[j] = h.String()
+			}
+		}
+		line := ""
+		for j, c := range cells {
+			if j < 12 {
+				c = fmt.Sprintf("%-4s", c)
+			}
+			line += c
+		}
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// Combos 按频率加权的组合数量
+func (r Range) Combos() float64 {
+	var combos float64
+	for h, f := range r {
+		combos += f * float64(Hand(h).NumCombos())
+	}
+	return combos
+}
+
+// Percent 占所有组合的比例
+func (r Range) Percent() float64 {
+	return r.Combos() / NumCombos
+}
+
+// runs 把连续包括的起手牌合并，hand(k) 按从大到小的顺序给出第 k 个起手牌
+func runs(r Range, n int, hand func(int) Hand, format func(from, to Hand) string) []string {
+	var parts []string
+	for k := 0; k < n; k++ {
+		if r[hand(k)] < 0.5 {
+			continue
+		}
+		end := k
+		for end+1 < n && r[hand(end+1)] >= 0.5 {
+			end++
+		}
+		parts = append(parts, format(hand(k), hand(end)))
+		k = end
+	}
+	return parts
+}
+
+// parseToken 解析范围中的一项
+func parseToken(token string) ([]Hand, error) {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidRange, token)
+	plus := strings.HasSuffix(token, "+")
+	token = strings.TrimSuffix(token, "+")
+	from, to := token, token
+	if i := strings.IndexByte(token, '-'); i >= 0 {
+		if plus {
+			return nil, invalid
+		}
+		from, to = token[:i], token[i+1:]
+	}
+
+	// 不写 s 或 o 时展开为两种
+	if len(from) == 2 && from[0] != from[1] {
+		if len(to) != 2 {
+			return nil, invalid
+		}
+		suffix := ""
+		if plus {
+			suffix = "+"
+		}
+		suited, err := parseToken(expand(from, to, "s") + suffix)
+		if err != nil {
+			return nil, err
+		}
+		offsuit, err := parseToken(expand(from, to, "o") + suffix)
+		if err != nil {
+			return nil, err
+		}
+		return append(suited, offsuit...), nil
+	}
+
+	a, err1 := ParseHand(from)
+	b, err2 := ParseHand(to)
+	if err1 != nil || err2 != nil || a.Pair() != b.Pair() || a.Suited() != b.Suited() {
+		return nil, invalid
+	}
+	ah, al := a.Ranks()
+	bh, bl := b.Ranks()
+	var hands []Hand
+	switch {
+	case a.Pair():
+		// 22+ 到 AA，88-55 从大到小
+		hi, lo := index(ah), index(bh)
+		if plus {
+			hi = 0
+		}
+		if hi > lo {
+			hi, lo = lo, hi
+		}
+		for i := hi; i <= lo; i++ {
+			hands = append(hands, Hand(i*13+i))
+		}
+	default:
+		// A2s+ 踢脚到比大牌小一点，K9s-K6s 大牌必须相同
+		if ah != bh {
+			return nil, invalid
+		}
+		hi, lo := index(al), index(bl)
+		if plus {
+			hi = index(ah) + 1
+		}
+		if hi > lo {
+			hi, lo = lo, hi
+		}
+		for k := hi; k <= lo; k++ {
+			hands = append(hands, NewHand(ah, rank(k), a.Suited()))
+		}
+	}
+	return hands, nil
+}
+
+// expand 给 AK 或 AK-AT 这样的写法加上 s 或 o
+func expand(from, to, suffix string) string {
+	if from == to {
+		return from + suffix
+	}
+	return from + suffix + "-" + to + suffix
+}
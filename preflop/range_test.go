@@ -0,0 +1,46 @@
+package preflop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	r, err := ParseRange("22+, A2s+, KTo+, K9s-K6s, AK")
+	assert.Nil(t, err)
+	// 13 个对子，12 个 A 同花，K 不同花 3 个，K 同花 4 个，AKo
+	assert.Equal(t, float64(13*6+12*4+3*12+4*4+12), r.Combos())
+	assert.Equal(t, "22+, A2s+, K9s-K6s, AKo, KTo+", r.String())
+
+	for _, s := range []string{
+		"22+, A2s+, K9s+, ATo+",
+		"QQ-88, 55, AKs, A5s-A2s, KQo",
+		"KK+, AKs, AKo",
+	} {
+		assert.Equal(t, s, mustParse(t, s).String())
+	}
+	assert.Equal(t, "TT+, AQs+, AKo", mustParse(t, "AK, AQs, AA, KK, QQ, JJ, TT").String())
+	assert.Equal(t, "", Range{}.String())
+
+	for _, s := range []string{"AKx", "A2s+-A5s", "AKs-QJs", "AKs-AKo", "22-AKs", "A"} {
+		_, err := ParseRange(s)
+		assert.True(t, errors.Is(err, ErrInvalidRange), s)
+	}
+}
+
+func TestRangeChart(t *testing.T) {
+	r := mustParse(t, "QQ+, AKs, AKo")
+	chart := r.Chart()
+	assert.Equal(t, "AA  AKs .   .   .   .   .   .   .   .   .   .   .\n", chart[:len("AA  AKs .   .   .   .   .   .   .   .   .   .   .\n")])
+	assert.Contains(t, chart, "AKo KK  .")
+	assert.Contains(t, chart, ".   .   QQ  .")
+	assert.InDelta(t, 34.0/NumCombos, r.Percent(), 1e-12)
+}
+
+func mustParse(t *testing.T, s string) Range {
+	r, err := ParseRange(s)
+	assert.Nil(t, err)
+	return r
+}
@@ -0,0 +1,103 @@
+package preflop
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+
+	"github.com/openpoker-dev/contrib/card"
+)
+
+//go:generate go run ./cmd/equitygen -boards 30000 -seed 1
+
+var (
+	//go:embed equity_headsup.bin
+	headsUpData []byte
+	//go:embed equity_multiway.bin
+	multiwayData []byte
+
+	loadTables sync.Once
+	headsUp    EquityTable
+	multiway   MultiwayTable
+)
+
+// HeadsUpEquity 内置的单挑胜率表，由 GenerateEquity 生成
+func HeadsUpEquity() *EquityTable {
+	load()
+	return &headsUp
+}
+
+// MultiwayEquity 内置的多人胜率表，由 GenerateEquity 生成
+func MultiwayEquity() *MultiwayTable {
+	load()
+	return &multiway
+}
+
+// CardsEquity 查表得到手牌 a 对手牌 b 的胜率
+// 表按起手牌的等价类计算，不区分具体花色，结果是两个等价类所有组合的平均胜率
+// 手牌不是两张不同的标准牌，或者 a 和 b 有相同的牌时返回 ErrInvalidHand
+func CardsEquity(a, b [2]card.Card) (float64, error) {
+	x, err := Classify(a[0], a[1])
+	if err != nil {
+		return 0, err
+	}
+	y, err := Classify(b[0], b[1])
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range a {
+		if c == b[0] || c == b[1] {
+			return 0, fmt.Errorf("%w: shared %s", ErrInvalidHand, c.ASCII())
+		}
+	}
+	return HeadsUpEquity().Equity(x, y), nil
+}
+
+// NamesEquity 查表得到 AKs、T9o 格式的起手牌之间的胜率
+func NamesEquity(a, b string) (float64, error) {
+	x, err := ParseHand(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := ParseHand(b)
+	if err != nil {
+		return 0, err
+	}
+	return HeadsUpEquity().Equity(x, y), nil
+}
+
+// CardsEquityVs 查表得到手牌对 opponents 个随机对手的胜率，不区分具体花色
+// 手牌不是两张不同的标准牌时返回 ErrInvalidHand
+func CardsEquityVs(hole [2]card.Card, opponents int) (float64, error) {
+	h, err := Classify(hole[0], hole[1])
+	if err != nil {
+		return 0, err
+	}
+	if opponents < 1 || opponents > MaxOpponents {
+		return 0, fmt.Errorf("%w: %d opponents", ErrInvalidHand, opponents)
+	}
+	return MultiwayEquity().Equity(h, opponents), nil
+}
+
+// NameEquityVs 查表得到起手牌对 opponents 个随机对手的胜率
+func NameEquityVs(name string, opponents int) (float64, error) {
+	h, err := ParseHand(name)
+	if err != nil {
+		return 0, err
+	}
+	if opponents < 1 || opponents > MaxOpponents {
+		return 0, fmt.Errorf("%w: %d opponents", ErrInvalidHand, opponents)
+	}
+	return MultiwayEquity().Equity(h, opponents), nil
+}
+
+func load() {
+	loadTables.Do(func() {
+		if err := headsUp.UnmarshalBinary(headsUpData); err != nil {
+			panic(err)
+		}
+		if err := multiway.UnmarshalBinary(multiwayData); err != nil {
+			panic(err)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package preflop
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadsUpEquity(t *testing.T) {
+	for _, c := range []struct {
+		a, b   string
+		equity float64
+	}{
+		{"AA", "KK", 0.82},
+		{"AKs", "QQ", 0.46},
+		{"AKo", "22", 0.47},
+		{"72o", "AA", 0.12},
+		{"AKo", "AKo", 0.5},
+	} {
+		e, err := NamesEquity(c.a, c.b)
+		assert.Nil(t, err)
+		assert.InDelta(t, c.equity, e, 0.015, c.a+" vs "+c.b)
+	}
+	_, err := NamesEquity("AA", "AAs")
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	_, err = NamesEquity("1A", "AA")
+	assert.ErrorIs(t, err, ErrInvalidHand)
+
+	aces := [2]card.Card{card.NewCard("As"), card.NewCard("Ah")}
+	kings := [2]card.Card{card.NewCard("Kd"), card.NewCard("Kc")}
+	e, err := CardsEquity(aces, kings)
+	assert.Nil(t, err)
+	assert.Equal(t, HeadsUpEquity()[0][14], e)
+	_, err = CardsEquity(aces, [2]card.Card{card.NewCard("Kd"), card.NewCard("As")})
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	_, err = CardsEquity(aces, [2]card.Card{card.NewCard("Kd"), card.NewCard("Kd")})
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	_, err = CardsEquity([2]card.Card{}, kings)
+	assert.ErrorIs(t, err, ErrInvalidHand)
+}
+
+func TestMultiwayEquity(t *testing.T) {
+	for _, c := range []struct {
+		hand      string
+		opponents int
+		equity    float64
+	}{
+		{"AA", 1, 0.85},
+		{"AA", 9, 0.31},
+		{"KK", 1, 0.82},
+		{"AKs", 1, 0.67},
+		{"72o", 1, 0.35},
+		{"22", 1, 0.50},
+	} {
+		e, err := NameEquityVs(c.hand, c.opponents)
+		assert.Nil(t, err)
+		assert.InDelta(t, c.equity, e, 0.015, c.hand)
+	}
+	// 对手越多胜率越低
+	for _, h := range Hands() {
+		for n := 2; n <= MaxOpponents; n++ {
+			assert.Less(t, MultiwayEquity().Equity(h, n), MultiwayEquity().Equity(h, n-1)+0.005, h.String())
+		}
+	}
+
+	_, err := NameEquityVs("AA", 0)
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	_, err = NameEquityVs("AAA", 1)
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	e, err := CardsEquityVs([2]card.Card{card.NewCard("7s"), card.NewCard("2h")}, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, MultiwayEquity().Equity(NewHand(card.RankSeven, card.RankTwo, false), 1), e)
+	_, err = CardsEquityVs([2]card.Card{card.NewCard("7s"), card.NewCard("2h")}, MaxOpponents+1)
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	_, err = CardsEquityVs([2]card.Card{card.NewCard("7s"), card.NewCard("7s")}, 1)
+	assert.ErrorIs(t, err, ErrInvalidHand)
+	_, err = CardsEquityVs([2]card.Card{{}, card.NewCard("2h")}, 1)
+	assert.ErrorIs(t, err, ErrInvalidHand)
+}
+
+// TestEmbeddedTables 内置的表与牌型计算的结果一致
+func TestEmbeddedTables(t *testing.T) {
+	em := evaluator.NewEvaluatorManager()
+	rng := rand.New(rand.NewSource(5))
+	table := HeadsUpEquity()
+	for i := 0; i < 20; i++ {
+		a, b := Hand(rng.Intn(NumHands)), Hand(rng.Intn(NumHands))
+		assert.InDelta(t, simulate(em, rng, a, b, 2000), table.Equity(a, b), 0.035, a.String()+" vs "+b.String())
+	}
+
+	// 对一个随机对手的胜率约等于按组合数加权的单挑胜率
+	for _, a := range Hands() {
+		var weighted float64
+		for _, b := range Hands() {
+			weighted += float64(b.NumCombos()) * table.Equity(a, b)
+		}
+		assert.InDelta(t, weighted/NumCombos, MultiwayEquity().Equity(a, 1), 0.02, a.String())
+	}
+}
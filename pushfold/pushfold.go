@@ -0,0 +1,195 @@
+// Package pushfold 短筹码的全下或弃牌纳什均衡
+package pushfold
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openpoker-dev/contrib/preflop"
+)
+
+type (
+	// Config 求解配置，筹码和前注以大盲为单位
+	// 位置从0开始按行动顺序排列，最后两个位置是小盲和大盲，两人时位置0是小盲
+	Config struct {
+		Players    int
+		Stack      float64        // 所有玩家相同的有效筹码，包括要下的盲注和前注
+		Ante       float64        // 每个玩家的前注
+		Equity     preflop.Equity // 起手牌之间的胜率
+		Iterations int            // 虚拟博弈的迭代次数，为空时为 500
+	}
+
+	// Solution 均衡策略
+	Solution struct {
+		Config Config
+		Push   []preflop.Range   // Push[i] 为前面的玩家都弃牌时位置 i 全下的范围，大盲没有
+		Call   [][]preflop.Range // Call[j][i] 为位置 i 全下、中间的玩家都弃牌时位置 j 跟注的范围
+	}
+
+	// model 求解时不变的量
+	model struct {
+		cfg     Config
+		blinds  []float64
+		compat  [preflop.NumHands][preflop.NumHands]float64 // 一手牌确定时另一种起手牌不冲突的组合数
+		equity  [preflop.NumHands][preflop.NumHands]float64
+		remains float64 // 一手牌确定时剩下的组合数
+	}
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid push/fold config")
+
+	// anyTwo 任意两张牌
+	anyTwo = func() (r preflop.Range) {
+		for h := range r {
+			r[h] = 1
+		}
+		return r
+	}()
+)
+
+// Solve 用线性加权的虚拟博弈求解全下或弃牌的均衡：第一个入池的玩家只能全下或弃牌，
+// 后面的玩家只能跟注或弃牌，简化为只有第一个跟注的玩家参与摊牌，其他玩家弃牌
+func Solve(cfg Config) (*Solution, error) {
+	if cfg.Players < 2 || cfg.Players > 10 {
+		return nil, fmt.Errorf("%w: %d players", ErrInvalidConfig, cfg.Players)
+	}
+	if cfg.Ante < 0 || cfg.Stack <= cfg.Ante+1 || cfg.Equity == nil {
+		return nil, fmt.Errorf("%w: stack %g, ante %g", ErrInvalidConfig, cfg.Stack, cfg.Ante)
+	}
+	if cfg.Iterations <= 0 {
+		cfg.Iterations = 500
+	}
+	m := newModel(cfg)
+
+	n := cfg.Players
+	s := &Solution{Config: cfg, Push: make([]preflop.Range, n-1), Call: make([][]preflop.Range, n)}
+	for j := range s.Call {
+		s.Call[j] = make([]preflop.Range, n)
+	}
+	for t := 1; t <= cfg.Iterations; t++ {
+		// 所有位置同时对平均策略做最优反应，再并入平均策略
+		push := make([]preflop.Range, n-1)
+		for i := range push {
+			for h := range push[i] {
+				if m.pushValue(s, i, preflop.Hand(h)) > m.foldValue(i) {
+					push[i][h] = 1
+				}
+			}
+		}
+		call := make([][]preflop.Range, n)
+		for j := range call {
+			call[j] = make([]preflop.Range, n)
+			for i := 0; i < j; i++ {
+				for h := range call[j][i] {
+					if m.callValue(s, i, j, preflop.Hand(h)) > m.foldValue(j) {
+						call[j][i][h] = 1
+					}
+				}
+			}
+		}
+
+		// 按迭代次数线性加权，减少早期最优反应的影响
+		w := 2 / float64(t+1)
+		for i := range push {
+			mix(&s.Push[i], &push[i], w)
+		}
+		for j := range call {
+			for i := 0; i < j; i++ {
+				mix(&s.Call[j][i], &call[j][i], w)
+			}
+		}
+	}
+	return s, nil
+}
+
+// PushEV 位置 i 拿 h 全下比弃牌多赢的大盲数
+func (s *Solution) PushEV(i int, h preflop.Hand) float64 {
+	m := newModel(s.Config)
+	return m.pushValue(s, i, h) - m.foldValue(i)
+}
+
+// CallEV 位置 j 拿 h 跟注位置 i 的全下比弃牌多赢的大盲数
+func (s *Solution) CallEV(i, j int, h preflop.Hand) float64 {
+	m := newModel(s.Config)
+	return m.callValue(s, i, j, h) - m.foldValue(j)
+}
+
+func newModel(cfg Config) *model {
+	n := cfg.Players
+	m := &model{cfg: cfg, blinds: make([]float64, n), remains: 50 * 49 / 2}
+	m.blinds[n-2], m.blinds[n-1] = 0.5, 1
+	for a := range m.compat {
+		combo := preflop.Hand(a).Combos()[0]
+		for b := range m.compat[a] {
+			for _, other := range preflop.Hand(b).Combos() {
+				if other[0] != combo[0] && other[0] != combo[1] && other[1] != combo[0] && other[1] != combo[1] {
+					m.compat[a][b]++
+				}
+			}
+			m.equity[a][b] = cfg.Equity.Equity(preflop.Hand(a), preflop.Hand(b))
+		}
+	}
+	return m
+}
+
+// foldValue 弃牌时输掉已经下的盲注和前注
+func (m *model) foldValue(i int) float64 {
+	return -(m.cfg.Ante + m.blinds[i])
+}
+
+// pushValue 位置 i 拿 h 全下时的筹码变化
+func (m *model) pushValue(s *Solution, i int, h preflop.Hand) float64 {
+	n := m.cfg.Players
+	// 所有人弃牌时赢得盲注和前注
+	folded := 1.0
+	var value float64
+	for j := i + 1; j < n; j++ {
+		p, equity := m.against(&s.Call[j][i], h)
+		if p > 0 {
+			value += folded * p * m.showdown(i, j, equity)
+		}
+		folded *= 1 - p
+	}
+	return value + folded*(float64(n)*m.cfg.Ante+1.5+m.foldValue(i))
+}
+
+// callValue 位置 j 拿 h 跟注位置 i 的全下时的筹码变化，全下范围为空时按任意两张牌计算
+func (m *model) callValue(s *Solution, i, j int, h preflop.Hand) float64 {
+	p, equity := m.against(&s.Push[i], h)
+	if p == 0 {
+		_, equity = m.against(&anyTwo, h)
+	}
+	return m.showdown(j, i, equity)
+}
+
+// showdown 位置 i 和 j 全下摊牌时 i 的筹码变化，equity 为 i 的胜率
+func (m *model) showdown(i, j int, equity float64) float64 {
+	stack, ante := m.cfg.Stack, m.cfg.Ante
+	// 双方的有效筹码，所有人的前注，以及其他玩家弃掉的盲注
+	pot := 2*(stack-ante) + float64(m.cfg.Players)*ante + 1.5 - m.blinds[i] - m.blinds[j]
+	return equity*pot - stack
+}
+
+// against 对手的牌在范围 r 中的概率，以及 h 对这部分牌的平均胜率
+func (m *model) against(r *preflop.Range, h preflop.Hand) (float64, float64) {
+	var combos, won float64
+	for b, f := range r {
+		if f > 0 {
+			c := f * m.compat[h][b]
+			combos += c
+			won += c * m.equity[h][b]
+		}
+	}
+	if combos == 0 {
+		return 0, 0
+	}
+	return combos / m.remains, won / combos
+}
+
+// mix 平均策略向最优反应移动 w
+func mix(average, response *preflop.Range, w float64) {
+	for h := range average {
+		average[h] += w * (response[h] - average[h])
+	}
+}
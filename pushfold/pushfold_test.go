@@ -0,0 +1,106 @@
+package pushfold
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/preflop"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	once   sync.Once
+	equity *preflop.EquityTable
+)
+
+// simulated 测试共用的粗略胜率表
+func simulated() *preflop.EquityTable {
+	once.Do(func() {
+		equity = preflop.SimulateEquity(6, 1)
+	})
+	return equity
+}
+
+// coinflip 所有起手牌胜率相同
+type coinflip struct{}
+
+func (coinflip) Equity(a, b preflop.Hand) float64 {
+	return 0.5
+}
+
+func hand(t *testing.T, s string) preflop.Hand {
+	h, err := preflop.ParseHand(s)
+	assert.Nil(t, err)
+	return h
+}
+
+func TestSolveCoinflip(t *testing.T) {
+	// 胜率相同时跟注不亏，所以总是跟注；全下不亏，所以总是全下
+	s, err := Solve(Config{Players: 2, Stack: 10, Equity: coinflip{}, Iterations: 50})
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, s.Push[0].Percent())
+	assert.Equal(t, 1.0, s.Call[1][0].Percent())
+	assert.Equal(t, "22+, A2s+, K2s+, Q2s+, J2s+, T2s+, 92s+, 82s+, 72s+, 62s+, 52s+, 42s+, 32s, A2o+, K2o+, Q2o+, J2o+, T2o+, 92o+, 82o+, 72o+, 62o+, 52o+, 42o+, 32o", s.Push[0].String())
+	assert.InDelta(t, 0.5, s.PushEV(0, hand(t, "72o")), 1e-9)
+	assert.InDelta(t, 1, s.CallEV(0, 1, hand(t, "72o")), 1e-9)
+}
+
+func TestSolveHeadsUp(t *testing.T) {
+	s, err := Solve(Config{Players: 2, Stack: 10, Equity: simulated()})
+	assert.Nil(t, err)
+	push, call := s.Push[0], s.Call[1][0]
+	// 10个大盲时小盲大约全下六成的牌，大盲跟注的范围更窄
+	assert.InDelta(t, 0.6, push.Percent(), 0.1)
+	assert.Less(t, call.Percent(), push.Percent())
+	for _, h := range []string{"AA", "KK", "AKo", "A2s", "22"} {
+		assert.Greater(t, push[hand(t, h)], 0.99, h)
+		assert.Greater(t, call[hand(t, h)], 0.99, h)
+		assert.Greater(t, s.PushEV(0, hand(t, h)), 0.0, h)
+	}
+	for _, h := range []string{"72o", "32o", "42o"} {
+		assert.Less(t, push[hand(t, h)], 0.01, h)
+		assert.Less(t, s.PushEV(0, hand(t, h)), 0.0, h)
+	}
+	lines := 0
+	for _, c := range push.Chart() {
+		if c == '\n' {
+			lines++
+		}
+	}
+	assert.Equal(t, 13, lines)
+
+	// 筹码越深范围越窄，有前注时范围更宽
+	deep, err := Solve(Config{Players: 2, Stack: 20, Equity: simulated()})
+	assert.Nil(t, err)
+	assert.Less(t, deep.Push[0].Percent(), push.Percent())
+	antes, err := Solve(Config{Players: 2, Stack: 10, Ante: 0.25, Equity: simulated()})
+	assert.Nil(t, err)
+	assert.Greater(t, antes.Push[0].Percent(), push.Percent())
+}
+
+func TestSolveRing(t *testing.T) {
+	s, err := Solve(Config{Players: 4, Stack: 10, Equity: simulated(), Iterations: 200})
+	assert.Nil(t, err)
+	assert.Len(t, s.Push, 3)
+	// 越靠前全下的范围越窄
+	assert.Less(t, s.Push[0].Percent(), s.Push[1].Percent())
+	assert.Less(t, s.Push[1].Percent(), s.Push[2].Percent())
+	// 面对越靠前的全下跟注越紧
+	assert.Less(t, s.Call[3][0].Percent(), s.Call[3][2].Percent())
+	assert.Equal(t, 0.0, s.Call[0][0].Percent())
+	assert.Greater(t, s.Push[0][hand(t, "AA")], 0.99)
+}
+
+func TestSolveInvalid(t *testing.T) {
+	for _, cfg := range []Config{
+		{Players: 1, Stack: 10, Equity: coinflip{}},
+		{Players: 11, Stack: 10, Equity: coinflip{}},
+		{Players: 2, Stack: 1, Equity: coinflip{}},
+		{Players: 2, Stack: 10, Ante: -1, Equity: coinflip{}},
+		{Players: 2, Stack: 10},
+	} {
+		_, err := Solve(cfg)
+		assert.True(t, errors.Is(err, ErrInvalidConfig))
+	}
+}
@@ -0,0 +1,87 @@
+package table
+
+type (
+	// BettingStructure 下注结构，计算下注和加注后总额的范围
+	// 返回的范围不考虑玩家的筹码，筹码不足时由牌局按全下处理
+	BettingStructure interface {
+		BetRange(BettingState) (min, max int64)
+		RaiseRange(BettingState) (min, max int64, ok bool)
+	}
+
+	// BettingState 计算下注范围时的牌局状态
+	BettingState struct {
+		Street     Street
+		BigBlind   int64
+		CurrentBet int64 // 本轮最高下注额
+		LastRaise  int64 // 本轮最近一次完整加注的幅度
+		Committed  int64 // 行动玩家本轮已经下注的筹码
+		Stack      int64 // 行动玩家剩余的筹码
+		Pot        int64 // 底池中的筹码，包括本轮所有的下注
+		Bets       int   // 本轮完整下注和加注的次数，翻牌前大盲算一次
+	}
+
+	// NoLimit 无限注
+	NoLimit struct{}
+
+	// PotLimit 底池限注，最多加注到跟注后的底池大小
+	PotLimit struct{}
+
+	// FixedLimit 固定限注，翻牌前和翻牌圈按 SmallBet 下注，转牌和河牌按 BigBet 下注
+	FixedLimit struct {
+		SmallBet int64
+		BigBet   int64
+		Cap      int // 每轮下注和加注的次数上限，为 0 时使用 4
+	}
+)
+
+var (
+	_ BettingStructure = NoLimit{}
+	_ BettingStructure = PotLimit{}
+	_ BettingStructure = FixedLimit{}
+)
+
+func (NoLimit) BetRange(s BettingState) (int64, int64) {
+	return s.BigBlind, s.Committed + s.Stack
+}
+
+func (NoLimit) RaiseRange(s BettingState) (int64, int64, bool) {
+	return s.CurrentBet + s.LastRaise, s.Committed + s.Stack, true
+}
+
+func (PotLimit) BetRange(s BettingState) (int64, int64) {
+	max := s.Pot
+	if max < s.BigBlind {
+		max = s.BigBlind
+	}
+	return s.BigBlind, max
+}
+
+// RaiseRange 先跟注，再加注底池大小
+func (PotLimit) RaiseRange(s BettingState) (int64, int64, bool) {
+	toCall := s.CurrentBet - s.Committed
+	return s.CurrentBet + s.LastRaise, s.CurrentBet + s.Pot + toCall, true
+}
+
+func (fl FixedLimit) BetRange(s BettingState) (int64, int64) {
+	size := fl.size(s.Street)
+	return size, size
+}
+
+func (fl FixedLimit) RaiseRange(s BettingState) (int64, int64, bool) {
+	cap := fl.Cap
+	if cap == 0 {
+		cap = 4
+	}
+	if s.Bets >= cap {
+		return 0, 0, false
+	}
+	to := s.CurrentBet + fl.size(s.Street)
+	return to, to, true
+}
+
+func (fl FixedLimit) size(street Street) int64 {
+	if street >= StreetTurn {
+		return fl.BigBet
+	}
+	return fl.SmallBet
+}
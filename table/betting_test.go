@@ -0,0 +1,135 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/stretchr/testify/assert"
+)
+
+func shuffledDeck() card.Deck {
+	deck := card.NewFiftyTwoCardsDeck()
+	deck.Shuffle()
+	return deck
+}
+
+func legalOf(h *Hand, at ActionType) (LegalAction, bool) {
+	for _, legal := range h.LegalActions() {
+		if legal.Type == at {
+			return legal, true
+		}
+	}
+	return LegalAction{}, false
+}
+
+func TestPotLimit(t *testing.T) {
+	h, err := NewHand(Config{SmallBlind: 5, BigBlind: 10, Structure: PotLimit{}}, []Player{
+		{Seat: 1, Stack: 1000},
+		{Seat: 2, Stack: 1000},
+		{Seat: 3, Stack: 1000},
+	}, 1, shuffledDeck())
+	assert.Nil(t, err)
+
+	// 跟注 10 之后底池为 25，最多加注到 35
+	raise, ok := legalOf(h, ActionRaise)
+	assert.True(t, ok)
+	assert.Equal(t, LegalAction{Type: ActionRaise, Min: 20, Max: 35}, raise)
+
+	apply(t, h, Action{Seat: 1, Type: ActionRaise, Amount: 35})
+	// 小盲跟注 30 之后底池为 80，最多加注到 115
+	raise, _ = legalOf(h, ActionRaise)
+	assert.Equal(t, LegalAction{Type: ActionRaise, Min: 60, Max: 115}, raise)
+
+	apply(t, h,
+		Action{Seat: 2, Type: ActionCall},
+		Action{Seat: 3, Type: ActionCall},
+	)
+	bet, _ := legalOf(h, ActionBet)
+	assert.Equal(t, LegalAction{Type: ActionBet, Min: 10, Max: 105}, bet)
+}
+
+func TestFixedLimitCap(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10, Structure: FixedLimit{SmallBet: 10, BigBet: 20}}, []Player{
+		{Seat: 1, Stack: 1000},
+		{Seat: 2, Stack: 1000},
+		{Seat: 3, Stack: 1000},
+	}, 1, shuffledDeck())
+
+	raise, _ := legalOf(h, ActionRaise)
+	assert.Equal(t, LegalAction{Type: ActionRaise, Min: 20, Max: 20}, raise)
+	apply(t, h,
+		Action{Seat: 1, Type: ActionRaise, Amount: 20},
+		Action{Seat: 2, Type: ActionRaise, Amount: 30},
+		Action{Seat: 3, Type: ActionRaise, Amount: 40},
+	)
+	// 大盲、加注、再加注、三次加注之后封顶
+	_, ok := legalOf(h, ActionRaise)
+	assert.False(t, ok)
+	apply(t, h,
+		Action{Seat: 1, Type: ActionCall},
+		Action{Seat: 2, Type: ActionCall},
+	)
+
+	assert.Equal(t, StreetFlop, h.Street())
+	bet, _ := legalOf(h, ActionBet)
+	assert.Equal(t, LegalAction{Type: ActionBet, Min: 10, Max: 10}, bet)
+	apply(t, h,
+		Action{Seat: 2, Type: ActionCheck},
+		Action{Seat: 3, Type: ActionCheck},
+		Action{Seat: 1, Type: ActionCheck},
+	)
+	bet, _ = legalOf(h, ActionBet)
+	assert.Equal(t, LegalAction{Type: ActionBet, Min: 20, Max: 20}, bet)
+}
+
+func TestIncompleteRaiseDoesNotReopen(t *testing.T) {
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10}, []Player{
+		{Seat: 1, Stack: 1000},
+		{Seat: 2, Stack: 130},
+		{Seat: 3, Stack: 1000},
+	}, 3, shuffledDeck())
+
+	apply(t, h,
+		Action{Seat: 3, Type: ActionRaise, Amount: 100},
+		Action{Seat: 1, Type: ActionCall},
+		// 全下 130 不足最小加注额 190
+		Action{Seat: 2, Type: ActionRaise, Amount: 130},
+	)
+	seat, _ := h.ToAct()
+	assert.Equal(t, 3, seat)
+	_, ok := legalOf(h, ActionRaise)
+	assert.False(t, ok)
+	call, _ := legalOf(h, ActionCall)
+	assert.Equal(t, int64(130), call.Max)
+	apply(t, h, Action{Seat: 3, Type: ActionCall})
+
+	_, ok = legalOf(h, ActionRaise)
+	assert.False(t, ok)
+	apply(t, h, Action{Seat: 1, Type: ActionCall})
+	assert.Equal(t, StreetFlop, h.Street())
+}
+
+func TestOmahaShowdown(t *testing.T) {
+	// 公共牌有四张黑桃，座位 2 只有一张黑桃手牌，不能组成同花
+	deck := presetDeck(t, "As 2c Ks 3s Qs 7d Js 8d 4h 2s 5s 9s 5h Ts 6h Th")
+	h, err := NewHand(Config{Variant: VariantOmaha, SmallBlind: 5, BigBlind: 10}, []Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+	}, 2, deck)
+	assert.Nil(t, err)
+	assert.Len(t, h.Seats()[0].Hole, 4)
+
+	apply(t, h,
+		Action{Seat: 2, Type: ActionCall},
+		Action{Seat: 1, Type: ActionCheck},
+	)
+	for h.Street() != StreetShowdown {
+		seat, _ := h.ToAct()
+		apply(t, h, Action{Seat: seat, Type: ActionCheck})
+	}
+	result, _ := h.Result()
+	assert.Equal(t, evaluator.RankFlush, result.Showdown[1].Rank)
+	assert.Equal(t, evaluator.RankTwoParis, result.Showdown[2].Rank)
+	assert.Equal(t, []int{1}, result.Pots[0].Winners)
+}
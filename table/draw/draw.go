@@ -0,0 +1,78 @@
+// Package draw 换牌扑克牌局的状态机，支持五张换牌和三次换牌的低牌玩法
+package draw
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Game 换牌玩法
+	Game struct {
+		Name  string
+		Draws int  // 换牌次数，最多三次
+		Low   bool // 是否是低牌玩法
+		Rule  evaluator.LowballRule
+	}
+
+	// Config 牌局配置
+	Config struct {
+		ID         string // 牌局编号，记录在牌局历史中
+		Table      string // 牌桌名，记录在牌局历史中
+		Game       Game
+		Ante       int64
+		SmallBlind int64
+		BigBlind   int64
+		Structure  table.BettingStructure     // 为空时使用 table.NoLimit
+		Evaluator  evaluator.EvaluatorManager // 为空时使用 evaluator.NewEvaluatorManager
+		// Reshuffle 牌堆不够换牌时用弃牌组成新的牌堆，为空时随机洗牌
+		Reshuffle func(cards []card.Card) card.Deck
+		Clock     func() time.Time // 牌局历史的时间戳，为空时使用 time.Now
+	}
+
+	// SeatState 座位在牌局中的状态
+	SeatState struct {
+		table.Stake
+		Cards []card.Card
+		Drawn []int // 每次换牌换掉的张数
+	}
+)
+
+var (
+	FiveCardDraw = Game{Name: "five card draw", Draws: 1}
+
+	DeuceToSevenSingleDraw = Game{Name: "2-7 single draw", Draws: 1, Low: true, Rule: evaluator.LowballDeuceToSeven}
+
+	DeuceToSevenTripleDraw = Game{Name: "2-7 triple draw", Draws: 3, Low: true, Rule: evaluator.LowballDeuceToSeven}
+
+	AceToFiveTripleDraw = Game{Name: "A-5 triple draw", Draws: 3, Low: true, Rule: evaluator.LowballAceToFive}
+)
+
+var (
+	// historyStreets 按换牌次数的下注轮
+	historyStreets = []history.Street{history.StreetPredraw, history.StreetFirstDraw, history.StreetSecondDraw, history.StreetThirdDraw}
+)
+
+// streetOf 下注轮对应的 table.Street，最后一轮为河牌，用于 FixedLimit 按换牌后的轮次使用大注
+func (g Game) streetOf(round int) table.Street {
+	if round == 0 {
+		return table.StreetPreflop
+	}
+	return table.StreetRiver - table.Street(g.Draws-round)
+}
+
+// shuffle 默认的重洗方式
+func shuffle(cards []card.Card) card.Deck {
+	cards = append([]card.Card{}, cards...)
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r.Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+	deck, _ := card.NewPresetDeck(cards...)
+	return deck
+}
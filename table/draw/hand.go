@@ -0,0 +1,378 @@
+package draw
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/pot"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Hand 一局换牌扑克，下注由 Apply 驱动，换牌由 Draw 驱动
+	Hand struct {
+		cfg        Config
+		em         evaluator.EvaluatorManager
+		structure  table.BettingStructure
+		reshuffle  func([]card.Card) card.Deck
+		seats      []*SeatState // 按座位号排序
+		betting    *table.Round
+		posts      []table.Post
+		recorder   *table.Recorder
+		button     int       // 庄家的座位号
+		stub       card.Deck // 剩余的牌堆
+		muck       []card.Card
+		reshuffles int
+		round      int  // 已经完成的换牌次数
+		drawing    bool // 是否在换牌
+		toAct      int  // 当前行动或换牌的座位下标，-1 表示没有
+		result     *table.Result
+	}
+)
+
+var (
+	ErrNotDrawing = errors.New("not drawing")
+	ErrBadDiscard = errors.New("bad discard")
+)
+
+// NewHand 开始一局牌：下前注和盲注，每人发五张暗牌，deck 需要已经洗好
+// button 为庄家的座位号，可以是空座位
+func NewHand(cfg Config, players []table.Player, button int, deck card.Deck) (*Hand, error) {
+	if cfg.SmallBlind <= 0 || cfg.BigBlind < cfg.SmallBlind || cfg.Ante < 0 {
+		return nil, errors.New("bad blinds")
+	}
+	if cfg.Game.Draws <= 0 || cfg.Game.Draws >= len(historyStreets) {
+		return nil, errors.New("bad game")
+	}
+	if len(players) < 2 {
+		return nil, errors.New("at least two players")
+	}
+
+	h := &Hand{
+		cfg:       cfg,
+		em:        cfg.Evaluator,
+		structure: cfg.Structure,
+		reshuffle: cfg.Reshuffle,
+		button:    button,
+		stub:      deck,
+		toAct:     -1,
+	}
+	if h.em == nil {
+		h.em = evaluator.NewEvaluatorManager()
+	}
+	if h.structure == nil {
+		h.structure = table.NoLimit{}
+	}
+	if h.reshuffle == nil {
+		h.reshuffle = shuffle
+	}
+
+	seatNumbers := make([]int, 0, len(players))
+	for _, p := range players {
+		if p.Stack <= 0 {
+			return nil, fmt.Errorf("seat %d has no chips", p.Seat)
+		}
+		h.seats = append(h.seats, &SeatState{Stake: table.Stake{Player: p}})
+		seatNumbers = append(seatNumbers, p.Seat)
+	}
+	sort.Slice(h.seats, func(i, j int) bool {
+		return h.seats[i].Seat < h.seats[j].Seat
+	})
+	stakes := make([]*table.Stake, len(h.seats))
+	for i, seat := range h.seats {
+		stakes[i] = &seat.Stake
+	}
+	h.betting = table.NewRound(stakes)
+	h.recordStart()
+
+	dealer, err := card.NewDealer(deck, card.DrawProcedure, seatNumbers, button)
+	if err != nil {
+		return nil, err
+	}
+	bb := h.postForcedBets()
+	if _, err := dealer.Next(); err != nil {
+		return nil, err
+	}
+	for _, seat := range h.seats {
+		for _, dc := range dealer.Hand(seat.Seat) {
+			seat.Cards = append(seat.Cards, dc.Card)
+		}
+		h.recorder.Deal(seat.Seat, seat.Cards)
+	}
+
+	h.toAct = h.betting.Next(bb)
+	if h.toAct < 0 {
+		if err := h.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// Apply 执行当前行动玩家的下注行动
+func (h *Hand) Apply(a table.Action) error {
+	if h.result != nil {
+		return table.ErrHandOver
+	}
+	if h.drawing || h.toAct < 0 || h.seats[h.toAct].Seat != a.Seat {
+		return table.ErrNotYourTurn
+	}
+
+	legal, ok := h.legal(a.Type)
+	if !ok {
+		return fmt.Errorf("%w: %s", table.ErrIllegalAction, a.Type)
+	}
+	seat := h.seats[h.toAct]
+	before := seat.Committed
+	to, err := h.betting.Act(h.toAct, a, legal)
+	if err != nil {
+		return err
+	}
+	if a.Type == table.ActionFold {
+		h.muck = append(h.muck, seat.Cards...)
+	}
+	h.recorder.Action(historyStreets[h.round], &seat.Stake, a.Type, seat.Committed-before, to)
+
+	if h.betting.Complete() {
+		return h.advance()
+	}
+	h.toAct = h.betting.Next(h.toAct)
+	return nil
+}
+
+// Draw 当前换牌的玩家换掉 discards，返回补发的牌，不换牌时 discards 为空
+// 牌堆的最后一张牌不发，和弃牌一起重洗成新的牌堆，当前玩家换掉的牌不参与重洗
+func (h *Hand) Draw(seatNumber int, discards ...card.Card) ([]card.Card, error) {
+	if h.result != nil {
+		return nil, table.ErrHandOver
+	}
+	if !h.drawing {
+		return nil, ErrNotDrawing
+	}
+	if h.toAct < 0 || h.seats[h.toAct].Seat != seatNumber {
+		return nil, table.ErrNotYourTurn
+	}
+
+	seat := h.seats[h.toAct]
+	kept := append([]card.Card{}, seat.Cards...)
+	for _, c := range discards {
+		index := -1
+		for i, held := range kept {
+			if held == c {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("%w: seat %d does not hold %s", ErrBadDiscard, seatNumber, c.ASCII())
+		}
+		kept = append(kept[:index], kept[index+1:]...)
+	}
+
+	drawn, err := h.replace(len(discards))
+	if err != nil {
+		return nil, err
+	}
+	seat.Cards = append(kept, drawn...)
+	seat.Drawn = append(seat.Drawn, len(discards))
+	h.muck = append(h.muck, discards...)
+	h.recorder.Draw(historyStreets[h.round+1], seatNumber, discards, drawn)
+
+	h.toAct = h.nextToDraw(h.toAct)
+	if h.toAct >= 0 {
+		return drawn, nil
+	}
+
+	// 所有人都换完牌，开始下一轮下注
+	h.drawing = false
+	h.round++
+	h.toAct = h.betting.Next(h.betting.Button(h.button))
+	if h.toAct < 0 || !h.betting.OthersCanAct(h.toAct) {
+		if err := h.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return drawn, nil
+}
+
+// LegalActions 当前行动玩家可以做的下注行动，换牌时为空
+func (h *Hand) LegalActions() []table.LegalAction {
+	if h.result != nil || h.drawing || h.toAct < 0 {
+		return nil
+	}
+	actions := make([]table.LegalAction, 0, 3)
+	for _, at := range []table.ActionType{table.ActionFold, table.ActionCheck, table.ActionCall, table.ActionBet, table.ActionRaise} {
+		if legal, ok := h.legal(at); ok {
+			actions = append(actions, legal)
+		}
+	}
+	return actions
+}
+
+// ToAct 当前行动或换牌的座位号
+func (h *Hand) ToAct() (int, bool) {
+	if h.result != nil || h.toAct < 0 {
+		return 0, false
+	}
+	return h.seats[h.toAct].Seat, true
+}
+
+// Drawing 是否在换牌
+func (h *Hand) Drawing() bool {
+	return h.drawing
+}
+
+// Round 已经完成的换牌次数，也是当前下注轮的序号
+func (h *Hand) Round() int {
+	return h.round
+}
+
+// Done 牌局是否已经结束
+func (h *Hand) Done() bool {
+	return h.result != nil
+}
+
+func (h *Hand) Button() int {
+	return h.button
+}
+
+// Seats 所有座位状态的副本
+func (h *Hand) Seats() []SeatState {
+	states := make([]SeatState, 0, len(h.seats))
+	for _, seat := range h.seats {
+		state := *seat
+		state.Cards = append([]card.Card{}, seat.Cards...)
+		state.Drawn = append([]int{}, seat.Drawn...)
+		states = append(states, state)
+	}
+	return states
+}
+
+// Pot 底池中的筹码，包括本轮的下注
+func (h *Hand) Pot() int64 {
+	return h.betting.Pot()
+}
+
+// Posts 本局的强制下注
+func (h *Hand) Posts() []table.Post {
+	return append([]table.Post{}, h.posts...)
+}
+
+// Muck 弃牌堆，包括烧牌、弃牌玩家的手牌和换掉的牌，重洗后清空
+func (h *Hand) Muck() []card.Card {
+	return append([]card.Card{}, h.muck...)
+}
+
+// Reshuffles 重洗弃牌的次数
+func (h *Hand) Reshuffles() int {
+	return h.reshuffles
+}
+
+// Result 牌局结束后返回结果
+func (h *Hand) Result() (*table.Result, bool) {
+	return h.result, h.result != nil
+}
+
+func (h *Hand) legal(at table.ActionType) (table.LegalAction, bool) {
+	state := h.betting.State(h.toAct, h.cfg.Game.streetOf(h.round), h.cfg.BigBlind)
+	return h.betting.Legal(h.toAct, at, h.structure, state)
+}
+
+// postForcedBets 下前注和盲注，返回大盲的座位下标
+func (h *Hand) postForcedBets() int {
+	sb, bb := h.betting.Blinds(h.button)
+
+	if h.cfg.Ante > 0 {
+		for i := range h.seats {
+			h.post(i, table.PostAnte, h.cfg.Ante)
+		}
+	}
+	h.post(sb, table.PostSmallBlind, h.cfg.SmallBlind)
+	h.post(bb, table.PostBigBlind, h.cfg.BigBlind)
+	h.betting.CurrentBet = h.cfg.BigBlind
+	h.betting.LastRaise = h.cfg.BigBlind
+	h.betting.Bets = 1
+	return bb
+}
+
+// post 强制下注，筹码不足时全下
+func (h *Hand) post(index int, pt table.PostType, amount int64) {
+	if p, ok := h.betting.Post(index, pt, amount); ok {
+		h.posts = append(h.posts, p)
+		h.recorder.Post(p)
+	}
+}
+
+// replace 从牌堆补发 n 张牌，只剩最后一张时和弃牌堆一起重洗
+func (h *Hand) replace(n int) ([]card.Card, error) {
+	if h.stub.Length()+len(h.muck) < n {
+		return nil, fmt.Errorf("%w: not enough cards to draw %d", card.ErrMisdeal, n)
+	}
+	drawn := make([]card.Card, 0, n)
+	for len(drawn) < n {
+		if h.stub.Length() > 1 || len(h.muck) == 0 {
+			c, _ := h.stub.Deal()
+			drawn = append(drawn, c)
+			continue
+		}
+		cards := append([]card.Card{}, h.muck...)
+		if last, ok := h.stub.Deal(); ok {
+			cards = append(cards, last)
+		}
+		h.muck = nil
+		h.stub = h.reshuffle(cards)
+		h.reshuffles++
+	}
+	return drawn, nil
+}
+
+// advance 结束本轮下注，开始换牌，换完最后一次后摊牌
+func (h *Hand) advance() error {
+	if h.betting.Remaining() <= 1 || h.round >= h.cfg.Game.Draws {
+		h.finish()
+		return nil
+	}
+	h.betting.Reset(h.round+1, h.cfg.BigBlind)
+
+	// 换牌前烧一张牌
+	if h.stub.Length() > 1 {
+		c, _ := h.stub.Deal()
+		h.muck = append(h.muck, c)
+	}
+	h.drawing = true
+	h.toAct = h.nextToDraw(h.betting.Button(h.button))
+	return nil
+}
+
+// finish 分配底池
+func (h *Hand) finish() {
+	h.drawing = false
+	h.toAct = -1
+	distributor := pot.Distributor{Button: h.button, Low: h.cfg.Game.Low}
+	h.result = h.betting.Settle(distributor, func(i int) evaluator.PokerHand {
+		return h.evaluate(h.seats[i].Cards)
+	})
+	h.recordResult(h.result)
+}
+
+func (h *Hand) evaluate(cards []card.Card) evaluator.PokerHand {
+	if h.cfg.Game.Low {
+		return evaluator.EvaluateLow(h.em, h.cfg.Game.Rule, cards...)
+	}
+	return h.em.Evaluate(append([]card.Card{}, cards...)...)
+}
+
+// nextToDraw index 之后第一个本次还没有换牌的座位，全下的玩家也要换牌，没有时返回 -1
+func (h *Hand) nextToDraw(index int) int {
+	for i := 1; i <= len(h.seats); i++ {
+		next := (index + i) % len(h.seats)
+		seat := h.seats[next]
+		if !seat.Folded && len(seat.Drawn) <= h.round {
+			return next
+		}
+	}
+	return -1
+}
@@ -0,0 +1,154 @@
+package draw
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/pot"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/openpoker-dev/contrib/table/tabletest"
+	"github.com/stretchr/testify/assert"
+)
+
+// inOrder 按弃牌堆原来的顺序组成新的牌堆，便于测试
+func inOrder(cards []card.Card) card.Deck {
+	deck, _ := card.NewPresetDeck(cards...)
+	return deck
+}
+
+func TestReshuffleMuck(t *testing.T) {
+	// 从座位 1 开始轮流发五张，剩下一张烧牌和最后一张牌
+	deck := tabletest.PresetDeck(t, "9h Kh Ah 8d Qd Ad 6c Jc Ac 4s Ts As 2h 9s Kd 5d 3h")
+	h, err := NewHand(Config{Game: DeuceToSevenSingleDraw, SmallBlind: 5, BigBlind: 10, Reshuffle: inOrder}, []table.Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+		{Seat: 3, Stack: 100},
+	}, 3, deck)
+	assert.Nil(t, err)
+	assert.Equal(t, cardtest.Cards(t, "9h 8d 6c 4s 2h"), h.Seats()[0].Cards)
+
+	tabletest.Apply(t, h,
+		table.Action{Seat: 3, Type: table.ActionFold},
+		table.Action{Seat: 1, Type: table.ActionCall},
+		table.Action{Seat: 2, Type: table.ActionCheck},
+	)
+	assert.True(t, h.Drawing())
+	assert.Nil(t, h.LegalActions())
+	assert.True(t, errors.Is(h.Apply(table.Action{Seat: 1, Type: table.ActionCheck}), table.ErrNotYourTurn))
+	assert.Equal(t, cardtest.Cards(t, "Ah Ad Ac As Kd 5d"), h.Muck())
+
+	// 牌堆只剩最后一张，和弃牌一起重洗
+	seat, _ := h.ToAct()
+	assert.Equal(t, 1, seat)
+	_, err = h.Draw(1, card.NewCard("Kh"))
+	assert.True(t, errors.Is(err, ErrBadDiscard))
+	_, err = h.Draw(2)
+	assert.True(t, errors.Is(err, table.ErrNotYourTurn))
+
+	drawn, err := h.Draw(1, card.NewCard("9h"), card.NewCard("8d"))
+	assert.Nil(t, err)
+	assert.Equal(t, cardtest.Cards(t, "Ah Ad"), drawn)
+	assert.Equal(t, 1, h.Reshuffles())
+	assert.Equal(t, cardtest.Cards(t, "9h 8d"), h.Muck())
+
+	// 座位 2 换掉的牌不会被重洗后发回给自己
+	drawn, err = h.Draw(2, cardtest.Cards(t, "Kh Qd Jc Ts 9s")...)
+	assert.Nil(t, err)
+	assert.Equal(t, cardtest.Cards(t, "Ac As Kd 5d 9h"), drawn)
+	assert.Equal(t, 2, h.Reshuffles())
+	assert.Equal(t, cardtest.Cards(t, "Kh Qd Jc Ts 9s"), h.Muck())
+	assert.Equal(t, []int{5}, h.Seats()[1].Drawn)
+
+	// 换牌后庄家左手边先行动
+	assert.False(t, h.Drawing())
+	assert.Equal(t, 1, h.Round())
+	seat, _ = h.ToAct()
+	assert.Equal(t, 1, seat)
+	tabletest.Apply(t, h,
+		table.Action{Seat: 1, Type: table.ActionCheck},
+		table.Action{Seat: 2, Type: table.ActionCheck},
+	)
+
+	// 2-7 低牌，都是一对 A，座位 1 的踢脚更小
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Equal(t, evaluator.RankOnePair, result.Showdown[1].Rank)
+	assert.Equal(t, map[int]int64{1: 20}, result.Payouts)
+}
+
+func TestFiveCardDraw(t *testing.T) {
+	deck := tabletest.PresetDeck(t, "Ah 2c Ad 3c Kh 7d Ks 8s 4h 9h Qc 5c 5d")
+	h, err := NewHand(Config{Game: FiveCardDraw, SmallBlind: 5, BigBlind: 10}, []table.Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+	}, 2, deck)
+	assert.Nil(t, err)
+
+	// 单挑时庄家下小盲，换牌前先行动
+	seat, _ := h.ToAct()
+	assert.Equal(t, 2, seat)
+	_, err = h.Draw(2)
+	assert.True(t, errors.Is(err, ErrNotDrawing))
+	tabletest.Apply(t, h,
+		table.Action{Seat: 2, Type: table.ActionRaise, Amount: 30},
+		table.Action{Seat: 1, Type: table.ActionCall},
+	)
+
+	drawn, err := h.Draw(1, card.NewCard("4h"))
+	assert.Nil(t, err)
+	assert.Equal(t, cardtest.Cards(t, "5c"), drawn)
+	drawn, err = h.Draw(2)
+	assert.Nil(t, err)
+	assert.Empty(t, drawn)
+
+	tabletest.Apply(t, h,
+		table.Action{Seat: 1, Type: table.ActionBet, Amount: 70},
+		table.Action{Seat: 2, Type: table.ActionFold},
+	)
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Empty(t, result.Showdown)
+	assert.Equal(t, pot.Uncalled{Seat: 1, Amount: 70}, result.Uncalled)
+	assert.Equal(t, int64(130), h.Seats()[0].Stack)
+}
+
+func TestTripleDrawLimits(t *testing.T) {
+	deck := card.NewFiftyTwoCardsDeck()
+	deck.Shuffle()
+	limit := table.FixedLimit{SmallBet: 10, BigBet: 20}
+	h, err := NewHand(Config{Game: DeuceToSevenTripleDraw, SmallBlind: 5, BigBlind: 10, Structure: limit}, []table.Player{
+		{Seat: 1, Stack: 1000},
+		{Seat: 2, Stack: 1000},
+	}, 1, deck)
+	assert.Nil(t, err)
+
+	bets := make(map[int]int64)
+	for !h.Done() {
+		seat, _ := h.ToAct()
+		if h.Drawing() {
+			held := h.Seats()[seat-1].Cards
+			_, err := h.Draw(seat, held[:2]...)
+			assert.Nil(t, err)
+			continue
+		}
+		legal := h.LegalActions()
+		if legal[len(legal)-1].Type == table.ActionBet {
+			bets[h.Round()] = legal[len(legal)-1].Min
+		}
+		action := table.Action{Seat: seat, Type: table.ActionCheck}
+		if legal[0].Type == table.ActionFold {
+			action.Type = table.ActionCall
+		}
+		assert.Nil(t, h.Apply(action))
+	}
+
+	// 第二次换牌后按大注下注
+	assert.Equal(t, map[int]int64{1: 10, 2: 20, 3: 20}, bets)
+	assert.Equal(t, 3, h.Round())
+	assert.Equal(t, []int{2, 2, 2}, h.Seats()[0].Drawn)
+	result, _ := h.Result()
+	assert.Len(t, result.Showdown, 2)
+}
@@ -0,0 +1,42 @@
+package draw
+
+import (
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+// History 牌局历史的副本，牌局进行中也可以调用
+func (h *Hand) History() *history.Hand {
+	return h.recorder.History(nil)
+}
+
+// Actions 本局到目前为止的行动和换牌的副本
+func (h *Hand) Actions() []history.Action {
+	return h.recorder.Actions()
+}
+
+// recordStart 记录开始时的配置和玩家，需要在下强制下注之前调用
+func (h *Hand) recordStart() {
+	h.recorder = table.NewRecorder(history.Hand{
+		ID:         h.cfg.ID,
+		Table:      h.cfg.Table,
+		Game:       history.GameDraw,
+		Limit:      table.LimitOf(h.structure),
+		SmallBlind: h.cfg.SmallBlind,
+		BigBlind:   h.cfg.BigBlind,
+		Ante:       h.cfg.Ante,
+		Button:     h.button,
+	}, h.betting.Seats, h.cfg.Clock)
+}
+
+func (h *Hand) recordResult(result *table.Result) {
+	h.recorder.Result(result, nil, func(seat int) []card.Card {
+		for _, s := range h.seats {
+			if s.Seat == seat {
+				return append([]card.Card{}, s.Cards...)
+			}
+		}
+		return nil
+	})
+}
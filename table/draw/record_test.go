@@ -0,0 +1,113 @@
+package draw
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/openpoker-dev/contrib/table/tabletest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	// 单挑时庄家下小盲，先给座位 2 发牌，换牌前烧掉 8h
+	deck := tabletest.PresetDeck(t, "2c Ah 3d Ad 4h Ac 5s As 7c Kd 8h 9h Th")
+	h, err := NewHand(Config{ID: "9", Game: FiveCardDraw, SmallBlind: 5, BigBlind: 10, Clock: func() time.Time { return start }}, []table.Player{
+		{Seat: 1, Name: "alice", Stack: 100},
+		{Seat: 2, Name: "bob", Stack: 100},
+	}, 1, deck)
+	assert.Nil(t, err)
+
+	tabletest.Apply(t, h,
+		table.Action{Seat: 1, Type: table.ActionCall},
+		table.Action{Seat: 2, Type: table.ActionCheck},
+	)
+	_, err = h.Draw(2, cardtest.Cards(t, "7c")...)
+	assert.Nil(t, err)
+	_, err = h.Draw(1)
+	assert.Nil(t, err)
+	tabletest.Apply(t, h,
+		table.Action{Seat: 2, Type: table.ActionCheck},
+		table.Action{Seat: 1, Type: table.ActionCheck},
+	)
+
+	record := h.History()
+	assert.Equal(t, "9", record.ID)
+	assert.Equal(t, history.GameDraw, record.Game)
+	assert.Equal(t, history.LimitNo, record.Limit)
+	assert.Equal(t, cardtest.Cards(t, "Ah Ad Ac As Kd"), record.Players[0].Cards)
+	assert.Equal(t, cardtest.Cards(t, "2c 3d 4h 5s 7c"), record.Players[1].Cards)
+	assert.Equal(t, h.Actions(), record.Actions)
+	assert.Equal(t, []history.Action{
+		{Street: history.StreetPredraw, Seat: 1, Type: history.ActionCall, Amount: 5, At: start},
+		{Street: history.StreetPredraw, Seat: 2, Type: history.ActionCheck, At: start},
+		{Street: history.StreetFirstDraw, Seat: 2, Type: history.ActionDraw, Discarded: cardtest.Cards(t, "7c"), Drawn: cardtest.Cards(t, "9h"), At: start},
+		{Street: history.StreetFirstDraw, Seat: 1, Type: history.ActionDraw, At: start},
+		{Street: history.StreetFirstDraw, Seat: 2, Type: history.ActionCheck, At: start},
+		{Street: history.StreetFirstDraw, Seat: 1, Type: history.ActionCheck, At: start},
+	}, record.Actions)
+
+	assert.Len(t, record.Showdown, 2)
+	assert.Equal(t, cardtest.Cards(t, "2c 3d 4h 5s 9h"), record.Showdown[1].Cards)
+	assert.Equal(t, []history.Pot{
+		{Amount: 20, Eligible: []int{1, 2}, Winners: []history.Winner{{Seat: 1, Amount: 20}}},
+	}, record.Pots)
+	assert.Nil(t, record.Validate())
+
+	data, err := history.Marshal(record)
+	assert.Nil(t, err)
+	decoded, err := history.Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, record, decoded)
+}
+
+// allInShowdown 单挑时两人翻前全下，每次换牌都不换，一直到摊牌
+func allInShowdown(t *testing.T, game Game, deck string) *history.Hand {
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	h, err := NewHand(Config{Game: game, SmallBlind: 5, BigBlind: 10, Clock: func() time.Time { return start }}, []table.Player{
+		{Seat: 1, Name: "alice", Stack: 10},
+		{Seat: 2, Name: "bob", Stack: 10},
+	}, 1, tabletest.PresetDeck(t, deck))
+	assert.Nil(t, err)
+	tabletest.Apply(t, h, table.Action{Seat: 1, Type: table.ActionCall})
+	for i := 0; i < game.Draws; i++ {
+		_, err = h.Draw(2)
+		assert.Nil(t, err)
+		_, err = h.Draw(1)
+		assert.Nil(t, err)
+	}
+	assert.True(t, h.Done())
+
+	record := h.History()
+	assert.Len(t, record.Showdown, 2)
+	assert.Nil(t, record.Validate())
+
+	data, err := history.Marshal(record)
+	assert.Nil(t, err)
+	decoded, err := history.Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, record, decoded)
+	return decoded
+}
+
+func TestDeuceToSevenHistory(t *testing.T) {
+	record := allInShowdown(t, DeuceToSevenSingleDraw, "2c 2d 3d 3c 4h 4s 5s 6h 7c 8d")
+	assert.Equal(t, cardtest.Cards(t, "2c 3d 4h 5s 7c"), record.Showdown[1].Cards)
+	assert.Equal(t, []history.Pot{
+		{Amount: 20, Eligible: []int{1, 2}, Winners: []history.Winner{{Seat: 2, Amount: 20}}},
+	}, record.Pots)
+}
+
+func TestAceToFiveHistory(t *testing.T) {
+	record := allInShowdown(t, AceToFiveTripleDraw, "Kd Ah 2s 2c 3h 3d 4c 4h 6c 5s")
+	assert.Equal(t, cardtest.Cards(t, "Ah 2c 3d 4h 5s"), record.Showdown[0].Cards)
+	// 摊牌的牌型中仍然是 A，不是 card.RankAceAsOne
+	assert.Contains(t, record.Showdown[0].Hand.Cards, card.NewCard("Ah"))
+	assert.Equal(t, []history.Pot{
+		{Amount: 20, Eligible: []int{1, 2}, Winners: []history.Winner{{Seat: 1, Amount: 20}}},
+	}, record.Pots)
+}
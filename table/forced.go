@@ -0,0 +1,148 @@
+package table
+
+import "sort"
+
+type (
+	// ForcedBets 盲注之外的强制下注配置
+	ForcedBets struct {
+		Ante           int64        // 每个玩家的前注
+		BigBlindAnte   int64        // 由大盲一人支付的前注
+		ButtonAnte     int64        // 由庄家一人支付的前注
+		Straddle       StraddleType // 本局的抓位
+		StraddleAmount int64        // 抓位金额，为 0 时为两倍大盲
+	}
+
+	// StraddleType 抓位类型
+	StraddleType int
+
+	// PostType 强制下注类型
+	PostType int
+
+	// Post 一笔强制下注，Dead 的筹码直接进入底池，不计入本轮下注额
+	Post struct {
+		Seat   int
+		Type   PostType
+		Amount int64
+		Dead   bool
+	}
+
+	// Missed 玩家错过的盲注，回到牌局时需要补交：大盲作为活注，小盲作为死注
+	Missed struct {
+		Small bool
+		Big   bool
+	}
+
+	// BlindTracker 跨局记录暂离或换座的玩家错过的盲注，按玩家名字记录
+	BlindTracker struct {
+		missed map[string]Missed
+	}
+
+	// Seated 坐在牌桌上的玩家，包括暂离的玩家
+	Seated struct {
+		Player
+		SittingOut bool
+	}
+)
+
+const (
+	StraddleNone        StraddleType = iota // 没有抓位
+	StraddleUTG                             // 大盲左手边的玩家抓位
+	StraddleMississippi                     // 庄家抓位
+)
+
+const (
+	PostAnte         PostType = iota // 前注
+	PostBigBlindAnte                 // 大盲前注
+	PostButtonAnte                   // 庄家前注
+	PostSmallBlind                   // 小盲
+	PostBigBlind                     // 大盲
+	PostMissedSmall                  // 补交的小盲，死注
+	PostMissedBig                    // 补交的大盲，活注
+	PostStraddle                     // 抓位
+)
+
+var (
+	postDescriptions = map[PostType]string{
+		PostAnte:         "ante",
+		PostBigBlindAnte: "big blind ante",
+		PostButtonAnte:   "button ante",
+		PostSmallBlind:   "small blind",
+		PostBigBlind:     "big blind",
+		PostMissedSmall:  "dead small blind",
+		PostMissedBig:    "missed big blind",
+		PostStraddle:     "straddle",
+	}
+)
+
+func (pt PostType) String() string {
+	return postDescriptions[pt]
+}
+
+func NewBlindTracker() *BlindTracker {
+	return &BlindTracker{missed: make(map[string]Missed)}
+}
+
+// Record 一局开始前调用，记录盲注位置上暂离的玩家
+// 盲注位置按所有在座的玩家计算，与暂离玩家都参加时相同
+func (bt *BlindTracker) Record(seated []Seated, button int) {
+	if len(seated) < 2 {
+		return
+	}
+	ordered := append([]Seated{}, seated...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Seat < ordered[j].Seat
+	})
+
+	start := 0
+	for i, s := range ordered {
+		if s.Seat > button {
+			start = i
+			break
+		}
+	}
+	sb := ordered[start]
+	bb := ordered[(start+1)%len(ordered)]
+	if len(ordered) == 2 { // 单挑时庄家下小盲
+		sb, bb = bb, sb
+	}
+
+	if sb.SittingOut {
+		missed := bt.missed[sb.Name]
+		missed.Small = true
+		bt.missed[sb.Name] = missed
+	}
+	if bb.SittingOut {
+		missed := bt.missed[bb.Name]
+		missed.Big = true
+		bt.missed[bb.Name] = missed
+	}
+}
+
+// ChangeSeat 换座的玩家需要补交大盲，避免逃过盲注
+func (bt *BlindTracker) ChangeSeat(name string) {
+	missed := bt.missed[name]
+	missed.Big = true
+	bt.missed[name] = missed
+}
+
+// Owes 玩家需要补交的盲注
+func (bt *BlindTracker) Owes(name string) Missed {
+	return bt.missed[name]
+}
+
+// Players 返回本局参加的玩家，填上需要补交的盲注并清除记录
+func (bt *BlindTracker) Players(seated []Seated) []Player {
+	players := make([]Player, 0, len(seated))
+	for _, s := range seated {
+		if s.SittingOut {
+			continue
+		}
+		p := s.Player
+		if missed, exists := bt.missed[p.Name]; exists {
+			p.Missed = missed
+			delete(bt.missed, p.Name)
+		}
+		players = append(players, p)
+	}
+	return players
+}
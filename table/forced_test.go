@@ -0,0 +1,195 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAntes(t *testing.T) {
+	h, err := NewHand(Config{
+		SmallBlind: 5,
+		BigBlind:   10,
+		Forced:     ForcedBets{Ante: 1, ButtonAnte: 3, BigBlindAnte: 10},
+	}, []Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+		{Seat: 3, Stack: 100},
+	}, 1, shuffledDeck())
+	assert.Nil(t, err)
+	assert.Equal(t, []Post{
+		{Seat: 1, Type: PostAnte, Amount: 1, Dead: true},
+		{Seat: 2, Type: PostAnte, Amount: 1, Dead: true},
+		{Seat: 3, Type: PostAnte, Amount: 1, Dead: true},
+		{Seat: 1, Type: PostButtonAnte, Amount: 3, Dead: true},
+		{Seat: 3, Type: PostBigBlindAnte, Amount: 10, Dead: true},
+		{Seat: 2, Type: PostSmallBlind, Amount: 5},
+		{Seat: 3, Type: PostBigBlind, Amount: 10},
+	}, h.Posts())
+	assert.Equal(t, int64(31), h.Pot())
+
+	// 前注不计入本轮下注额
+	call, _ := legalOf(h, ActionCall)
+	assert.Equal(t, int64(10), call.Max)
+	assert.Equal(t, int64(0), h.Seats()[0].Committed)
+}
+
+func TestStraddle(t *testing.T) {
+	players := []Player{
+		{Seat: 1, Stack: 1000},
+		{Seat: 2, Stack: 1000},
+		{Seat: 3, Stack: 1000},
+		{Seat: 4, Stack: 1000},
+	}
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10, Forced: ForcedBets{Straddle: StraddleUTG}}, players, 1, shuffledDeck())
+	assert.Equal(t, Post{Seat: 4, Type: PostStraddle, Amount: 20}, h.Posts()[2])
+	seat, _ := h.ToAct()
+	assert.Equal(t, 1, seat)
+	raise, _ := legalOf(h, ActionRaise)
+	assert.Equal(t, int64(40), raise.Min)
+
+	apply(t, h,
+		Action{Seat: 1, Type: ActionCall},
+		Action{Seat: 2, Type: ActionCall},
+		Action{Seat: 3, Type: ActionCall},
+	)
+	// 抓位的玩家最后行动，可以加注
+	seat, _ = h.ToAct()
+	assert.Equal(t, 4, seat)
+	_, ok := legalOf(h, ActionRaise)
+	assert.True(t, ok)
+
+	h, _ = NewHand(Config{SmallBlind: 5, BigBlind: 10, Forced: ForcedBets{Straddle: StraddleMississippi, StraddleAmount: 30}}, players, 1, shuffledDeck())
+	assert.Equal(t, Post{Seat: 1, Type: PostStraddle, Amount: 30}, h.Posts()[2])
+	seat, _ = h.ToAct()
+	assert.Equal(t, 4, seat)
+}
+
+func TestShortStraddle(t *testing.T) {
+	players := []Player{
+		{Seat: 1, Stack: 1000},
+		{Seat: 2, Stack: 1000},
+		{Seat: 3, Stack: 1000},
+		{Seat: 4, Stack: 15},
+	}
+	h, _ := NewHand(Config{SmallBlind: 5, BigBlind: 10, Forced: ForcedBets{Straddle: StraddleUTG}}, players, 1, shuffledDeck())
+	assert.Equal(t, Post{Seat: 4, Type: PostStraddle, Amount: 15}, h.Posts()[2])
+
+	// 全下的抓位不足一次完整加注，跟注到 15，最小加注仍然按大盲计算
+	seat, _ := h.ToAct()
+	assert.Equal(t, 1, seat)
+	call, _ := legalOf(h, ActionCall)
+	assert.Equal(t, int64(15), call.Max)
+	raise, _ := legalOf(h, ActionRaise)
+	assert.Equal(t, int64(25), raise.Min)
+}
+
+func TestMissedBlinds(t *testing.T) {
+	tracker := NewBlindTracker()
+	seated := []Seated{
+		{Player: Player{Seat: 1, Name: "alice", Stack: 100}},
+		{Player: Player{Seat: 2, Name: "bob", Stack: 100}, SittingOut: true},
+		{Player: Player{Seat: 3, Name: "carol", Stack: 100}, SittingOut: true},
+		{Player: Player{Seat: 4, Name: "dave", Stack: 100}},
+	}
+
+	// 庄家 1，bob 错过小盲，carol 错过大盲
+	tracker.Record(seated, 1)
+	assert.Equal(t, Missed{Small: true}, tracker.Owes("bob"))
+	assert.Equal(t, Missed{Big: true}, tracker.Owes("carol"))
+
+	// 庄家 2，carol 又错过小盲
+	tracker.Record(seated, 2)
+	assert.Equal(t, Missed{Small: true, Big: true}, tracker.Owes("carol"))
+
+	tracker.ChangeSeat("dave")
+	seated[1].SittingOut = false
+	seated[2].SittingOut = false
+	players := tracker.Players(seated)
+	assert.Equal(t, Missed{}, tracker.Owes("carol"))
+
+	// 庄家 3，dave 小盲，alice 大盲，bob 和 carol 补交盲注
+	h, err := NewHand(Config{SmallBlind: 5, BigBlind: 10}, players, 3, shuffledDeck())
+	assert.Nil(t, err)
+	assert.Equal(t, []Post{
+		{Seat: 4, Type: PostSmallBlind, Amount: 5},
+		{Seat: 1, Type: PostBigBlind, Amount: 10},
+		{Seat: 2, Type: PostMissedSmall, Amount: 5, Dead: true},
+		{Seat: 3, Type: PostMissedBig, Amount: 10},
+		{Seat: 3, Type: PostMissedSmall, Amount: 5, Dead: true},
+	}, h.Posts())
+
+	// 补交的大盲是活注，轮到时可以过牌
+	apply(t, h, Action{Seat: 2, Type: ActionCall})
+	seat, _ := h.ToAct()
+	assert.Equal(t, 3, seat)
+	_, ok := legalOf(h, ActionCheck)
+	assert.True(t, ok)
+	assert.Equal(t, "dead small blind", PostMissedSmall.String())
+}
+
+// sumPots 所有底池的筹码
+func sumPots(result *Result) int64 {
+	var total int64
+	for _, award := range result.Pots {
+		total += award.Amount
+	}
+	return total
+}
+
+func TestBigBlindAnteNotReturned(t *testing.T) {
+	h, err := NewHand(Config{
+		SmallBlind: 5,
+		BigBlind:   10,
+		Forced:     ForcedBets{BigBlindAnte: 10},
+	}, []Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+		{Seat: 3, Stack: 100},
+	}, 1, shuffledDeck())
+	assert.Nil(t, err)
+	apply(t, h,
+		Action{Seat: 1, Type: ActionFold},
+		Action{Seat: 2, Type: ActionCall},
+		Action{Seat: 3, Type: ActionCheck},
+	)
+	for i := 0; i < 3; i++ {
+		apply(t, h, Action{Seat: 2, Type: ActionCheck}, Action{Seat: 3, Type: ActionCheck})
+	}
+
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Zero(t, result.Uncalled.Amount)
+	assert.Equal(t, int64(30), sumPots(result))
+	var paid int64
+	for _, amount := range result.Payouts {
+		paid += amount
+	}
+	assert.Equal(t, int64(30), paid)
+}
+
+func TestButtonAnteNotReturned(t *testing.T) {
+	h, err := NewHand(Config{
+		SmallBlind: 5,
+		BigBlind:   10,
+		Forced:     ForcedBets{ButtonAnte: 10},
+	}, []Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+		{Seat: 3, Stack: 100},
+	}, 1, shuffledDeck())
+	assert.Nil(t, err)
+	apply(t, h,
+		Action{Seat: 1, Type: ActionCall},
+		Action{Seat: 2, Type: ActionFold},
+		Action{Seat: 3, Type: ActionCheck},
+	)
+	for i := 0; i < 3; i++ {
+		apply(t, h, Action{Seat: 3, Type: ActionCheck}, Action{Seat: 1, Type: ActionCheck})
+	}
+
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Zero(t, result.Uncalled.Amount)
+	assert.Equal(t, int64(35), sumPots(result))
+}
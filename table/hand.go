@@ -0,0 +1,326 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/pot"
+)
+
+type (
+	// Hand 一局德州扑克，由 Apply 驱动的确定性状态机
+	Hand struct {
+		cfg       Config
+		em        evaluator.EvaluatorManager
+		structure BettingStructure
+		seats     []*SeatState // 按座位号排序
+		betting   *Round
+		posts     []Post
+		recorder  *Recorder
+		button    int // 庄家的座位号
+		dealer    *card.Dealer
+		street    Street
+		board     []card.Card
+		toAct     int     // 当前行动的座位下标，-1 表示没有
+		result    *Result // 牌局结束后的结果
+	}
+)
+
+// NewHand 开始一局牌：下盲注并发手牌，deck 需要已经洗好
+// button 为庄家的座位号，可以是空座位
+func NewHand(cfg Config, players []Player, button int, deck card.Deck) (*Hand, error) {
+	if cfg.SmallBlind <= 0 || cfg.BigBlind < cfg.SmallBlind {
+		return nil, errors.New("bad blinds")
+	}
+	if len(players) < 2 {
+		return nil, errors.New("at least two players")
+	}
+
+	h := &Hand{
+		cfg:       cfg,
+		em:        cfg.Evaluator,
+		structure: cfg.Structure,
+		button:    button,
+		toAct:     -1,
+	}
+	if h.em == nil {
+		h.em = evaluator.NewEvaluatorManager()
+	}
+	if h.structure == nil {
+		h.structure = NoLimit{}
+	}
+	procedure := card.HoldemProcedure
+	switch cfg.Variant {
+	case VariantHoldem:
+	case VariantOmaha:
+		procedure = card.OmahaProcedure
+	default:
+		return nil, fmt.Errorf("unknown variant: %d", cfg.Variant)
+	}
+
+	seatNumbers := make([]int, 0, len(players))
+	for _, p := range players {
+		if p.Stack <= 0 {
+			return nil, fmt.Errorf("seat %d has no chips", p.Seat)
+		}
+		h.seats = append(h.seats, &SeatState{Stake: Stake{Player: p}})
+		seatNumbers = append(seatNumbers, p.Seat)
+	}
+	sort.Slice(h.seats, func(i, j int) bool {
+		return h.seats[i].Seat < h.seats[j].Seat
+	})
+	stakes := make([]*Stake, len(h.seats))
+	for i, seat := range h.seats {
+		stakes[i] = &seat.Stake
+	}
+	h.betting = NewRound(stakes)
+
+	dealer, err := card.NewDealer(deck, procedure, seatNumbers, button)
+	if err != nil {
+		return nil, err
+	}
+	h.dealer = dealer
+
+	h.recordStart()
+	last := h.postForcedBets()
+
+	if _, err := h.dealer.Next(); err != nil {
+		return nil, err
+	}
+	for _, seat := range h.seats {
+		for _, dc := range h.dealer.Hand(seat.Seat) {
+			seat.Hole = append(seat.Hole, dc.Card)
+		}
+	}
+	h.recordHoles()
+
+	h.toAct = h.betting.Next(last)
+	if h.toAct < 0 {
+		if err := h.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// Apply 执行当前行动玩家的行动
+func (h *Hand) Apply(a Action) error {
+	if h.result != nil {
+		return ErrHandOver
+	}
+	if h.toAct < 0 || h.seats[h.toAct].Seat != a.Seat {
+		return ErrNotYourTurn
+	}
+
+	legal, ok := h.legal(a.Type)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrIllegalAction, a.Type)
+	}
+	seat := h.seats[h.toAct]
+	before := seat.Committed
+	to, err := h.betting.Act(h.toAct, a, legal)
+	if err != nil {
+		return err
+	}
+	h.recorder.Action(historyStreets[h.street], &seat.Stake, a.Type, seat.Committed-before, to)
+
+	if h.betting.Complete() {
+		return h.advance()
+	}
+	h.toAct = h.betting.Next(h.toAct)
+	return nil
+}
+
+// LegalActions 当前行动玩家可以做的行动
+func (h *Hand) LegalActions() []LegalAction {
+	if h.result != nil || h.toAct < 0 {
+		return nil
+	}
+	actions := make([]LegalAction, 0, 3)
+	for _, at := range []ActionType{ActionFold, ActionCheck, ActionCall, ActionBet, ActionRaise} {
+		if legal, ok := h.legal(at); ok {
+			actions = append(actions, legal)
+		}
+	}
+	return actions
+}
+
+// ToAct 当前行动的座位号
+func (h *Hand) ToAct() (int, bool) {
+	if h.result != nil || h.toAct < 0 {
+		return 0, false
+	}
+	return h.seats[h.toAct].Seat, true
+}
+
+func (h *Hand) Street() Street {
+	return h.street
+}
+
+func (h *Hand) Button() int {
+	return h.button
+}
+
+func (h *Hand) Board() []card.Card {
+	return append([]card.Card{}, h.board...)
+}
+
+// Seats 所有座位状态的副本
+func (h *Hand) Seats() []SeatState {
+	states := make([]SeatState, 0, len(h.seats))
+	for _, seat := range h.seats {
+		state := *seat
+		state.Hole = append([]card.Card{}, seat.Hole...)
+		states = append(states, state)
+	}
+	return states
+}
+
+// Pot 底池中的筹码，包括本轮的下注
+func (h *Hand) Pot() int64 {
+	return h.betting.Pot()
+}
+
+// Posts 本局的强制下注
+func (h *Hand) Posts() []Post {
+	return append([]Post{}, h.posts...)
+}
+
+// Result 牌局结束后返回结果
+func (h *Hand) Result() (*Result, bool) {
+	return h.result, h.result != nil
+}
+
+func (h *Hand) legal(at ActionType) (LegalAction, bool) {
+	return h.betting.Legal(h.toAct, at, h.structure, h.betting.State(h.toAct, h.street, h.cfg.BigBlind))
+}
+
+// postForcedBets 下前注、盲注、补交的盲注和抓位，返回最后一个活注的座位下标
+func (h *Hand) postForcedBets() int {
+	forced := h.cfg.Forced
+	button := h.betting.Button(h.button)
+	buttonSeated := h.seats[button].Seat == h.button
+	sb, bb := h.betting.Blinds(h.button)
+
+	if forced.Ante > 0 {
+		for i := range h.seats {
+			h.post(i, PostAnte, forced.Ante)
+		}
+	}
+	if forced.ButtonAnte > 0 && buttonSeated {
+		h.post(button, PostButtonAnte, forced.ButtonAnte)
+	}
+	if forced.BigBlindAnte > 0 {
+		h.post(bb, PostBigBlindAnte, forced.BigBlindAnte)
+	}
+	h.post(sb, PostSmallBlind, h.cfg.SmallBlind)
+	h.post(bb, PostBigBlind, h.cfg.BigBlind)
+	h.betting.CurrentBet = h.cfg.BigBlind
+	h.betting.LastRaise = h.cfg.BigBlind
+	h.betting.Bets = 1
+
+	for i, seat := range h.seats {
+		if i == sb || i == bb {
+			continue
+		}
+		if seat.Missed.Big {
+			h.post(i, PostMissedBig, h.cfg.BigBlind)
+		}
+		if seat.Missed.Small {
+			h.post(i, PostMissedSmall, h.cfg.SmallBlind)
+		}
+	}
+
+	last := bb
+	straddler := -1
+	switch forced.Straddle {
+	case StraddleUTG:
+		straddler = (bb + 1) % len(h.seats)
+	case StraddleMississippi:
+		if buttonSeated {
+			straddler = button
+		}
+	}
+	if straddler >= 0 && straddler != sb && straddler != bb {
+		amount := forced.StraddleAmount
+		if amount == 0 {
+			amount = 2 * h.cfg.BigBlind
+		}
+		h.post(straddler, PostStraddle, amount)
+		// 抓位相当于加倍的大盲，最小加注到抓位的两倍
+		// 筹码不足时按实际下的抓位计算，不足一次完整加注时只提高跟注额
+		if posted := h.seats[straddler].Committed; posted > h.betting.CurrentBet {
+			if posted-h.betting.CurrentBet >= h.betting.LastRaise {
+				h.betting.LastRaise = posted
+				h.betting.Bets = 2
+			}
+			h.betting.CurrentBet = posted
+		}
+		if forced.Straddle == StraddleUTG {
+			last = straddler
+		}
+	}
+	return last
+}
+
+// post 强制下注，筹码不足时全下
+func (h *Hand) post(index int, pt PostType, amount int64) {
+	if p, ok := h.betting.Post(index, pt, amount); ok {
+		h.posts = append(h.posts, p)
+		h.recorder.Post(p)
+	}
+}
+
+// advance 结束本轮下注，发下一街的牌，没有人能行动时直接发完公共牌
+func (h *Hand) advance() error {
+	for {
+		if h.betting.Remaining() <= 1 {
+			h.finish()
+			return nil
+		}
+		h.betting.Reset(int(h.street)+1, h.cfg.BigBlind)
+
+		if h.street == StreetRiver {
+			h.finish()
+			return nil
+		}
+		if _, err := h.dealer.Next(); err != nil {
+			return err
+		}
+		h.street++
+		h.board = h.dealer.Board()
+
+		h.toAct = h.betting.Next(h.betting.Button(h.button))
+		if h.toAct >= 0 && h.betting.OthersCanAct(h.toAct) {
+			return nil
+		}
+	}
+}
+
+// finish 分配底池
+func (h *Hand) finish() {
+	h.street = StreetShowdown
+	h.toAct = -1
+	h.result = h.betting.Settle(pot.Distributor{Button: h.button}, func(i int) evaluator.PokerHand {
+		return h.evaluate(h.seats[i].Hole)
+	})
+	h.recordResult(h.result)
+}
+
+// evaluate 手牌和公共牌组成的最大牌型，奥马哈必须使用两张手牌和三张公共牌
+func (h *Hand) evaluate(hole []card.Card) evaluator.PokerHand {
+	if h.cfg.Variant != VariantOmaha {
+		return h.em.Evaluate(append(append([]card.Card{}, hole...), h.board...)...)
+	}
+	return evaluator.EvaluateOmaha(h.em, hole, h.board)
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,172 @@
+package table
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/pot"
+	"github.com/stretchr/testify/assert"
+)
+
+func presetDeck(t *testing.T, s string) card.Deck {
+	cards, err := card.ParseCards(s)
+	assert.Nil(t, err)
+	deck, err := card.NewPresetDeck(cards...)
+	assert.Nil(t, err)
+	return deck
+}
+
+func apply(t *testing.T, h *Hand, actions ...Action) {
+	for _, a := range actions {
+		assert.Nil(t, h.Apply(a), "%+v", a)
+	}
+}
+
+func TestHeadsUpShowdown(t *testing.T) {
+	// 座位 1 是庄家（小盲），先给座位 2 发牌
+	deck := presetDeck(t, "7c As 2d Ad 3h Kh Qs 2s 4h Jd 5c 9s")
+	h, err := NewHand(Config{SmallBlind: 5, BigBlind: 10}, []Player{
+		{Seat: 1, Name: "alice", Stack: 1000},
+		{Seat: 2, Name: "bob", Stack: 1000},
+	}, 1, deck)
+	assert.Nil(t, err)
+	assert.Equal(t, StreetPreflop, h.Street())
+	assert.Equal(t, int64(15), h.Pot())
+
+	seat, ok := h.ToAct()
+	assert.True(t, ok)
+	assert.Equal(t, 1, seat)
+	assert.Equal(t, []LegalAction{
+		{Type: ActionFold},
+		{Type: ActionCall, Min: 10, Max: 10},
+		{Type: ActionRaise, Min: 20, Max: 1000},
+	}, h.LegalActions())
+
+	assert.True(t, errors.Is(h.Apply(Action{Seat: 2, Type: ActionCheck}), ErrNotYourTurn))
+	assert.True(t, errors.Is(h.Apply(Action{Seat: 1, Type: ActionCheck}), ErrIllegalAction))
+	assert.True(t, errors.Is(h.Apply(Action{Seat: 1, Type: ActionRaise, Amount: 15}), ErrIllegalAction))
+
+	apply(t, h,
+		Action{Seat: 1, Type: ActionCall},
+		Action{Seat: 2, Type: ActionCheck},
+	)
+	assert.Equal(t, StreetFlop, h.Street())
+	assert.Equal(t, []card.Card{card.NewCard("Kh"), card.NewCard("Qs"), card.NewCard("2s")}, h.Board())
+
+	// 翻牌后大盲先行动
+	seat, _ = h.ToAct()
+	assert.Equal(t, 2, seat)
+	apply(t, h,
+		Action{Seat: 2, Type: ActionBet, Amount: 20},
+		Action{Seat: 1, Type: ActionRaise, Amount: 60},
+		Action{Seat: 2, Type: ActionCall},
+		Action{Seat: 2, Type: ActionCheck},
+		Action{Seat: 1, Type: ActionCheck},
+		Action{Seat: 2, Type: ActionCheck},
+		Action{Seat: 1, Type: ActionCheck},
+	)
+
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Equal(t, StreetShowdown, h.Street())
+	assert.Len(t, h.Board(), 5)
+	assert.Equal(t, []pot.Award{{
+		Pot:     pot.Pot{Amount: 140, Eligible: []int{1, 2}},
+		Winners: []int{1},
+		Shares:  map[int]int64{1: 140},
+	}}, result.Pots)
+	assert.Equal(t, map[int]int64{1: 140}, result.Payouts)
+	assert.Equal(t, evaluator.RankOnePair, result.Showdown[1].Rank)
+	assert.Equal(t, int64(1070), h.Seats()[0].Stack)
+	assert.Equal(t, int64(930), h.Seats()[1].Stack)
+	assert.Equal(t, ErrHandOver, h.Apply(Action{Seat: 1, Type: ActionCheck}))
+}
+
+func TestFoldToBigBlind(t *testing.T) {
+	deck := card.NewFiftyTwoCardsDeck()
+	deck.Shuffle()
+	h, err := NewHand(Config{SmallBlind: 1, BigBlind: 2}, []Player{
+		{Seat: 3, Stack: 100},
+		{Seat: 5, Stack: 100},
+		{Seat: 8, Stack: 100},
+	}, 8, deck)
+	assert.Nil(t, err)
+
+	seat, _ := h.ToAct()
+	assert.Equal(t, 8, seat)
+	apply(t, h,
+		Action{Seat: 8, Type: ActionFold},
+		Action{Seat: 3, Type: ActionFold},
+	)
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Empty(t, result.Showdown)
+	assert.Equal(t, map[int]int64{5: 3}, result.Payouts)
+	assert.Equal(t, pot.Uncalled{Seat: 5, Amount: 1}, result.Uncalled)
+	assert.Equal(t, int64(101), h.Seats()[1].Stack)
+}
+
+func TestAllInSidePots(t *testing.T) {
+	// 庄家 3，发牌顺序 1 2 3
+	deck := presetDeck(t, "As Ks 2c Ah Kh 3d 4c Qs Qd 7h 5s 8d 9c Tc")
+	h, err := NewHand(Config{SmallBlind: 5, BigBlind: 10}, []Player{
+		{Seat: 1, Stack: 50},
+		{Seat: 2, Stack: 200},
+		{Seat: 3, Stack: 300},
+	}, 3, deck)
+	assert.Nil(t, err)
+
+	apply(t, h,
+		Action{Seat: 3, Type: ActionRaise, Amount: 300},
+		Action{Seat: 1, Type: ActionCall},
+		Action{Seat: 2, Type: ActionCall},
+	)
+
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Len(t, h.Board(), 5)
+	// 座位 1 赢得主池，座位 2 赢得边池，座位 3 多出的 100 退回
+	assert.Len(t, result.Pots, 2)
+	assert.Equal(t, pot.Pot{Amount: 150, Eligible: []int{1, 2, 3}}, result.Pots[0].Pot)
+	assert.Equal(t, []int{1}, result.Pots[0].Winners)
+	assert.Equal(t, pot.Pot{Amount: 300, Eligible: []int{2, 3}}, result.Pots[1].Pot)
+	assert.Equal(t, []int{2}, result.Pots[1].Winners)
+	assert.Equal(t, pot.Uncalled{Seat: 3, Amount: 100}, result.Uncalled)
+	assert.Equal(t, map[int]int64{1: 150, 2: 300, 3: 100}, result.Payouts)
+}
+
+func TestOddChip(t *testing.T) {
+	// 公共牌形成的顺子，平分底池
+	deck := presetDeck(t, "2c 3c 4c 2d 3d 4d Jh 5h 6s 7h Qd 8s Kd 9d")
+	h, _ := NewHand(Config{SmallBlind: 1, BigBlind: 2}, []Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+		{Seat: 3, Stack: 100},
+	}, 1, deck)
+	apply(t, h,
+		Action{Seat: 1, Type: ActionCall},
+		Action{Seat: 2, Type: ActionFold},
+		Action{Seat: 3, Type: ActionCheck},
+		Action{Seat: 3, Type: ActionCheck},
+		Action{Seat: 1, Type: ActionCheck},
+		Action{Seat: 3, Type: ActionCheck},
+		Action{Seat: 1, Type: ActionCheck},
+		Action{Seat: 3, Type: ActionCheck},
+		Action{Seat: 1, Type: ActionCheck},
+	)
+	result, _ := h.Result()
+	assert.Equal(t, []int{1, 3}, result.Pots[0].Winners)
+	assert.Equal(t, map[int]int64{3: 3, 1: 2}, result.Payouts)
+}
+
+func TestNewHandErrors(t *testing.T) {
+	deck := card.NewFiftyTwoCardsDeck()
+	_, err := NewHand(Config{SmallBlind: 0, BigBlind: 2}, []Player{{Seat: 1, Stack: 1}, {Seat: 2, Stack: 1}}, 1, deck)
+	assert.NotNil(t, err)
+	_, err = NewHand(Config{SmallBlind: 1, BigBlind: 2}, []Player{{Seat: 1, Stack: 1}}, 1, deck)
+	assert.NotNil(t, err)
+	_, err = NewHand(Config{SmallBlind: 1, BigBlind: 2}, []Player{{Seat: 1, Stack: 1}, {Seat: 2}}, 1, deck)
+	assert.NotNil(t, err)
+}
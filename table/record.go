@@ -0,0 +1,184 @@
+package table
+
+import (
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/history"
+)
+
+type (
+	// Recorder 记录牌局历史，德州扑克、梭哈和换牌扑克共用
+	Recorder struct {
+		hand  *history.Hand
+		clock func() time.Time
+	}
+)
+
+var (
+	historyStreets = map[Street]history.Street{
+		StreetPreflop:  history.StreetPreflop,
+		StreetFlop:     history.StreetFlop,
+		StreetTurn:     history.StreetTurn,
+		StreetRiver:    history.StreetRiver,
+		StreetShowdown: history.StreetShowdown,
+	}
+)
+
+// NewRecorder 开始记录一局牌，record 为牌局的配置，按 seats 记录开始时的玩家，需要在下强制下注之前调用
+// clock 为空时使用 time.Now
+func NewRecorder(record history.Hand, seats []*Stake, clock func() time.Time) *Recorder {
+	r := &Recorder{hand: &record, clock: clock}
+	record.Version = history.SchemaVersion
+	record.StartedAt = r.now()
+	record.Players = nil
+	for _, seat := range seats {
+		record.Players = append(record.Players, history.Player{Seat: seat.Seat, Name: seat.Name, Stack: seat.Stack})
+	}
+	return r
+}
+
+// LimitOf 下注结构对应的 history.Limit
+func LimitOf(bs BettingStructure) history.Limit {
+	switch bs.(type) {
+	case PotLimit:
+		return history.LimitPot
+	case FixedLimit:
+		return history.LimitFixed
+	}
+	return history.LimitNo
+}
+
+// History 牌局历史的副本，牌局进行中也可以调用
+func (r *Recorder) History(board []card.Card) *history.Hand {
+	record := r.hand.Clone()
+	record.Board = append([]card.Card(nil), board...)
+	return record
+}
+
+// Actions 到目前为止的行动的副本
+func (r *Recorder) Actions() []history.Action {
+	return append([]history.Action{}, r.hand.Actions...)
+}
+
+func (r *Recorder) Post(p Post) {
+	r.hand.Posts = append(r.hand.Posts, history.Post{
+		Seat:   p.Seat,
+		Type:   history.PostType(p.Type.String()),
+		Amount: p.Amount,
+		Dead:   p.Dead,
+	})
+}
+
+// Deal 记录座位目前发到的所有牌
+func (r *Recorder) Deal(seat int, cards []card.Card) {
+	for i := range r.hand.Players {
+		if r.hand.Players[i].Seat == seat {
+			r.hand.Players[i].Cards = append([]card.Card{}, cards...)
+		}
+	}
+}
+
+// Action amount 为本次投入的筹码，to 为下注和加注后的总额
+func (r *Recorder) Action(street history.Street, seat *Stake, at ActionType, amount, to int64) {
+	r.hand.Actions = append(r.hand.Actions, history.Action{
+		Street: street,
+		Seat:   seat.Seat,
+		Type:   history.ActionType(at.String()),
+		Amount: amount,
+		To:     to,
+		AllIn:  seat.AllIn && amount > 0,
+		At:     r.now(),
+	})
+}
+
+// Draw 换牌，discarded 为换掉的牌，drawn 为补发的牌
+func (r *Recorder) Draw(street history.Street, seat int, discarded, drawn []card.Card) {
+	r.hand.Actions = append(r.hand.Actions, history.Action{
+		Street:    street,
+		Seat:      seat,
+		Type:      history.ActionDraw,
+		Discarded: append([]card.Card(nil), discarded...),
+		Drawn:     append([]card.Card(nil), drawn...),
+		At:        r.now(),
+	})
+}
+
+// Result 记录摊牌和底池的分配，cards 为摊牌座位亮出的牌
+func (r *Recorder) Result(result *Result, board []card.Card, cards func(seat int) []card.Card) {
+	r.hand.Board = append([]card.Card(nil), board...)
+	for _, p := range r.hand.Players {
+		hand, exists := result.Showdown[p.Seat]
+		if !exists {
+			continue
+		}
+		r.hand.Showdown = append(r.hand.Showdown, history.Showdown{
+			Seat:  p.Seat,
+			Cards: cards(p.Seat),
+			Hand:  hand,
+		})
+	}
+	if result.Uncalled.Amount > 0 {
+		r.hand.Uncalled = &history.Uncalled{Seat: result.Uncalled.Seat, Amount: result.Uncalled.Amount}
+	}
+	for _, award := range result.Pots {
+		pot := history.Pot{Amount: award.Amount, Eligible: append([]int{}, award.Eligible...)}
+		for _, winner := range award.Winners {
+			pot.Winners = append(pot.Winners, history.Winner{Seat: winner, Amount: award.Shares[winner]})
+		}
+		r.hand.Pots = append(r.hand.Pots, pot)
+	}
+}
+
+func (r *Recorder) now() time.Time {
+	if r.clock != nil {
+		return r.clock()
+	}
+	return time.Now()
+}
+
+// History 牌局历史的副本，牌局进行中也可以调用
+func (h *Hand) History() *history.Hand {
+	return h.recorder.History(h.Board())
+}
+
+// Actions 本局到目前为止的行动的副本
+func (h *Hand) Actions() []history.Action {
+	return h.recorder.Actions()
+}
+
+// recordStart 记录开始时的配置和玩家，需要在下强制下注之前调用
+func (h *Hand) recordStart() {
+	record := history.Hand{
+		ID:         h.cfg.ID,
+		Table:      h.cfg.Table,
+		Game:       history.GameHoldem,
+		Limit:      LimitOf(h.structure),
+		SmallBlind: h.cfg.SmallBlind,
+		BigBlind:   h.cfg.BigBlind,
+		Ante:       h.cfg.Forced.Ante,
+		Button:     h.button,
+	}
+	if h.cfg.Variant == VariantOmaha {
+		record.Game = history.GameOmaha
+	}
+	h.recorder = NewRecorder(record, h.betting.Seats, h.cfg.Clock)
+}
+
+// recordHoles 记录发到的手牌
+func (h *Hand) recordHoles() {
+	for _, seat := range h.seats {
+		h.recorder.Deal(seat.Seat, seat.Hole)
+	}
+}
+
+func (h *Hand) recordResult(result *Result) {
+	h.recorder.Result(result, h.Board(), func(seat int) []card.Card {
+		for _, s := range h.seats {
+			if s.Seat == seat {
+				return append([]card.Card{}, s.Hole...)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,81 @@
+package table
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	ticks := 0
+	clock := func() time.Time {
+		ticks++
+		return start.Add(time.Duration(ticks-1) * time.Second)
+	}
+
+	deck := presetDeck(t, "7c As 2d Ad 3h Kh Qs 2s 4h Jd 5c 9s")
+	h, err := NewHand(Config{ID: "42", Table: "Alpha", SmallBlind: 5, BigBlind: 10, Clock: clock}, []Player{
+		{Seat: 1, Name: "alice", Stack: 1000},
+		{Seat: 2, Name: "bob", Stack: 1000},
+	}, 1, deck)
+	assert.Nil(t, err)
+
+	record := h.History()
+	assert.Equal(t, history.SchemaVersion, record.Version)
+	assert.Equal(t, "42", record.ID)
+	assert.Equal(t, "Alpha", record.Table)
+	assert.Equal(t, history.GameHoldem, record.Game)
+	assert.Equal(t, history.LimitNo, record.Limit)
+	assert.Equal(t, start, record.StartedAt)
+	assert.Equal(t, []history.Player{
+		{Seat: 1, Name: "alice", Stack: 1000, Cards: []card.Card{card.NewCard("As"), card.NewCard("Ad")}},
+		{Seat: 2, Name: "bob", Stack: 1000, Cards: []card.Card{card.NewCard("7c"), card.NewCard("2d")}},
+	}, record.Players)
+	assert.Equal(t, []history.Post{
+		{Seat: 1, Type: history.PostSmallBlind, Amount: 5},
+		{Seat: 2, Type: history.PostBigBlind, Amount: 10},
+	}, record.Posts)
+
+	apply(t, h,
+		Action{Seat: 1, Type: ActionRaise, Amount: 30},
+		Action{Seat: 2, Type: ActionCall},
+		Action{Seat: 2, Type: ActionCheck},
+		Action{Seat: 1, Type: ActionBet, Amount: 40},
+		Action{Seat: 2, Type: ActionCall},
+		Action{Seat: 2, Type: ActionBet, Amount: 930},
+		Action{Seat: 1, Type: ActionCall},
+	)
+
+	record = h.History()
+	assert.Equal(t, record.Actions, h.Actions())
+	assert.Equal(t, []history.Action{
+		{Street: history.StreetPreflop, Seat: 1, Type: history.ActionRaise, Amount: 25, To: 30, At: start.Add(time.Second)},
+		{Street: history.StreetPreflop, Seat: 2, Type: history.ActionCall, Amount: 20, At: start.Add(2 * time.Second)},
+		{Street: history.StreetFlop, Seat: 2, Type: history.ActionCheck, At: start.Add(3 * time.Second)},
+		{Street: history.StreetFlop, Seat: 1, Type: history.ActionBet, Amount: 40, To: 40, At: start.Add(4 * time.Second)},
+		{Street: history.StreetFlop, Seat: 2, Type: history.ActionCall, Amount: 40, At: start.Add(5 * time.Second)},
+		{Street: history.StreetTurn, Seat: 2, Type: history.ActionBet, Amount: 930, To: 930, AllIn: true, At: start.Add(6 * time.Second)},
+		{Street: history.StreetTurn, Seat: 1, Type: history.ActionCall, Amount: 930, AllIn: true, At: start.Add(7 * time.Second)},
+	}, record.Actions)
+	assert.Equal(t, h.Board(), record.Board)
+	assert.Len(t, record.Board, 5)
+
+	assert.Len(t, record.Showdown, 2)
+	assert.Equal(t, []card.Card{card.NewCard("As"), card.NewCard("Ad")}, record.Showdown[0].Cards)
+	assert.Equal(t, evaluator.RankOnePair, record.Showdown[0].Hand.Rank)
+	assert.Equal(t, []history.Pot{
+		{Amount: 2000, Eligible: []int{1, 2}, Winners: []history.Winner{{Seat: 1, Amount: 2000}}},
+	}, record.Pots)
+	assert.Nil(t, record.Validate())
+
+	data, err := history.Marshal(record)
+	assert.Nil(t, err)
+	decoded, err := history.Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, record, decoded)
+}
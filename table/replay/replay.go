@@ -0,0 +1,382 @@
+// Package replay 按牌局历史重放牌局，核对记录的结果和规则算出的结果是否一致
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Replayer 逐个行动重放一局德州扑克或奥马哈
+	Replayer struct {
+		record *history.Hand
+		hand   *table.Hand
+		next   int // 下一个要重放的行动
+	}
+
+	// Divergence 记录和重放结果不一致的地方
+	Divergence struct {
+		What       string // eg. "action 3" "pot 0 winners" "showdown seat 2"
+		Recorded   string
+		Recomputed string
+	}
+
+	// Report 重放的结果
+	Report struct {
+		Replayed    *history.Hand // 重放后的牌局历史
+		Result      *table.Result
+		Divergences []Divergence
+	}
+)
+
+var (
+	ErrUnsupportedGame = errors.New("unsupported game")
+	ErrReplay          = errors.New("replay failed")
+)
+
+// New 用记录的手牌和公共牌重建牌堆，以记录开始时的筹码和强制下注开始一局
+// em 为空时使用 evaluator.NewEvaluatorManager
+func New(record *history.Hand, em evaluator.EvaluatorManager) (*Replayer, error) {
+	cfg, err := Config(record)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Evaluator = em
+	deck, err := Deck(record)
+	if err != nil {
+		return nil, err
+	}
+
+	missed := make(map[int]table.Missed)
+	for _, p := range record.Posts {
+		m := missed[p.Seat]
+		switch p.Type {
+		case history.PostMissedSmall:
+			m.Small = true
+		case history.PostMissedBig:
+			m.Big = true
+		}
+		missed[p.Seat] = m
+	}
+	players := make([]table.Player, 0, len(record.Players))
+	for _, p := range record.Players {
+		players = append(players, table.Player{Seat: p.Seat, Name: p.Name, Stack: p.Stack, Missed: missed[p.Seat]})
+	}
+
+	hand, err := table.NewHand(cfg, players, record.Button, deck)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReplay, err)
+	}
+	return &Replayer{record: record, hand: hand}, nil
+}
+
+// Config 从牌局历史推算牌局配置，固定限注的小注和大注按一倍和两倍大盲计算
+func Config(record *history.Hand) (table.Config, error) {
+	cfg := table.Config{
+		ID:         record.ID,
+		Table:      record.Table,
+		SmallBlind: record.SmallBlind,
+		BigBlind:   record.BigBlind,
+		Clock:      func() time.Time { return record.StartedAt },
+	}
+	switch record.Game {
+	case history.GameHoldem:
+		cfg.Variant = table.VariantHoldem
+	case history.GameOmaha:
+		cfg.Variant = table.VariantOmaha
+	default:
+		return cfg, fmt.Errorf("%w: %s", ErrUnsupportedGame, record.Game)
+	}
+	switch record.Limit {
+	case history.LimitPot:
+		cfg.Structure = table.PotLimit{}
+	case history.LimitFixed:
+		cfg.Structure = table.FixedLimit{SmallBet: record.BigBlind, BigBet: 2 * record.BigBlind}
+	default:
+		cfg.Structure = table.NoLimit{}
+	}
+
+	// 筹码不足的玩家强制下注时全下，取记录中最大的金额
+	cfg.Forced.Ante = record.Ante
+	for _, p := range record.Posts {
+		switch p.Type {
+		case history.PostAnte:
+			cfg.Forced.Ante = max64(cfg.Forced.Ante, p.Amount)
+		case history.PostBigBlindAnte:
+			cfg.Forced.BigBlindAnte = max64(cfg.Forced.BigBlindAnte, p.Amount)
+		case history.PostButtonAnte:
+			cfg.Forced.ButtonAnte = max64(cfg.Forced.ButtonAnte, p.Amount)
+		case history.PostStraddle:
+			cfg.Forced.Straddle = table.StraddleUTG
+			if p.Seat == record.Button {
+				cfg.Forced.Straddle = table.StraddleMississippi
+			}
+			cfg.Forced.StraddleAmount = max64(cfg.Forced.StraddleAmount, p.Amount)
+		}
+	}
+	return cfg, nil
+}
+
+// Deck 按发牌顺序重建牌堆：从庄家左手边开始轮流发手牌，翻牌、转牌和河牌之前各烧一张
+// 未知的手牌、烧牌和没有发出的公共牌用剩余的牌按标准顺序补齐
+func Deck(record *history.Hand) (card.Deck, error) {
+	holes := 2
+	procedure := card.HoldemProcedure
+	if record.Game == history.GameOmaha {
+		holes = 4
+		procedure = card.OmahaProcedure
+	}
+
+	seats := make([]int, 0, len(record.Players))
+	for _, p := range record.Players {
+		seats = append(seats, p.Seat)
+	}
+	// 借用 Dealer 得到发牌顺序
+	probe, err := card.NewDealer(card.NewFiftyTwoCardsDeck(), procedure, seats, record.Button)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReplay, err)
+	}
+	order := probe.Active()
+
+	var known []*card.Card
+	slots := make([]card.Card, 0, 52)
+	slot := func(c *card.Card) {
+		slots = append(slots, card.Card{})
+		if c != nil {
+			slots[len(slots)-1] = *c
+			known = append(known, c)
+		}
+	}
+	// 没有记录手牌的玩家用摊牌时亮出的牌
+	hands := make(map[int][]card.Card, len(order))
+	for _, p := range record.Players {
+		hands[p.Seat] = p.Cards
+	}
+	for _, s := range record.Showdown {
+		if len(hands[s.Seat]) == 0 {
+			hands[s.Seat] = s.Cards
+		}
+	}
+	for i := 0; i < holes; i++ {
+		for _, seat := range order {
+			if i < len(hands[seat]) {
+				slot(&hands[seat][i])
+			} else {
+				slot(nil)
+			}
+		}
+	}
+	for i, step := range []int{3, 1, 1} {
+		slot(nil)
+		for j := 0; j < step; j++ {
+			index := []int{0, 3, 4}[i] + j
+			if index < len(record.Board) {
+				slot(&record.Board[index])
+			} else {
+				slot(nil)
+			}
+		}
+	}
+
+	used := make(map[card.Card]bool, len(known))
+	for _, c := range known {
+		if used[*c] {
+			return nil, fmt.Errorf("%w: duplicate card %s", ErrReplay, c.ASCII())
+		}
+		used[*c] = true
+	}
+	fresh := card.NewFiftyTwoCardsDeck()
+	var spare []card.Card
+	for {
+		c, ok := fresh.Deal()
+		if !ok {
+			break
+		}
+		if !used[c] {
+			spare = append(spare, c)
+		}
+	}
+	for i := range slots {
+		if (slots[i] == card.Card{}) {
+			slots[i], spare = spare[0], spare[1:]
+		}
+	}
+	deck, err := card.NewPresetDeck(append(slots, spare...)...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReplay, err)
+	}
+	return deck, nil
+}
+
+// Hand 重放中的牌局，用于逐步展示
+func (r *Replayer) Hand() *table.Hand {
+	return r.hand
+}
+
+// Step 重放下一个行动，所有行动都重放之后返回 io.EOF
+// 行动不合法或者不是该座位行动时返回 ErrReplay
+func (r *Replayer) Step() (history.Action, error) {
+	if r.next >= len(r.record.Actions) {
+		return history.Action{}, io.EOF
+	}
+	a := r.record.Actions[r.next]
+	action := table.Action{Seat: a.Seat}
+	switch a.Type {
+	case history.ActionFold:
+		action.Type = table.ActionFold
+	case history.ActionCheck:
+		action.Type = table.ActionCheck
+	case history.ActionCall:
+		action.Type = table.ActionCall
+	case history.ActionBet:
+		action.Type, action.Amount = table.ActionBet, a.To
+	case history.ActionRaise:
+		action.Type, action.Amount = table.ActionRaise, a.To
+	default:
+		return a, fmt.Errorf("%w: action %d: unsupported %s", ErrReplay, r.next, a.Type)
+	}
+	if err := r.hand.Apply(action); err != nil {
+		return a, fmt.Errorf("%w: action %d (seat %d %s): %v", ErrReplay, r.next, a.Seat, a.Type, err)
+	}
+	r.next++
+	return a, nil
+}
+
+// Run 重放剩余的行动并核对结果
+func (r *Replayer) Run() (*Report, error) {
+	for {
+		if _, err := r.Step(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	result, ok := r.hand.Result()
+	if !ok {
+		return nil, fmt.Errorf("%w: hand not finished after %d actions", ErrReplay, len(r.record.Actions))
+	}
+	replayed := r.hand.History()
+	return &Report{
+		Replayed:    replayed,
+		Result:      result,
+		Divergences: compare(r.record, replayed),
+	}, nil
+}
+
+// Verify 重放整局并核对结果
+func Verify(record *history.Hand, em evaluator.EvaluatorManager) (*Report, error) {
+	r, err := New(record, em)
+	if err != nil {
+		return nil, err
+	}
+	return r.Run()
+}
+
+// OK 记录和重放的结果完全一致
+func (r *Report) OK() bool {
+	return len(r.Divergences) == 0
+}
+
+// compare 核对强制下注、行动、公共牌、摊牌、退回的筹码和底池
+// 有抽水时底池的金额不可比，只核对底池总额和赢家
+func compare(recorded, replayed *history.Hand) []Divergence {
+	var divergences []Divergence
+	check := func(what string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			divergences = append(divergences, Divergence{What: what, Recorded: fmt.Sprint(a), Recomputed: fmt.Sprint(b)})
+		}
+	}
+
+	check("posts", recorded.Posts, replayed.Posts)
+	for i, a := range recorded.Actions {
+		b := replayed.Actions[i]
+		check(fmt.Sprintf("action %d", i),
+			[]interface{}{a.Street, a.Seat, a.Type, a.Amount, a.To, a.AllIn},
+			[]interface{}{b.Street, b.Seat, b.Type, b.Amount, b.To, b.AllIn})
+	}
+	check("board", recorded.Board, replayed.Board)
+
+	shown := make(map[int]history.Showdown)
+	for _, s := range replayed.Showdown {
+		shown[s.Seat] = s
+	}
+	for _, s := range recorded.Showdown {
+		check(fmt.Sprintf("showdown seat %d", s.Seat), describe(s.Hand), describe(shown[s.Seat].Hand))
+	}
+	check("showdown seats", seatsOf(recorded.Showdown), seatsOf(replayed.Showdown))
+	check("uncalled", uncalled(recorded.Uncalled), uncalled(replayed.Uncalled))
+
+	check("pots", len(recorded.Pots), len(replayed.Pots))
+	var recordedTotal, replayedTotal int64
+	for i := range recorded.Pots {
+		recordedTotal += recorded.Pots[i].Amount
+		if i >= len(replayed.Pots) {
+			continue
+		}
+		a, b := recorded.Pots[i], replayed.Pots[i]
+		if recorded.Rake == 0 {
+			check(fmt.Sprintf("pot %d amount", i), a.Amount, b.Amount)
+			check(fmt.Sprintf("pot %d winners", i), a.Winners, b.Winners)
+		} else {
+			check(fmt.Sprintf("pot %d winners", i), winnerSeats(a.Winners), winnerSeats(b.Winners))
+		}
+	}
+	for _, pot := range replayed.Pots {
+		replayedTotal += pot.Amount
+	}
+	check("total pot", recordedTotal+recorded.Rake, replayedTotal)
+	return divergences
+}
+
+func describe(hand evaluator.PokerHand) string {
+	if len(hand.Cards) == 0 {
+		return ""
+	}
+	ascii := make([]string, 0, len(hand.Cards))
+	for _, c := range hand.Cards {
+		ascii = append(ascii, c.ASCII())
+	}
+	return hand.Rank.String() + " " + strings.Join(ascii, " ")
+}
+
+// seatsOf 摊牌的座位，亮牌的先后不影响结果
+func seatsOf(showdown []history.Showdown) []int {
+	seats := make([]int, 0, len(showdown))
+	for _, s := range showdown {
+		seats = append(seats, s.Seat)
+	}
+	sort.Ints(seats)
+	return seats
+}
+
+func uncalled(u *history.Uncalled) history.Uncalled {
+	if u == nil {
+		return history.Uncalled{}
+	}
+	return *u
+}
+
+func winnerSeats(winners []history.Winner) []int {
+	seats := make([]int, 0, len(winners))
+	for _, w := range winners {
+		seats = append(seats, w.Seat)
+	}
+	return seats
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,187 @@
+package replay
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/history/pokerstars"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/stretchr/testify/assert"
+)
+
+// play 用随机的合法行动打完一局
+func play(t *testing.T, rng *rand.Rand, cfg table.Config, players []table.Player, button int) *history.Hand {
+	deck := card.NewFiftyTwoCardsDeck()
+	deck.Shuffle()
+	h, err := table.NewHand(cfg, players, button, deck)
+	assert.Nil(t, err)
+	for {
+		seat, ok := h.ToAct()
+		if !ok {
+			break
+		}
+		legal := h.LegalActions()
+		choice := legal[rng.Intn(len(legal))]
+		amount := choice.Min
+		if choice.Max > choice.Min {
+			amount += rng.Int63n(choice.Max - choice.Min + 1)
+		}
+		assert.Nil(t, h.Apply(table.Action{Seat: seat, Type: choice.Type, Amount: amount}))
+	}
+	return h.History()
+}
+
+func TestVerifyEngineHands(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	configs := []table.Config{
+		{Variant: table.VariantHoldem, SmallBlind: 5, BigBlind: 10},
+		{Variant: table.VariantOmaha, SmallBlind: 5, BigBlind: 10, Structure: table.PotLimit{}},
+		{Variant: table.VariantHoldem, SmallBlind: 5, BigBlind: 10, Structure: table.FixedLimit{SmallBet: 10, BigBet: 20}},
+		{Variant: table.VariantHoldem, SmallBlind: 5, BigBlind: 10, Forced: table.ForcedBets{Ante: 1, Straddle: table.StraddleUTG}},
+		{Variant: table.VariantHoldem, SmallBlind: 5, BigBlind: 10, Forced: table.ForcedBets{BigBlindAnte: 10}},
+	}
+	for i := 0; i < 200; i++ {
+		players := []table.Player{
+			{Seat: 1, Name: "alice", Stack: 200 + rng.Int63n(800)},
+			{Seat: 3, Name: "bob", Stack: 200 + rng.Int63n(800)},
+			{Seat: 4, Name: "carol", Stack: 200 + rng.Int63n(800)},
+			{Seat: 6, Name: "dave", Stack: 200 + rng.Int63n(800), Missed: table.Missed{Small: i%7 == 0, Big: i%7 == 0}},
+		}
+		record := play(t, rng, configs[i%len(configs)], players, []int{1, 3, 4, 6}[i%4])
+
+		report, err := Verify(record, nil)
+		assert.Nil(t, err)
+		assert.True(t, report.OK(), "%d: %v", i, report.Divergences)
+	}
+}
+
+func TestStepByStep(t *testing.T) {
+	record := play(t, rand.New(rand.NewSource(1)), table.Config{SmallBlind: 5, BigBlind: 10}, []table.Player{
+		{Seat: 1, Name: "alice", Stack: 1000},
+		{Seat: 2, Name: "bob", Stack: 1000},
+	}, 1)
+
+	r, err := New(record, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, record.Players[0].Cards, r.Hand().Seats()[0].Hole)
+	for _, expected := range record.Actions {
+		seat, ok := r.Hand().ToAct()
+		assert.True(t, ok)
+		assert.Equal(t, expected.Seat, seat)
+		a, err := r.Step()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, a)
+	}
+	_, err = r.Step()
+	assert.Equal(t, io.EOF, err)
+	_, done := r.Hand().Result()
+	assert.True(t, done)
+}
+
+func TestDivergence(t *testing.T) {
+	record := play(t, rand.New(rand.NewSource(3)), table.Config{SmallBlind: 5, BigBlind: 10}, []table.Player{
+		{Seat: 1, Name: "alice", Stack: 1000},
+		{Seat: 2, Name: "bob", Stack: 1000},
+	}, 1)
+	// 两人都过牌到摊牌
+	record.Players[0].Cards = cardtest.Cards(t, "As Ad")
+	record.Players[1].Cards = cardtest.Cards(t, "7c 2d")
+	record.Board = cardtest.Cards(t, "Kh Qs 2s Jd 9s")
+	record.Actions = []history.Action{
+		{Street: history.StreetPreflop, Seat: 1, Type: history.ActionCall, Amount: 5},
+		{Street: history.StreetPreflop, Seat: 2, Type: history.ActionCheck},
+		{Street: history.StreetFlop, Seat: 2, Type: history.ActionCheck},
+		{Street: history.StreetFlop, Seat: 1, Type: history.ActionCheck},
+		{Street: history.StreetTurn, Seat: 2, Type: history.ActionCheck},
+		{Street: history.StreetTurn, Seat: 1, Type: history.ActionCheck},
+		{Street: history.StreetRiver, Seat: 2, Type: history.ActionCheck},
+		{Street: history.StreetRiver, Seat: 1, Type: history.ActionCheck},
+	}
+	record.Uncalled = nil
+	// 记录错把底池判给了 bob
+	record.Showdown = []history.Showdown{
+		{Seat: 1, Cards: cardtest.Cards(t, "As Ad"), Hand: evaluator.Evaluate(cardtest.Cards(t, "As Ad Kh Qs 2s Jd 9s")...)},
+		{Seat: 2, Cards: cardtest.Cards(t, "7c 2d"), Hand: evaluator.Evaluate(cardtest.Cards(t, "7c 7d 2d Kh Qs 2s Jd")...)},
+	}
+	record.Pots = []history.Pot{{Amount: 20, Eligible: []int{1, 2}, Winners: []history.Winner{{Seat: 2, Amount: 20}}}}
+
+	report, err := Verify(record, nil)
+	assert.Nil(t, err)
+	assert.False(t, report.OK())
+	whats := make([]string, 0, len(report.Divergences))
+	for _, d := range report.Divergences {
+		whats = append(whats, d.What)
+	}
+	assert.Equal(t, []string{"showdown seat 2", "pot 0 winners"}, whats)
+	assert.Equal(t, "Two Pair 7c 7d 2d 2s Kh", report.Divergences[0].Recorded)
+	assert.Equal(t, "One Pair 2d 2s Kh Qs Jd", report.Divergences[0].Recomputed)
+	assert.Equal(t, "[{2 20}]", report.Divergences[1].Recorded)
+	assert.Equal(t, "[{1 20}]", report.Divergences[1].Recomputed)
+	assert.Equal(t, int64(20), report.Result.Payouts[1])
+
+	// 轮不到的座位行动
+	record.Actions[1].Seat = 1
+	_, err = Verify(record, nil)
+	assert.True(t, errors.Is(err, ErrReplay))
+
+	// 公共牌和手牌重复
+	record.Board = cardtest.Cards(t, "As Qs 2s Jd 9s")
+	_, err = Verify(record, nil)
+	assert.True(t, errors.Is(err, ErrReplay))
+
+	record.Game = history.GameStud
+	_, err = Verify(record, nil)
+	assert.True(t, errors.Is(err, ErrUnsupportedGame))
+}
+
+func TestVerifyParsedHand(t *testing.T) {
+	// 有抽水的牌局只核对底池总额和赢家，没有亮牌的玩家用剩余的牌补齐
+	text := `PokerStars Hand #1:  Hold'em No Limit ($0.05/$0.10 USD) - 2020/01/14 21:30:15 UTC
+Table 'Procyon' 6-max Seat #2 is the button
+Seat 1: Player One ($10 in chips)
+Seat 2: hero ($12.35 in chips)
+Seat 3: villain ($9.40 in chips)
+villain: posts small blind $0.05
+Player One: posts big blind $0.10
+*** HOLE CARDS ***
+Dealt to hero [Ah Kh]
+hero: raises $0.20 to $0.30
+villain: calls $0.25
+Player One: folds
+*** FLOP *** [Qh Jh 2c]
+villain: checks
+hero: bets $0.45
+villain: raises $0.90 to $1.35
+hero: raises $2.70 to $4.05
+villain: calls $2.70
+*** TURN *** [Qh Jh 2c] [3d]
+villain: checks
+hero: bets $8 and is all-in
+villain: calls $5.05 and is all-in
+Uncalled bet ($2.95) returned to hero
+*** RIVER *** [Qh Jh 2c 3d] [Th]
+*** SHOW DOWN ***
+villain: shows [Qs Qd] (three of a kind, Queens)
+hero: shows [Ah Kh] (a Royal Flush)
+hero collected $18.35 from pot
+*** SUMMARY ***
+Total pot $18.90 | Rake $0.55
+`
+	hands, err := pokerstars.Parse(strings.NewReader(text))
+	assert.Nil(t, err)
+	report, err := Verify(hands[0], nil)
+	assert.Nil(t, err)
+	assert.True(t, report.OK(), "%v", report.Divergences)
+
+	hands[0].Pots[0].Winners[0].Seat = 3
+	report, err = Verify(hands[0], nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []Divergence{{What: "pot 0 winners", Recorded: "[3]", Recomputed: "[2]"}}, report.Divergences)
+}
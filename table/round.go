@@ -0,0 +1,304 @@
+package table
+
+import (
+	"fmt"
+
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/pot"
+)
+
+type (
+	// Stake 座位的筹码和下注状态，各种玩法的座位状态都包含它
+	Stake struct {
+		Player
+		Committed int64 // 本轮已经下注的筹码
+		Total     int64 // 本局总共投入的筹码
+		Folded    bool
+		AllIn     bool
+	}
+
+	// Round 下注轮的状态，德州扑克、梭哈和换牌扑克共用
+	Round struct {
+		Seats      []*Stake // 按座位号排序
+		Ledger     *pot.Ledger
+		Street     int    // 记入 Ledger 的下注轮
+		Acted      []bool // 本轮最近一次完整加注之后是否已经行动
+		CurrentBet int64  // 本轮最高下注额
+		LastRaise  int64  // 本轮最近一次完整加注的幅度
+		Bets       int    // 本轮完整下注和加注的次数
+	}
+)
+
+// NewRound seats 需要按座位号排序
+func NewRound(seats []*Stake) *Round {
+	return &Round{
+		Seats:  seats,
+		Ledger: pot.NewLedger(),
+		Acted:  make([]bool, len(seats)),
+	}
+}
+
+// Reset 开始新的下注轮
+func (r *Round) Reset(street int, lastRaise int64) {
+	for i, seat := range r.Seats {
+		seat.Committed = 0
+		r.Acted[i] = false
+	}
+	r.Street = street
+	r.CurrentBet = 0
+	r.LastRaise = lastRaise
+	r.Bets = 0
+}
+
+// Commit 下注，筹码用完时全下
+func (r *Round) Commit(index int, amount int64) {
+	seat := r.Seats[index]
+	seat.Stack -= amount
+	seat.Committed += amount
+	seat.Total += amount
+	r.Ledger.Contribute(seat.Seat, r.Street, amount)
+	if seat.Stack == 0 {
+		seat.AllIn = true
+	}
+}
+
+// CommitDead 下死钱，计入底池但不算本轮的下注
+func (r *Round) CommitDead(index int, amount int64) {
+	seat := r.Seats[index]
+	seat.Stack -= amount
+	seat.Total += amount
+	r.Ledger.ContributeDead(seat.Seat, amount)
+	if seat.Stack == 0 {
+		seat.AllIn = true
+	}
+}
+
+// Raise 下注或加注到 to，raised 不小于 LastRaise 时算完整加注，重新开放其他玩家的行动
+// 返回是否是完整加注
+func (r *Round) Raise(index int, to, raised int64) bool {
+	r.Commit(index, to-r.Seats[index].Committed)
+	r.CurrentBet = to
+	if raised < r.LastRaise {
+		return false
+	}
+	r.LastRaise = raised
+	r.Bets++
+	for i := range r.Acted {
+		r.Acted[i] = false
+	}
+	return true
+}
+
+// Post 强制下注，筹码不足时全下，前注、庄家前注、大盲前注和补交的小盲是死注
+// 没有筹码时不下注，返回 false
+func (r *Round) Post(index int, pt PostType, amount int64) (Post, bool) {
+	seat := r.Seats[index]
+	amount = min64(amount, seat.Stack)
+	if amount <= 0 {
+		return Post{}, false
+	}
+	dead := pt == PostAnte || pt == PostBigBlindAnte || pt == PostButtonAnte || pt == PostMissedSmall
+	if dead {
+		r.CommitDead(index, amount)
+	} else {
+		r.Commit(index, amount)
+	}
+	return Post{Seat: seat.Seat, Type: pt, Amount: amount, Dead: dead}, true
+}
+
+// Button 座位号为 button 的庄家或庄家右手边第一个有人的座位下标
+func (r *Round) Button(button int) int {
+	index := len(r.Seats) - 1
+	for i, seat := range r.Seats {
+		if seat.Seat <= button {
+			index = i
+		}
+	}
+	return index
+}
+
+// Blinds 小盲和大盲的座位下标，单挑时庄家下小盲
+func (r *Round) Blinds(button int) (sb, bb int) {
+	index := r.Button(button)
+	sb = (index + 1) % len(r.Seats)
+	if len(r.Seats) == 2 && r.Seats[index].Seat == button {
+		sb = index
+	}
+	return sb, (sb + 1) % len(r.Seats)
+}
+
+// Act 执行 index 的弃牌、过牌、跟注、下注或加注，legal 为这种行动的合法范围
+// 返回下注或加注后的总额
+func (r *Round) Act(index int, a Action, legal LegalAction) (int64, error) {
+	var to int64
+	switch a.Type {
+	case ActionFold:
+		r.Fold(index)
+
+	case ActionCall:
+		r.Commit(index, legal.Max-r.Seats[index].Committed)
+
+	case ActionBet, ActionRaise:
+		if a.Amount < legal.Min || a.Amount > legal.Max {
+			return 0, fmt.Errorf("%w: %s %d not in [%d, %d]", ErrIllegalAction, a.Type, a.Amount, legal.Min, legal.Max)
+		}
+		to = a.Amount
+		// 不足最小加注额的全下不算完整加注，已经行动过的玩家只能跟注或弃牌
+		r.Raise(index, a.Amount, a.Amount-r.CurrentBet)
+	}
+	r.Acted[index] = true
+	return to, nil
+}
+
+func (r *Round) Fold(index int) {
+	r.Seats[index].Folded = true
+	r.Ledger.Fold(r.Seats[index].Seat)
+}
+
+// Legal index 在下注结构 bs 下能否弃牌、过牌、跟注、下注和加注，s 为 index 的下注状态
+func (r *Round) Legal(index int, at ActionType, bs BettingStructure, s BettingState) (LegalAction, bool) {
+	seat := r.Seats[index]
+	toCall := r.CurrentBet - seat.Committed
+	all := seat.Committed + seat.Stack
+
+	switch at {
+	case ActionFold:
+		return LegalAction{Type: at}, toCall > 0
+	case ActionCheck:
+		return LegalAction{Type: at}, toCall <= 0
+	case ActionCall:
+		if toCall <= 0 {
+			return LegalAction{}, false
+		}
+		to := min64(r.CurrentBet, all)
+		return LegalAction{Type: at, Min: to, Max: to}, true
+	case ActionBet:
+		if r.CurrentBet > 0 || seat.Stack <= 0 {
+			return LegalAction{}, false
+		}
+		min, max := bs.BetRange(s)
+		return LegalAction{Type: at, Min: min64(min, all), Max: min64(max, all)}, true
+	case ActionRaise:
+		// 已经行动过的玩家只面对不完整的加注时不能再加注
+		if r.CurrentBet <= 0 || all <= r.CurrentBet || r.Acted[index] || !r.OthersCanAct(index) {
+			return LegalAction{}, false
+		}
+		min, max, ok := bs.RaiseRange(s)
+		if !ok {
+			return LegalAction{}, false
+		}
+		return LegalAction{Type: at, Min: min64(min, all), Max: min64(max, all)}, true
+	}
+	return LegalAction{}, false
+}
+
+// State index 计算下注范围时的状态
+func (r *Round) State(index int, street Street, bigBlind int64) BettingState {
+	seat := r.Seats[index]
+	return BettingState{
+		Street:     street,
+		BigBlind:   bigBlind,
+		CurrentBet: r.CurrentBet,
+		LastRaise:  r.LastRaise,
+		Committed:  seat.Committed,
+		Stack:      seat.Stack,
+		Pot:        r.Pot(),
+		Bets:       r.Bets,
+	}
+}
+
+// Complete 所有能行动的玩家都已经行动，并且下注额相同
+func (r *Round) Complete() bool {
+	if r.Remaining() <= 1 {
+		return true
+	}
+	for i, seat := range r.Seats {
+		if !r.CanAct(i) {
+			continue
+		}
+		if !r.Acted[i] || seat.Committed < r.CurrentBet {
+			return false
+		}
+	}
+	return true
+}
+
+// Next index 之后第一个需要行动的座位，没有时返回 -1
+func (r *Round) Next(index int) int {
+	for i := 1; i <= len(r.Seats); i++ {
+		next := (index + i) % len(r.Seats)
+		if r.CanAct(next) {
+			if r.Acted[next] && r.Seats[next].Committed >= r.CurrentBet {
+				continue
+			}
+			return next
+		}
+	}
+	return -1
+}
+
+func (r *Round) CanAct(index int) bool {
+	seat := r.Seats[index]
+	return !seat.Folded && !seat.AllIn
+}
+
+// OthersCanAct 除了 index 之外是否还有能行动的玩家
+func (r *Round) OthersCanAct(index int) bool {
+	for i := range r.Seats {
+		if i != index && r.CanAct(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remaining 没有弃牌的玩家数
+func (r *Round) Remaining() int {
+	var n int
+	for _, seat := range r.Seats {
+		if !seat.Folded {
+			n++
+		}
+	}
+	return n
+}
+
+// Pot 底池中的筹码，包括本轮的下注
+func (r *Round) Pot() int64 {
+	var pot int64
+	for _, seat := range r.Seats {
+		pot += seat.Total
+	}
+	return pot
+}
+
+// Settle 退回没有人跟注的筹码，用 d 分配底池，赢得的筹码加回座位
+// 两个以上的玩家没有弃牌时用 evaluate 计算摊牌的牌型
+func (r *Round) Settle(d pot.Distributor, evaluate func(index int) evaluator.PokerHand) *Result {
+	result := &Result{
+		Payouts:  make(map[int]int64),
+		Showdown: make(map[int]evaluator.PokerHand),
+	}
+	if r.Remaining() > 1 {
+		for i, seat := range r.Seats {
+			if !seat.Folded {
+				result.Showdown[seat.Seat] = evaluate(i)
+			}
+		}
+	}
+
+	if uncalled, ok := r.Ledger.ReturnUncalled(); ok {
+		result.Uncalled = uncalled
+		result.Payouts[uncalled.Seat] += uncalled.Amount
+	}
+	awards, payouts := d.Distribute(r.Ledger.Pots(), result.Showdown)
+	result.Pots = awards
+	for seat, amount := range payouts {
+		result.Payouts[seat] += amount
+	}
+
+	for _, seat := range r.Seats {
+		seat.Stack += result.Payouts[seat.Seat]
+	}
+	return result
+}
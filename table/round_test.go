@@ -0,0 +1,53 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stakes(seats ...int) []*Stake {
+	stakes := make([]*Stake, 0, len(seats))
+	for _, seat := range seats {
+		stakes = append(stakes, &Stake{Player: Player{Seat: seat, Stack: 100}})
+	}
+	return stakes
+}
+
+func TestRoundBlinds(t *testing.T) {
+	r := NewRound(stakes(1, 3, 6))
+	assert.Equal(t, 1, r.Button(3))
+	sb, bb := r.Blinds(3)
+	assert.Equal(t, []int{2, 0}, []int{sb, bb})
+
+	// 庄家的座位没有人时按右手边的座位计算
+	assert.Equal(t, 1, r.Button(4))
+	assert.Equal(t, 2, r.Button(9))
+	assert.Equal(t, 2, r.Button(0))
+
+	// 单挑时庄家下小盲，庄家的座位没有人时不下小盲
+	r = NewRound(stakes(2, 5))
+	sb, bb = r.Blinds(5)
+	assert.Equal(t, []int{1, 0}, []int{sb, bb})
+	sb, bb = r.Blinds(4)
+	assert.Equal(t, []int{1, 0}, []int{sb, bb})
+}
+
+func TestRoundPost(t *testing.T) {
+	r := NewRound(stakes(1, 2))
+	p, ok := r.Post(0, PostAnte, 5)
+	assert.True(t, ok)
+	assert.Equal(t, Post{Seat: 1, Type: PostAnte, Amount: 5, Dead: true}, p)
+	assert.Equal(t, int64(0), r.Seats[0].Committed)
+
+	// 筹码不足时全下
+	p, ok = r.Post(1, PostBigBlind, 200)
+	assert.True(t, ok)
+	assert.Equal(t, Post{Seat: 2, Type: PostBigBlind, Amount: 100}, p)
+	assert.Equal(t, int64(100), r.Seats[1].Committed)
+	assert.True(t, r.Seats[1].AllIn)
+
+	_, ok = r.Post(1, PostStraddle, 20)
+	assert.False(t, ok)
+	assert.Equal(t, int64(105), r.Pot())
+}
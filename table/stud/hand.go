@@ -0,0 +1,396 @@
+package stud
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/pot"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Hand 一局七张梭哈，由 Apply 驱动的确定性状态机
+	Hand struct {
+		cfg         Config
+		em          evaluator.EvaluatorManager
+		seats       []*SeatState // 按座位号排序
+		betting     *table.Round
+		posts       []table.Post
+		recorder    *table.Recorder
+		button      int // 名义上的庄家座位号，决定发牌顺序和平局时的行动顺序
+		dealer      *card.Dealer
+		street      Street
+		board       []card.Card // 牌不够时发的公共牌
+		toAct       int         // 当前行动的座位下标，-1 表示没有
+		bringIn     int         // 需要强制下注的座位下标，下注后为 -1
+		bringInSeat int         // 第三街强制下注的座位号
+		doubled     bool        // 第四街明牌成对时是否已经按 BigBet 下注
+		result      *table.Result
+	}
+)
+
+// NewHand 开始一局牌：下前注，发第三街的牌，由最小明牌的玩家强制下注，deck 需要已经洗好
+// button 为名义上的庄家，从庄家左手边开始发牌
+func NewHand(cfg Config, players []table.Player, button int, deck card.Deck) (*Hand, error) {
+	if cfg.Ante < 0 || cfg.BringIn <= 0 || cfg.SmallBet < cfg.BringIn || cfg.BigBet < cfg.SmallBet {
+		return nil, errors.New("bad limits")
+	}
+	if len(players) < 2 {
+		return nil, errors.New("at least two players")
+	}
+	if len(players) > MaxPlayers {
+		return nil, fmt.Errorf("at most %d players", MaxPlayers)
+	}
+
+	h := &Hand{
+		cfg:     cfg,
+		em:      cfg.Evaluator,
+		button:  button,
+		toAct:   -1,
+		bringIn: -1,
+	}
+	if h.em == nil {
+		h.em = evaluator.NewEvaluatorManager()
+	}
+
+	seatNumbers := make([]int, 0, len(players))
+	for _, p := range players {
+		if p.Stack <= 0 {
+			return nil, fmt.Errorf("seat %d has no chips", p.Seat)
+		}
+		h.seats = append(h.seats, &SeatState{Stake: table.Stake{Player: p}})
+		seatNumbers = append(seatNumbers, p.Seat)
+	}
+	sort.Slice(h.seats, func(i, j int) bool {
+		return h.seats[i].Seat < h.seats[j].Seat
+	})
+	stakes := make([]*table.Stake, len(h.seats))
+	for i, seat := range h.seats {
+		stakes[i] = &seat.Stake
+	}
+	h.betting = table.NewRound(stakes)
+	h.recordStart()
+
+	dealer, err := card.NewDealer(deck, card.StudProcedure, seatNumbers, button)
+	if err != nil {
+		return nil, err
+	}
+	h.dealer = dealer
+
+	if cfg.Ante > 0 {
+		for i := range h.seats {
+			h.postAnte(i)
+		}
+	}
+	if err := h.deal(); err != nil {
+		return nil, err
+	}
+	h.betting.LastRaise = cfg.SmallBet
+
+	h.bringIn = h.lowestUpcard()
+	if h.bringIn < 0 {
+		if err := h.advance(); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}
+	h.bringInSeat = h.seats[h.bringIn].Seat
+	h.toAct = h.bringIn
+	return h, nil
+}
+
+// Apply 执行当前行动玩家的行动
+func (h *Hand) Apply(a table.Action) error {
+	if h.result != nil {
+		return table.ErrHandOver
+	}
+	if h.toAct < 0 || h.seats[h.toAct].Seat != a.Seat {
+		return table.ErrNotYourTurn
+	}
+
+	legal, ok := h.legal(a.Type)
+	if !ok {
+		return fmt.Errorf("%w: %s", table.ErrIllegalAction, a.Type)
+	}
+	seat := h.seats[h.toAct]
+	before := seat.Committed
+	var to int64
+	switch a.Type {
+	case table.ActionFold:
+		h.betting.Fold(h.toAct)
+		h.dealer.Fold(seat.Seat)
+
+	case table.ActionCheck:
+
+	case table.ActionCall:
+		h.betting.Commit(h.toAct, legal.Max-seat.Committed)
+
+	case table.ActionBringIn:
+		h.betting.Commit(h.toAct, legal.Max)
+		h.betting.CurrentBet = legal.Max
+		if legal.Max >= h.cfg.SmallBet { // 强制下注等于小注时视为完整下注
+			h.betting.Bets = 1
+		}
+		h.bringIn = -1
+
+	case table.ActionBet, table.ActionRaise:
+		// 固定限注只能按小注或大注下注，筹码不足时全下
+		if a.Amount != legal.Min && a.Amount != legal.Max {
+			return fmt.Errorf("%w: %s %d not in {%d, %d}", table.ErrIllegalAction, a.Type, a.Amount, legal.Min, legal.Max)
+		}
+		raised := a.Amount - h.betting.CurrentBet
+		if h.betting.Bets == 0 {
+			raised = a.Amount // 下注或把强制下注补足到小注
+		}
+		to = a.Amount
+		// 不足完整加注额的全下不重新开放加注
+		if h.betting.Raise(h.toAct, a.Amount, raised) && h.street == StreetFourth && raised >= h.cfg.BigBet {
+			h.doubled = true
+		}
+		h.bringIn = -1
+	}
+	h.recorder.Action(historyStreets[h.street], &seat.Stake, a.Type, seat.Committed-before, to)
+	h.betting.Acted[h.toAct] = true
+
+	if h.betting.Complete() {
+		return h.advance()
+	}
+	h.toAct = h.betting.Next(h.toAct)
+	return nil
+}
+
+// LegalActions 当前行动玩家可以做的行动
+func (h *Hand) LegalActions() []table.LegalAction {
+	if h.result != nil || h.toAct < 0 {
+		return nil
+	}
+	actions := make([]table.LegalAction, 0, 3)
+	for _, at := range []table.ActionType{table.ActionFold, table.ActionCheck, table.ActionCall, table.ActionBringIn, table.ActionBet, table.ActionRaise} {
+		if legal, ok := h.legal(at); ok {
+			actions = append(actions, legal)
+		}
+	}
+	return actions
+}
+
+// ToAct 当前行动的座位号
+func (h *Hand) ToAct() (int, bool) {
+	if h.result != nil || h.toAct < 0 {
+		return 0, false
+	}
+	return h.seats[h.toAct].Seat, true
+}
+
+func (h *Hand) Street() Street {
+	return h.street
+}
+
+func (h *Hand) Button() int {
+	return h.button
+}
+
+// BringIn 第三街强制下注的座位号
+func (h *Hand) BringIn() int {
+	return h.bringInSeat
+}
+
+// Board 牌不够每人一张时发的公共牌
+func (h *Hand) Board() []card.Card {
+	return append([]card.Card{}, h.board...)
+}
+
+// Seats 所有座位状态的副本
+func (h *Hand) Seats() []SeatState {
+	states := make([]SeatState, 0, len(h.seats))
+	for _, seat := range h.seats {
+		state := *seat
+		state.Cards = append([]card.DealtCard{}, seat.Cards...)
+		states = append(states, state)
+	}
+	return states
+}
+
+// Pot 底池中的筹码，包括本轮的下注
+func (h *Hand) Pot() int64 {
+	return h.betting.Pot()
+}
+
+// Posts 本局的前注
+func (h *Hand) Posts() []table.Post {
+	return append([]table.Post{}, h.posts...)
+}
+
+// Result 牌局结束后返回结果
+func (h *Hand) Result() (*table.Result, bool) {
+	return h.result, h.result != nil
+}
+
+func (h *Hand) legal(at table.ActionType) (table.LegalAction, bool) {
+	seat := h.seats[h.toAct]
+	pending := h.bringIn == h.toAct
+
+	switch at {
+	case table.ActionCheck:
+		if pending {
+			return table.LegalAction{}, false
+		}
+	case table.ActionBringIn:
+		if !pending {
+			return table.LegalAction{}, false
+		}
+		to := min64(h.cfg.BringIn, seat.Committed+seat.Stack)
+		return table.LegalAction{Type: at, Min: to, Max: to}, true
+	}
+	l := h.limit()
+	return h.betting.Legal(h.toAct, at, l, h.betting.State(h.toAct, l.small, h.cfg.SmallBet))
+}
+
+// limit 本轮的下注结构，第五街开始按大注，第四街明牌成对并且还没有人按大注下注时可以选择小注或大注
+func (h *Hand) limit() limit {
+	l := limit{
+		FixedLimit: table.FixedLimit{SmallBet: h.cfg.SmallBet, BigBet: h.cfg.BigBet, Cap: h.cfg.Cap},
+		small:      table.StreetPreflop,
+		big:        table.StreetPreflop,
+	}
+	switch {
+	case h.street >= StreetFifth, h.street == StreetFourth && h.doubled:
+		l.small, l.big = table.StreetTurn, table.StreetTurn
+	case h.street == StreetFourth && h.openPair():
+		l.big = table.StreetTurn
+	}
+	return l
+}
+
+// openPair 是否有没弃牌的玩家明牌成对
+func (h *Hand) openPair() bool {
+	for _, seat := range h.seats {
+		if seat.Folded {
+			continue
+		}
+		ranks := make(map[card.Rank]bool)
+		for _, c := range seat.Up() {
+			if ranks[c.Rank] {
+				return true
+			}
+			ranks[c.Rank] = true
+		}
+	}
+	return false
+}
+
+func (h *Hand) postAnte(index int) {
+	if p, ok := h.betting.Post(index, table.PostAnte, h.cfg.Ante); ok {
+		h.posts = append(h.posts, p)
+		h.recorder.Post(p)
+	}
+}
+
+// deal 发下一街的牌并同步座位的手牌
+func (h *Hand) deal() error {
+	if _, err := h.dealer.Next(); err != nil {
+		return err
+	}
+	for _, seat := range h.seats {
+		seat.Cards = h.dealer.Hand(seat.Seat)
+		h.recorder.Deal(seat.Seat, seat.all())
+	}
+	h.board = h.dealer.Board()
+	return nil
+}
+
+// lowestUpcard 明牌最小的能行动的座位，没有时返回 -1
+func (h *Hand) lowestUpcard() int {
+	lowest := -1
+	var lowestCard card.Card
+	for i, seat := range h.seats {
+		if !h.betting.CanAct(i) {
+			continue
+		}
+		up := seat.Up()
+		if len(up) == 0 {
+			continue
+		}
+		if lowest < 0 || lowerUpcard(up[0], lowestCard) {
+			lowest, lowestCard = i, up[0]
+		}
+	}
+	return lowest
+}
+
+// bestVisible 明牌最大的没弃牌的座位下标，相同时按发牌顺序靠前的座位
+func (h *Hand) bestVisible() int {
+	best := -1
+	var bestHand evaluator.PokerHand
+	for _, seatNumber := range h.dealer.Active() {
+		index := h.indexOf(seatNumber)
+		if h.seats[index].Folded {
+			continue
+		}
+		hand := evaluator.EvaluateVisible(h.em, h.seats[index].Up()...)
+		if best < 0 || hand.Compare(bestHand) == evaluator.ResultHigher {
+			best, bestHand = index, hand
+		}
+	}
+	return best
+}
+
+// advance 结束本轮下注，发下一街的牌，没有人能行动时直接发完所有的牌
+func (h *Hand) advance() error {
+	for {
+		if h.betting.Remaining() <= 1 {
+			h.finish()
+			return nil
+		}
+		h.betting.Reset(int(h.street)+1, 0)
+		h.doubled = false
+		h.bringIn = -1
+
+		if h.street == StreetSeventh {
+			h.finish()
+			return nil
+		}
+		if err := h.deal(); err != nil {
+			return err
+		}
+		h.street++
+		h.betting.LastRaise, _ = h.limit().BetRange(table.BettingState{})
+
+		// 明牌最大的玩家先行动，全下时由下一个玩家行动
+		h.toAct = -1
+		if best := h.bestVisible(); best >= 0 {
+			h.toAct = h.betting.Next(best - 1 + len(h.seats))
+		}
+		if h.toAct >= 0 && h.betting.OthersCanAct(h.toAct) {
+			return nil
+		}
+	}
+}
+
+// finish 分配底池
+func (h *Hand) finish() {
+	h.street = StreetShowdown
+	h.toAct = -1
+	h.result = h.betting.Settle(pot.Distributor{Button: h.button}, func(i int) evaluator.PokerHand {
+		return h.em.Evaluate(append(h.seats[i].all(), h.board...)...)
+	})
+	h.recordResult(h.result)
+}
+
+func (h *Hand) indexOf(seatNumber int) int {
+	for i, seat := range h.seats {
+		if seat.Seat == seatNumber {
+			return i
+		}
+	}
+	return -1
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,154 @@
+package stud
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/openpoker-dev/contrib/table/tabletest"
+	"github.com/stretchr/testify/assert"
+)
+
+var limits = Config{Ante: 1, BringIn: 2, SmallBet: 5, BigBet: 10}
+
+func TestBringIn(t *testing.T) {
+	// 从座位 1 开始发牌，两轮暗牌后发明牌，2h 和 2c 点数相同，红桃排在梅花前面
+	deck := tabletest.PresetDeck(t, "Ah Kh Qh Ad Kd Qd 2c 2h 9s "+
+		"Jc 3d 3h Ts "+
+		"4c 5d 5s "+
+		"6c 7d 8d "+
+		"6h Jd Js")
+	h, err := NewHand(limits, []table.Player{
+		{Seat: 1, Name: "alice", Stack: 100},
+		{Seat: 2, Name: "bob", Stack: 100},
+		{Seat: 3, Name: "carol", Stack: 100},
+	}, 3, deck)
+	assert.Nil(t, err)
+	assert.Equal(t, StreetThird, h.Street())
+	assert.Equal(t, int64(3), h.Pot())
+	assert.Equal(t, 2, h.BringIn())
+	assert.Equal(t, []card.Card{card.NewCard("2h")}, h.Seats()[1].Up())
+	assert.Equal(t, []card.Card{card.NewCard("Kh"), card.NewCard("Kd")}, h.Seats()[1].Down())
+
+	seat, _ := h.ToAct()
+	assert.Equal(t, 2, seat)
+	assert.Equal(t, []table.LegalAction{
+		{Type: table.ActionBringIn, Min: 2, Max: 2},
+		{Type: table.ActionBet, Min: 5, Max: 5},
+	}, h.LegalActions())
+
+	tabletest.Apply(t, h, table.Action{Seat: 2, Type: table.ActionBringIn})
+	assert.Equal(t, []table.LegalAction{
+		{Type: table.ActionFold},
+		{Type: table.ActionCall, Min: 2, Max: 2},
+		{Type: table.ActionRaise, Min: 5, Max: 5},
+	}, h.LegalActions())
+
+	// 补足到小注算一次完整下注，之后按小注加注
+	tabletest.Apply(t, h, table.Action{Seat: 3, Type: table.ActionRaise, Amount: 5})
+	raise, _ := tabletest.LegalOf(h, table.ActionRaise)
+	assert.Equal(t, int64(10), raise.Min)
+	tabletest.Apply(t, h,
+		table.Action{Seat: 1, Type: table.ActionCall},
+		table.Action{Seat: 2, Type: table.ActionCall},
+	)
+
+	// 第四街明牌 9s Ts 最大，先行动
+	assert.Equal(t, StreetFourth, h.Street())
+	seat, _ = h.ToAct()
+	assert.Equal(t, 3, seat)
+	assert.Equal(t, []table.LegalAction{
+		{Type: table.ActionCheck},
+		{Type: table.ActionBet, Min: 5, Max: 5},
+	}, h.LegalActions())
+	tabletest.Apply(t, h,
+		table.Action{Seat: 3, Type: table.ActionCheck},
+		table.Action{Seat: 1, Type: table.ActionCheck},
+		table.Action{Seat: 2, Type: table.ActionBet, Amount: 5},
+		table.Action{Seat: 3, Type: table.ActionCall},
+		table.Action{Seat: 1, Type: table.ActionFold},
+	)
+
+	assert.Equal(t, StreetFifth, h.Street())
+	bet, _ := tabletest.LegalOf(h, table.ActionBet)
+	assert.Equal(t, int64(10), bet.Min)
+	for h.Street() != StreetShowdown {
+		seat, _ := h.ToAct()
+		tabletest.Apply(t, h, table.Action{Seat: seat, Type: table.ActionCheck})
+	}
+
+	result, ok := h.Result()
+	assert.True(t, ok)
+	assert.Equal(t, evaluator.RankStraight, result.Showdown[3].Rank)
+	assert.Equal(t, evaluator.RankOnePair, result.Showdown[2].Rank)
+	assert.Equal(t, map[int]int64{3: 28}, result.Payouts)
+	assert.Equal(t, card.DealtCard{Card: card.NewCard("Js")}, h.Seats()[2].Cards[6])
+}
+
+func TestOpenPairOnFourthStreet(t *testing.T) {
+	deck := tabletest.PresetDeck(t, "Ah Kh Ad Kd 9c 3s 2c 9d 4s")
+	h, err := NewHand(limits, []table.Player{
+		{Seat: 1, Stack: 100},
+		{Seat: 2, Stack: 100},
+	}, 2, deck)
+	assert.Nil(t, err)
+	tabletest.Apply(t, h,
+		table.Action{Seat: 2, Type: table.ActionBringIn},
+		table.Action{Seat: 1, Type: table.ActionCall},
+	)
+
+	// 明牌成对的玩家先行动，可以按大注下注
+	assert.Equal(t, StreetFourth, h.Street())
+	seat, _ := h.ToAct()
+	assert.Equal(t, 1, seat)
+	bet, _ := tabletest.LegalOf(h, table.ActionBet)
+	assert.Equal(t, table.LegalAction{Type: table.ActionBet, Min: 5, Max: 10}, bet)
+	assert.True(t, errors.Is(h.Apply(table.Action{Seat: 1, Type: table.ActionBet, Amount: 7}), table.ErrIllegalAction))
+
+	tabletest.Apply(t, h, table.Action{Seat: 1, Type: table.ActionBet, Amount: 10})
+	raise, _ := tabletest.LegalOf(h, table.ActionRaise)
+	assert.Equal(t, table.LegalAction{Type: table.ActionRaise, Min: 20, Max: 20}, raise)
+}
+
+func TestEightPlayersRunOutOfCards(t *testing.T) {
+	players := make([]table.Player, 0, MaxPlayers)
+	for seat := 1; seat <= MaxPlayers; seat++ {
+		players = append(players, table.Player{Seat: seat, Stack: 100})
+	}
+	deck := card.NewFiftyTwoCardsDeck()
+	deck.Shuffle()
+	h, err := NewHand(limits, players, 1, deck)
+	assert.Nil(t, err)
+
+	for h.Street() != StreetShowdown {
+		seat, _ := h.ToAct()
+		action := table.Action{Seat: seat, Type: table.ActionCheck}
+		for _, at := range []table.ActionType{table.ActionBringIn, table.ActionCall} {
+			if _, ok := tabletest.LegalOf(h, at); ok {
+				action.Type = at
+			}
+		}
+		tabletest.Apply(t, h, action)
+	}
+
+	// 第七街只剩一张牌，作为公共牌
+	assert.Len(t, h.Board(), 1)
+	for _, seat := range h.Seats() {
+		assert.Len(t, seat.Cards, 6)
+	}
+	result, _ := h.Result()
+	assert.Len(t, result.Showdown, MaxPlayers)
+	assert.Equal(t, int64(24), h.Pot())
+
+	_, err = NewHand(limits, append(players, table.Player{Seat: 9, Stack: 100}), 1, deck)
+	assert.NotNil(t, err)
+}
+
+func TestLowerUpcard(t *testing.T) {
+	assert.True(t, lowerUpcard(card.NewCard("2s"), card.NewCard("3h")))
+	assert.True(t, lowerUpcard(card.NewCard("Kc"), card.NewCard("Ah")))
+	assert.True(t, lowerUpcard(card.NewCard("2d"), card.NewCard("2s")))
+	assert.False(t, lowerUpcard(card.NewCard("2c"), card.NewCard("2h")))
+}
@@ -0,0 +1,37 @@
+package stud
+
+import (
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+// History 牌局历史的副本，牌局进行中也可以调用
+func (h *Hand) History() *history.Hand {
+	return h.recorder.History(h.Board())
+}
+
+// Actions 本局到目前为止的行动的副本
+func (h *Hand) Actions() []history.Action {
+	return h.recorder.Actions()
+}
+
+// recordStart 记录开始时的配置和玩家，固定限注的小注和大注记为 SmallBlind 和 BigBlind
+func (h *Hand) recordStart() {
+	h.recorder = table.NewRecorder(history.Hand{
+		ID:         h.cfg.ID,
+		Table:      h.cfg.Table,
+		Game:       history.GameStud,
+		Limit:      history.LimitFixed,
+		SmallBlind: h.cfg.SmallBet,
+		BigBlind:   h.cfg.BigBet,
+		Ante:       h.cfg.Ante,
+		Button:     h.button,
+	}, h.betting.Seats, h.cfg.Clock)
+}
+
+func (h *Hand) recordResult(result *table.Result) {
+	h.recorder.Result(result, h.Board(), func(seat int) []card.Card {
+		return h.seats[h.indexOf(seat)].all()
+	})
+}
@@ -0,0 +1,89 @@
+package stud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/card/cardtest"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/openpoker-dev/contrib/table/tabletest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	cfg := limits
+	cfg.ID = "7"
+	cfg.Clock = func() time.Time { return start }
+
+	deck := tabletest.PresetDeck(t, "Ah Kh Ad Kd 9s 2h")
+	h, err := NewHand(cfg, []table.Player{
+		{Seat: 1, Name: "alice", Stack: 100},
+		{Seat: 2, Name: "bob", Stack: 100},
+	}, 2, deck)
+	assert.Nil(t, err)
+	tabletest.Apply(t, h,
+		table.Action{Seat: 2, Type: table.ActionBringIn},
+		table.Action{Seat: 1, Type: table.ActionRaise, Amount: 5},
+		table.Action{Seat: 2, Type: table.ActionFold},
+	)
+
+	record := h.History()
+	assert.Equal(t, "7", record.ID)
+	assert.Equal(t, history.GameStud, record.Game)
+	assert.Equal(t, history.LimitFixed, record.Limit)
+	assert.Equal(t, int64(1), record.Ante)
+	assert.Equal(t, []history.Player{
+		{Seat: 1, Name: "alice", Stack: 100, Cards: []card.Card{card.NewCard("Ah"), card.NewCard("Ad"), card.NewCard("9s")}},
+		{Seat: 2, Name: "bob", Stack: 100, Cards: []card.Card{card.NewCard("Kh"), card.NewCard("Kd"), card.NewCard("2h")}},
+	}, record.Players)
+	assert.Equal(t, []history.Post{
+		{Seat: 1, Type: history.PostAnte, Amount: 1, Dead: true},
+		{Seat: 2, Type: history.PostAnte, Amount: 1, Dead: true},
+	}, record.Posts)
+	assert.Equal(t, []history.Action{
+		{Street: history.StreetThird, Seat: 2, Type: history.ActionBringIn, Amount: 2, At: start},
+		{Street: history.StreetThird, Seat: 1, Type: history.ActionRaise, Amount: 5, To: 5, At: start},
+		{Street: history.StreetThird, Seat: 2, Type: history.ActionFold, At: start},
+	}, record.Actions)
+	assert.Equal(t, &history.Uncalled{Seat: 1, Amount: 3}, record.Uncalled)
+	assert.Equal(t, []history.Pot{
+		{Amount: 6, Eligible: []int{1}, Winners: []history.Winner{{Seat: 1, Amount: 6}}},
+	}, record.Pots)
+	assert.Nil(t, record.Validate())
+}
+
+func TestHistoryShowdown(t *testing.T) {
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	cfg := limits
+	cfg.Clock = func() time.Time { return start }
+
+	// 座位 1 跟注强制下注后全下，直接发完所有的牌
+	deck := tabletest.PresetDeck(t, "Ah Kh Ad Kd 9s 2h 2c 3c 4c 2d 5d 6d 2s 7h 8h Ts Js Qs")
+	h, err := NewHand(cfg, []table.Player{
+		{Seat: 1, Name: "alice", Stack: 3},
+		{Seat: 2, Name: "bob", Stack: 100},
+	}, 2, deck)
+	assert.Nil(t, err)
+	tabletest.Apply(t, h,
+		table.Action{Seat: 2, Type: table.ActionBringIn},
+		table.Action{Seat: 1, Type: table.ActionCall},
+	)
+	assert.Equal(t, StreetShowdown, h.Street())
+
+	record := h.History()
+	assert.Len(t, record.Showdown, 2)
+	assert.Equal(t, cardtest.Cards(t, "Ah Ad 9s 3c 5d 7h Js"), record.Showdown[0].Cards)
+	assert.Equal(t, []history.Pot{
+		{Amount: 6, Eligible: []int{1, 2}, Winners: []history.Winner{{Seat: 1, Amount: 6}}},
+	}, record.Pots)
+	assert.Nil(t, record.Validate())
+
+	data, err := history.Marshal(record)
+	assert.Nil(t, err)
+	decoded, err := history.Unmarshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, record, decoded)
+}
@@ -0,0 +1,137 @@
+// Package stud 七张梭哈牌局的状态机
+package stud
+
+import (
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/history"
+	"github.com/openpoker-dev/contrib/table"
+)
+
+type (
+	// Config 七张梭哈的配置，固定限注
+	// 第三街和第四街按 SmallBet 下注，第四街有人明牌成对时可以选择按 BigBet 下注，之后按 BigBet 下注
+	Config struct {
+		ID        string // 牌局编号，记录在牌局历史中
+		Table     string // 牌桌名，记录在牌局历史中
+		Ante      int64
+		BringIn   int64 // 最小明牌的强制下注，不超过 SmallBet
+		SmallBet  int64
+		BigBet    int64
+		Cap       int                        // 每轮下注和加注的次数上限，为 0 时使用 4
+		Evaluator evaluator.EvaluatorManager // 为空时使用 evaluator.NewEvaluatorManager
+		Clock     func() time.Time           // 牌局历史的时间戳，为空时使用 time.Now
+	}
+
+	// Street 下注轮
+	Street int
+
+	// SeatState 座位在牌局中的状态
+	SeatState struct {
+		table.Stake
+		Cards []card.DealtCard // 按发牌顺序，包括明牌和暗牌
+	}
+
+	// limit 七张梭哈的固定限注，按 small 和 big 两个下注轮的大小计算最小和最大的下注额
+	// 第三街和第四街对应 table.FixedLimit 的小注，之后对应大注
+	limit struct {
+		table.FixedLimit
+		small, big table.Street
+	}
+)
+
+const (
+	StreetThird    Street = iota // 第三街，两张暗牌一张明牌
+	StreetFourth                 // 第四街
+	StreetFifth                  // 第五街
+	StreetSixth                  // 第六街
+	StreetSeventh                // 第七街，一张暗牌
+	StreetShowdown               // 牌局结束
+)
+
+// MaxPlayers 一副牌最多支持八个玩家，第七街的牌不够时发一张公共牌
+const MaxPlayers = 8
+
+var (
+	streetDescriptions = map[Street]string{
+		StreetThird:    "3rd Street",
+		StreetFourth:   "4th Street",
+		StreetFifth:    "5th Street",
+		StreetSixth:    "6th Street",
+		StreetSeventh:  "7th Street",
+		StreetShowdown: "Showdown",
+	}
+
+	historyStreets = map[Street]history.Street{
+		StreetThird:    history.StreetThird,
+		StreetFourth:   history.StreetFourth,
+		StreetFifth:    history.StreetFifth,
+		StreetSixth:    history.StreetSixth,
+		StreetSeventh:  history.StreetSeventh,
+		StreetShowdown: history.StreetShowdown,
+	}
+)
+
+func (s Street) String() string {
+	return streetDescriptions[s]
+}
+
+// Up 明牌
+func (s SeatState) Up() []card.Card {
+	return s.filter(true)
+}
+
+// Down 暗牌
+func (s SeatState) Down() []card.Card {
+	return s.filter(false)
+}
+
+// all 按发牌顺序的所有牌
+func (s SeatState) all() []card.Card {
+	cards := make([]card.Card, 0, len(s.Cards))
+	for _, dc := range s.Cards {
+		cards = append(cards, dc.Card)
+	}
+	return cards
+}
+
+func (s SeatState) filter(up bool) []card.Card {
+	cards := make([]card.Card, 0, len(s.Cards))
+	for _, dc := range s.Cards {
+		if dc.Up == up {
+			cards = append(cards, dc.Card)
+		}
+	}
+	return cards
+}
+
+func (l limit) BetRange(s table.BettingState) (int64, int64) {
+	s.Street = l.small
+	min, _ := l.FixedLimit.BetRange(s)
+	s.Street = l.big
+	_, max := l.FixedLimit.BetRange(s)
+	return min, max
+}
+
+// RaiseRange 还没有完整下注时把强制下注补足到小注，按下注计算
+func (l limit) RaiseRange(s table.BettingState) (int64, int64, bool) {
+	if s.Bets == 0 {
+		min, max := l.BetRange(s)
+		return min, max, true
+	}
+	s.Street = l.small
+	min, _, ok := l.FixedLimit.RaiseRange(s)
+	s.Street = l.big
+	_, max, _ := l.FixedLimit.RaiseRange(s)
+	return min, max, ok
+}
+
+// lowerUpcard a 是否比 b 小，A 最大，点数相同时按 card.Suit 的顺序比较
+func lowerUpcard(a, b card.Card) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.Suit < b.Suit
+}
@@ -0,0 +1,126 @@
+// Package table 德州扑克牌局的状态机
+package table
+
+import (
+	"errors"
+	"time"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/evaluator"
+	"github.com/openpoker-dev/contrib/pot"
+)
+
+type (
+	// Player 入座的玩家
+	Player struct {
+		Seat   int
+		Name   string
+		Stack  int64
+		Missed Missed // 需要补交的盲注
+	}
+
+	// Config 牌局配置
+	Config struct {
+		ID         string // 牌局编号，记录在牌局历史中
+		Table      string // 牌桌名，记录在牌局历史中
+		Variant    Variant
+		SmallBlind int64
+		BigBlind   int64
+		Forced     ForcedBets
+		Structure  BettingStructure           // 为空时使用 NoLimit
+		Evaluator  evaluator.EvaluatorManager // 为空时使用 evaluator.NewEvaluatorManager
+		Clock      func() time.Time           // 牌局历史的时间戳，为空时使用 time.Now
+	}
+
+	// Variant 玩法
+	Variant int
+
+	// Street 下注轮
+	Street int
+
+	// ActionType 行动类型
+	ActionType int
+
+	// Action 玩家的行动，Bet 和 Raise 的 Amount 为本轮下注后的总额
+	Action struct {
+		Seat   int
+		Type   ActionType
+		Amount int64
+	}
+
+	// LegalAction 当前可以做的行动，Min 和 Max 为本轮下注后总额的范围
+	LegalAction struct {
+		Type ActionType
+		Min  int64
+		Max  int64
+	}
+
+	// SeatState 座位在牌局中的状态
+	SeatState struct {
+		Stake
+		Hole []card.Card
+	}
+
+	// Result 牌局结果
+	Result struct {
+		Pots     []pot.Award
+		Uncalled pot.Uncalled                // 没有人跟注而退回的筹码
+		Payouts  map[int]int64               // 座位赢得的筹码，包括退回的筹码
+		Showdown map[int]evaluator.PokerHand // 摊牌座位的最大牌型
+	}
+)
+
+const (
+	VariantHoldem Variant = iota // 德州扑克
+	VariantOmaha                 // 奥马哈，摊牌时必须使用两张手牌和三张公共牌
+)
+
+const (
+	StreetPreflop  Street = iota // 翻牌前
+	StreetFlop                   // 翻牌
+	StreetTurn                   // 转牌
+	StreetRiver                  // 河牌
+	StreetShowdown               // 牌局结束
+)
+
+const (
+	ActionFold    ActionType = iota // 弃牌
+	ActionCheck                     // 过牌
+	ActionCall                      // 跟注
+	ActionBet                       // 下注
+	ActionRaise                     // 加注
+	ActionBringIn                   // 梭哈第三街最小明牌的强制下注
+)
+
+var (
+	streetDescriptions = map[Street]string{
+		StreetPreflop:  "Preflop",
+		StreetFlop:     "Flop",
+		StreetTurn:     "Turn",
+		StreetRiver:    "River",
+		StreetShowdown: "Showdown",
+	}
+
+	actionDescriptions = map[ActionType]string{
+		ActionFold:    "fold",
+		ActionCheck:   "check",
+		ActionCall:    "call",
+		ActionBet:     "bet",
+		ActionRaise:   "raise",
+		ActionBringIn: "bring-in",
+	}
+)
+
+var (
+	ErrHandOver      = errors.New("hand is over")
+	ErrNotYourTurn   = errors.New("not your turn")
+	ErrIllegalAction = errors.New("illegal action")
+)
+
+func (s Street) String() string {
+	return streetDescriptions[s]
+}
+
+func (at ActionType) String() string {
+	return actionDescriptions[at]
+}
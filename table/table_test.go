@@ -0,0 +1,12 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescriptions(t *testing.T) {
+	assert.Equal(t, "Flop", StreetFlop.String())
+	assert.Equal(t, "raise", ActionRaise.String())
+}
@@ -0,0 +1,44 @@
+// Package tabletest 测试各种玩法的牌局时共用的工具
+package tabletest
+
+import (
+	"testing"
+
+	"github.com/openpoker-dev/contrib/card"
+	"github.com/openpoker-dev/contrib/table"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// Hand 各种玩法的牌局都支持的下注操作
+	Hand interface {
+		Apply(table.Action) error
+		LegalActions() []table.LegalAction
+	}
+)
+
+// PresetDeck 按 s 中的顺序发牌的牌堆
+func PresetDeck(t *testing.T, s string) card.Deck {
+	cards, err := card.ParseCards(s)
+	assert.Nil(t, err)
+	deck, err := card.NewPresetDeck(cards...)
+	assert.Nil(t, err)
+	return deck
+}
+
+// Apply 依次执行 actions，每个行动都必须成功
+func Apply(t *testing.T, h Hand, actions ...table.Action) {
+	for _, a := range actions {
+		assert.Nil(t, h.Apply(a), "%+v", a)
+	}
+}
+
+// LegalOf 当前行动玩家可以做的 at 类型的行动
+func LegalOf(h Hand, at table.ActionType) (table.LegalAction, bool) {
+	for _, legal := range h.LegalActions() {
+		if legal.Type == at {
+			return legal, true
+		}
+	}
+	return table.LegalAction{}, false
+}